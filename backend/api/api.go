@@ -0,0 +1,140 @@
+// backend/api/api.go
+
+// Package api holds the OpenAPI 3 description of the HTTP server in
+// cmd/server. The document is embedded so the server can publish it at
+// /api/openapi.json and check itself against it; pkg/client is generated
+// from the same file.
+package api
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// V1Prefix is where the documented routes live. Every /api/v1 route is also
+// served under LegacyPrefix for clients written before versioning.
+const (
+	V1Prefix     = "/api/v1"
+	LegacyPrefix = "/api"
+)
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+var (
+	loadOnce sync.Once
+	doc      *openapi3.T
+	docJSON  []byte
+	loadErr  error
+)
+
+// Spec parses and validates the embedded document. The result is cached.
+func Spec() (*openapi3.T, error) {
+	loadOnce.Do(func() {
+		loader := openapi3.NewLoader()
+		doc, loadErr = loader.LoadFromData(specYAML)
+		if loadErr != nil {
+			return
+		}
+		if loadErr = doc.Validate(context.Background()); loadErr != nil {
+			return
+		}
+		docJSON, loadErr = json.Marshal(doc)
+	})
+	return doc, loadErr
+}
+
+// JSON returns the document rendered as JSON.
+func JSON() ([]byte, error) {
+	if _, err := Spec(); err != nil {
+		return nil, err
+	}
+	return docJSON, nil
+}
+
+// YAML returns the document exactly as written.
+func YAML() []byte {
+	return specYAML
+}
+
+// ─── Route coverage ───────────────────────────────────────────────────────────
+
+var pathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// CheckRoutes reports drift between the document and a mux: spec paths the
+// mux would answer with its 404 handler, and registered patterns that no spec
+// path reaches. patterns are the strings passed to mux.HandleFunc.
+func CheckRoutes(mux *http.ServeMux, patterns []string) ([]string, error) {
+	spec, err := Spec()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	reached := make(map[string]bool)
+	for _, path := range spec.Paths.InMatchingOrder() {
+		item := spec.Paths.Value(path)
+		concrete := pathParam.ReplaceAllString(path, "1")
+		for method := range item.Operations() {
+			req := httptest.NewRequest(method, concrete, nil)
+			_, pattern := mux.Handler(req)
+			if pattern == "" {
+				problems = append(problems, fmt.Sprintf("%s %s is documented but not routed", method, path))
+				continue
+			}
+			reached[pattern] = true
+		}
+	}
+	for _, p := range patterns {
+		if !reached[p] {
+			problems = append(problems, fmt.Sprintf("%s is routed but not documented", p))
+		}
+	}
+	sort.Strings(problems)
+	return problems, nil
+}
+
+// ─── Response validation ──────────────────────────────────────────────────────
+
+// responseRecorder buffers the body next to the real writer so it can be
+// validated once the handler returns.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+	stream bool
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+		r.stream = r.Header().Get("Content-Type") == "text/event-stream"
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+	if !r.stream {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
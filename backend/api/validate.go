@@ -0,0 +1,97 @@
+// backend/api/validate.go
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// ValidateResponses wraps next so every response is checked against the
+// document after it has been sent. Mismatches — an undocumented route or
+// status, or a body that does not fit the schema — go to report; the
+// client still gets the handler's response unchanged. Event streams and
+// CORS preflights are not checked.
+//
+// It buffers every JSON body, so it is meant for development and CI runs
+// (OPENAPI_VALIDATE=true), not production traffic.
+func ValidateResponses(next http.Handler, report func(r *http.Request, err error)) (http.Handler, error) {
+	spec, err := Spec()
+	if err != nil {
+		return nil, err
+	}
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, err
+	}
+	opts := &openapi3filter.Options{IncludeResponseStatus: true}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		route, params, routeErr := router.FindRoute(versioned(r))
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.stream {
+			return
+		}
+
+		if routeErr != nil {
+			// Unknown paths and methods are expected to 404 or 405;
+			// anything else is drift.
+			if rec.status != http.StatusNotFound && rec.status != http.StatusMethodNotAllowed {
+				report(r, fmt.Errorf("%s %s answered %d but is not documented: %w",
+					r.Method, r.URL.Path, rec.status, routeErr))
+			}
+			return
+		}
+		if err := validate(r, route, params, rec, opts); err != nil {
+			report(r, err)
+		}
+	}), nil
+}
+
+// versioned maps a legacy /api/... request onto its documented /api/v1 path.
+func versioned(r *http.Request) *http.Request {
+	p := r.URL.Path
+	if strings.HasPrefix(p, V1Prefix+"/") || !strings.HasPrefix(p, LegacyPrefix+"/") {
+		return r
+	}
+	v := r.Clone(r.Context())
+	v.URL.Path = V1Prefix + strings.TrimPrefix(p, LegacyPrefix)
+	v.URL.RawPath = ""
+	return v
+}
+
+func validate(r *http.Request, route *routers.Route, params map[string]string,
+	rec *responseRecorder, opts *openapi3filter.Options) error {
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: params,
+			Route:      route,
+			Options:    opts,
+		},
+		Status:  status,
+		Header:  rec.Header(),
+		Body:    io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+		Options: opts,
+	}
+	if err := openapi3filter.ValidateResponse(r.Context(), input); err != nil {
+		return fmt.Errorf("%s %s → %d does not match the spec: %w", r.Method, r.URL.Path, status, err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Command create-admin makes the first admin, who can then hand out roles
+// through PUT /api/v1/admin/users/{id}/role.
+//
+//	go run ./cmd/create-admin -email ada@example.com -name "Ada Lovelace"
+//
+// An existing user with that email is promoted and keeps their password.
+// Otherwise a user is created with -password or, without one, a random
+// password that is printed once. It connects like the API server, through
+// DATABASE_URL or DB_*, and expects the API server to have created the
+// users table.
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+
+	"event-scraper/internal/config"
+	"event-scraper/internal/roles"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the admin (required)")
+	name := flag.String("name", "", "full name, when creating the user")
+	password := flag.String("password", "", "password, when creating the user (default: random)")
+	flag.Parse()
+
+	*email = strings.TrimSpace(strings.ToLower(*email))
+	if *email == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	_ = godotenv.Load()
+	connStr := os.Getenv("DATABASE_URL")
+	if connStr == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
+		connStr = cfg.Database.ConnectionString()
+	}
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		log.Fatalf("Failed to open DB: %v", err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to connect to DB: %v", err)
+	}
+	if err := roles.EnsureSchema(db); err != nil {
+		log.Fatalf("%v (start the API server once to create the users table)", err)
+	}
+
+	var userID string
+	err = db.QueryRow(`SELECT id::text FROM users WHERE email = $1`, *email).Scan(&userID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		userID, err = createUser(db, *email, *name, *password)
+		if err != nil {
+			log.Fatalf("Failed to create user: %v", err)
+		}
+	case err != nil:
+		log.Fatalf("Failed to look up user: %v", err)
+	}
+
+	if err := roles.Set(db, userID, roles.Admin); err != nil {
+		log.Fatalf("Failed to make %s an admin: %v", *email, err)
+	}
+	fmt.Printf("✅ %s is an admin\n", *email)
+}
+
+func createUser(db *sql.DB, email, name, password string) (string, error) {
+	if name == "" {
+		name = strings.SplitN(email, "@", 2)[0]
+	}
+	if password == "" {
+		b := make([]byte, 12)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		password = base64.RawURLEncoding.EncodeToString(b)
+		fmt.Printf("🔑 Generated password for %s: %s\n", email, password)
+	} else if len(password) < 6 {
+		return "", errors.New("password must be at least 6 characters")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	err = db.QueryRow(`
+		INSERT INTO users (full_name, email, password_hash) VALUES ($1, $2, $3)
+		RETURNING id::text
+	`, name, email, string(hash)).Scan(&id)
+	return id, err
+}
@@ -0,0 +1,305 @@
+// Command mock-oidc is a minimal OpenID Connect provider for trying single
+// sign-on locally. It implements discovery, the authorization code flow
+// with PKCE (S256 only), a JWKS endpoint and RS256 ID tokens, and trusts
+// whoever fills in its sign-in form. Never expose it.
+//
+//	go run ./cmd/mock-oidc
+//
+// and start the API server with
+//
+//	OIDC_ISSUER=http://localhost:9400 OIDC_CLIENT_ID=event-scraper OIDC_CLIENT_SECRET=secret
+//
+// MOCK_OIDC_EMAIL skips the form and signs everyone in as that address,
+// for scripted runs.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "mock-1"
+
+type authRequest struct {
+	ClientID      string
+	RedirectURI   string
+	State         string
+	Nonce         string
+	CodeChallenge string
+}
+
+type grant struct {
+	authRequest
+	Email    string
+	Name     string
+	Verified bool
+	Expires  time.Time
+}
+
+type provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	autoEmail    string
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]grant
+}
+
+func main() {
+	addr := getEnv("MOCK_OIDC_ADDR", ":9400")
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("Failed to generate signing key: %v", err)
+	}
+	p := &provider{
+		issuer:       strings.TrimRight(getEnv("MOCK_OIDC_ISSUER", "http://localhost"+addr), "/"),
+		clientID:     getEnv("MOCK_OIDC_CLIENT_ID", "event-scraper"),
+		clientSecret: getEnv("MOCK_OIDC_CLIENT_SECRET", "secret"),
+		autoEmail:    os.Getenv("MOCK_OIDC_EMAIL"),
+		key:          key,
+		codes:        make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.handleDiscovery)
+	mux.HandleFunc("GET /authorize", p.handleAuthorize)
+	mux.HandleFunc("POST /authorize", p.handleApprove)
+	mux.HandleFunc("POST /token", p.handleToken)
+	mux.HandleFunc("GET /jwks", p.handleJWKS)
+
+	log.Printf("🔑 Mock OIDC provider at %s (client %s)", p.issuer, p.clientID)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
+func (p *provider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, 200, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+	})
+}
+
+var form = template.Must(template.New("form").Parse(`<!doctype html>
+<title>Mock OIDC sign-in</title>
+<body style="font-family: sans-serif; max-width: 24rem; margin: 4rem auto">
+<h1>Mock OIDC</h1>
+<p>Sign in to <b>{{.ClientID}}</b> as anyone.</p>
+<form method="post">
+  {{range $k, $v := .Hidden}}<input type="hidden" name="{{$k}}" value="{{$v}}">{{end}}
+  <p><label>Email<br><input name="email" type="email" required autofocus></label></p>
+  <p><label>Name<br><input name="name"></label></p>
+  <p><label><input name="email_verified" type="checkbox" checked> Email verified</label></p>
+  <button>Sign in</button>
+</form>`))
+
+// handleAuthorize checks the authorization request and shows the sign-in
+// form, or signs in as MOCK_OIDC_EMAIL straight away.
+func (p *provider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req, msg := p.checkAuthRequest(q)
+	if msg != "" {
+		http.Error(w, msg, 400)
+		return
+	}
+	if p.autoEmail != "" {
+		p.redirectWithCode(w, r, req, p.autoEmail, "", true)
+		return
+	}
+	hidden := map[string]string{}
+	for _, k := range []string{"client_id", "redirect_uri", "state", "nonce", "code_challenge", "code_challenge_method", "response_type"} {
+		hidden[k] = q.Get(k)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = form.Execute(w, map[string]interface{}{"ClientID": req.ClientID, "Hidden": hidden})
+}
+
+func (p *provider) handleApprove(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad form", 400)
+		return
+	}
+	req, msg := p.checkAuthRequest(r.PostForm)
+	if msg != "" {
+		http.Error(w, msg, 400)
+		return
+	}
+	email := strings.TrimSpace(r.PostForm.Get("email"))
+	if email == "" {
+		http.Error(w, "email is required", 400)
+		return
+	}
+	p.redirectWithCode(w, r, req, email, r.PostForm.Get("name"), r.PostForm.Get("email_verified") != "")
+}
+
+func (p *provider) checkAuthRequest(q url.Values) (authRequest, string) {
+	req := authRequest{
+		ClientID:      q.Get("client_id"),
+		RedirectURI:   q.Get("redirect_uri"),
+		State:         q.Get("state"),
+		Nonce:         q.Get("nonce"),
+		CodeChallenge: q.Get("code_challenge"),
+	}
+	switch {
+	case q.Get("response_type") != "code":
+		return req, "response_type must be code"
+	case req.ClientID != p.clientID:
+		return req, "unknown client_id"
+	case req.RedirectURI == "":
+		return req, "redirect_uri is required"
+	case req.CodeChallenge == "" || q.Get("code_challenge_method") != "S256":
+		return req, "PKCE with code_challenge_method=S256 is required"
+	}
+	return req, ""
+}
+
+func (p *provider) redirectWithCode(w http.ResponseWriter, r *http.Request, req authRequest, email, name string, verified bool) {
+	code := randomString()
+	p.mu.Lock()
+	p.codes[code] = grant{authRequest: req, Email: email, Name: name, Verified: verified, Expires: time.Now().Add(time.Minute)}
+	p.mu.Unlock()
+
+	target, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		http.Error(w, "bad redirect_uri", 400)
+		return
+	}
+	q := target.Query()
+	q.Set("code", code)
+	q.Set("state", req.State)
+	target.RawQuery = q.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (p *provider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request", "bad form")
+		return
+	}
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != p.clientID || subtle.ConstantTimeCompare([]byte(secret), []byte(p.clientSecret)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="mock-oidc"`)
+		writeJSON(w, 401, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "only authorization_code")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	g, found := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+	switch {
+	case !found || time.Now().After(g.Expires):
+		tokenError(w, "invalid_grant", "unknown or expired code")
+		return
+	case g.RedirectURI != r.PostForm.Get("redirect_uri"):
+		tokenError(w, "invalid_grant", "redirect_uri does not match")
+		return
+	case challenge(r.PostForm.Get("code_verifier")) != g.CodeChallenge:
+		tokenError(w, "invalid_grant", "code_verifier does not match code_challenge")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            "mock|" + strings.ToLower(g.Email),
+		"aud":            g.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"email":          g.Email,
+		"email_verified": g.Verified,
+	}
+	if g.Nonce != "" {
+		claims["nonce"] = g.Nonce
+	}
+	if g.Name != "" {
+		claims["name"] = g.Name
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+	idToken, err := token.SignedString(p.key)
+	if err != nil {
+		tokenError(w, "server_error", err.Error())
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, 200, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (p *provider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, 200, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func challenge(verifier string) string {
+	h := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func tokenError(w http.ResponseWriter, code, desc string) {
+	writeJSON(w, 400, map[string]string{"error": code, "error_description": desc})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
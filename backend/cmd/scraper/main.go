@@ -0,0 +1,79 @@
+// Command scraper runs the scraping scheduler: every SCRAPER_INTERVAL_MINUTES
+// it runs the scrapers, cleans and geocodes what they found, and then
+// announces new and changed events — webhook deliveries, live stream
+// messages over Postgres NOTIFY, and rescheduled reminders.
+//
+//	go run ./cmd/scraper
+//
+// It connects like the API server, through DATABASE_URL or DB_*, creates the
+// events tables if needed and expects the API server to have created the
+// rest (webhooks, reminders and so on). Webhook and reminder deliveries are
+// sent by the API server's dispatchers.
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"event-scraper/internal/config"
+	"event-scraper/internal/database"
+	"event-scraper/internal/scheduler"
+)
+
+func main() {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Failed to load config", zap.Error(err))
+	}
+	connStr := os.Getenv("DATABASE_URL")
+	if connStr == "" {
+		connStr = cfg.Database.ConnectionString()
+	}
+
+	sqlDB, err := sql.Open("postgres", connStr)
+	if err != nil {
+		logger.Fatal("Failed to open database", zap.Error(err))
+	}
+	defer sqlDB.Close()
+
+	db, err := database.New(sqlDB)
+	if err != nil {
+		logger.Fatal("Database ping failed", zap.Error(err))
+	}
+	if err := db.Migrate(); err != nil {
+		logger.Fatal("Migration failed", zap.Error(err))
+	}
+
+	fmt.Println(strings.Repeat("=", 80))
+	fmt.Println("🚀 EVENT SCRAPER — Scheduler")
+	fmt.Printf("   Runs every %d minutes\n", cfg.Scraper.IntervalMinutes)
+	fmt.Println("   Announces new and changed events to webhooks and the live stream")
+	fmt.Println(strings.Repeat("=", 80))
+
+	sched := scheduler.New(db, logger,
+		cfg.Scraper.IntervalMinutes,
+		time.Duration(cfg.Scraper.TimeoutSeconds)*time.Second,
+		cfg.Scraper.MaxRetries,
+	)
+	if err := sched.Start(); err != nil {
+		logger.Fatal("Failed to start scheduler", zap.Error(err))
+	}
+
+	// Graceful shutdown on Ctrl+C
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+	fmt.Println("\n⛔ Scraper shutting down gracefully...")
+	sched.Stop()
+}
@@ -0,0 +1,227 @@
+// backend/cmd/server/accounts.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"event-scraper/internal/accounts"
+	"event-scraper/internal/lockout"
+	"event-scraper/internal/notify"
+	"event-scraper/internal/sessions"
+)
+
+// ─── Email verification & password reset ─────────────────────────────────────
+//
+// Both flows mail a link to the frontend (APP_URL) carrying a single-use
+// token; the page posts the token back here. Mail goes out through the
+// MAIL_DRIVER mailer, so in development the links land in the server log
+// or in MAIL_DIR.
+
+var appURL = strings.TrimRight(getEnv("APP_URL", "http://localhost:5173"), "/")
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// sendAccountMail issues a token for user and mails them the link to the
+// frontend page at path. Sending happens in the background and failures
+// are only logged, so the response does not reveal whether mail went out.
+func (s *Server) sendAccountMail(user User, purpose accounts.Purpose, path string) error {
+	token, err := accounts.Issue(s.db, user.ID, user.Email, purpose)
+	if err != nil {
+		return err
+	}
+	link := appURL + path + "?token=" + url.QueryEscape(token)
+
+	var m notify.Mail
+	switch purpose {
+	case accounts.PurposeVerifyEmail:
+		m = notify.Mail{
+			To:      user.Email,
+			Subject: "Confirm your email address",
+			Text: fmt.Sprintf("Hi %s,\n\nConfirm your email address by opening this link:\n\n%s\n\n"+
+				"The link works once and expires in %s. If you did not sign up, ignore this email.\n",
+				user.FullName, link, humanDuration(accounts.TTL[purpose])),
+		}
+	case accounts.PurposeResetPassword:
+		m = notify.Mail{
+			To:      user.Email,
+			Subject: "Reset your password",
+			Text: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. Choose a new one here:\n\n%s\n\n"+
+				"The link works once and expires in %s. If it was not you, ignore this email; your password is unchanged.\n",
+				user.FullName, link, humanDuration(accounts.TTL[purpose])),
+		}
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.mailer.Send(ctx, m); err != nil {
+			log.Printf("⚠️  Could not send %s mail to %s: %v", purpose, user.Email, err)
+		}
+	}()
+	return nil
+}
+
+// POST /api/v1/auth/verify-email/request
+//
+// Mails the signed-in user a new verification link.
+func (s *Server) handleRequestVerification(w http.ResponseWriter, r *http.Request) {
+	user, err := s.getUser(getUserID(r))
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "User not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	if user.EmailVerified {
+		jsonError(w, "Email is already verified", 409)
+		return
+	}
+	if err := s.sendAccountMail(*user, accounts.PurposeVerifyEmail, "/verify-email"); err != nil {
+		serverError(w, "Failed to send verification email", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "Verification email sent to " + user.Email})
+}
+
+// POST /api/v1/auth/verify-email
+func (s *Server) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		jsonError(w, "token is required", 400)
+		return
+	}
+	userID, err := accounts.VerifyEmail(s.db, req.Token)
+	if errors.Is(err, accounts.ErrInvalid) {
+		jsonError(w, "This verification link is invalid or has expired", 400)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to verify email", err)
+		return
+	}
+	user, err := s.getUser(userID)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"user": user})
+}
+
+// POST /api/v1/auth/forgot-password
+//
+// Mails a reset link if the address belongs to an account. The response is
+// the same either way, so it cannot be used to find accounts.
+func (s *Server) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	req.Email = strings.TrimSpace(strings.ToLower(req.Email))
+	if req.Email == "" {
+		jsonError(w, "Email is required", 400)
+		return
+	}
+
+	var user User
+	err := s.db.QueryRow(
+		`SELECT id::text, full_name, email FROM users WHERE email=$1`, req.Email,
+	).Scan(&user.ID, &user.FullName, &user.Email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		serverError(w, "Server error", err)
+		return
+	default:
+		if err := s.sendAccountMail(user, accounts.PurposeResetPassword, "/reset-password"); err != nil {
+			serverError(w, "Failed to send reset email", err)
+			return
+		}
+	}
+	jsonOK(w, map[string]interface{}{
+		"message": "If an account uses that email, a link to reset its password is on its way",
+	})
+}
+
+// POST /api/v1/auth/reset-password
+//
+// Sets a new password and signs the account out everywhere.
+func (s *Server) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	if req.Token == "" {
+		jsonError(w, "token is required", 400)
+		return
+	}
+	if len(req.Password) < 6 {
+		jsonError(w, "Password must be at least 6 characters", 400)
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	userID, err := accounts.ResetPassword(s.db, req.Token, string(hash))
+	if errors.Is(err, accounts.ErrInvalid) {
+		jsonError(w, "This reset link is invalid or has expired", 400)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to reset password", err)
+		return
+	}
+	if _, err := sessions.RevokeAll(s.db, userID, "", sessions.ReasonPasswordReset); err != nil {
+		log.Printf("⚠️  Could not end sessions after a password reset: %v", err)
+	}
+	if user, err := s.getUser(userID); err == nil {
+		if err := lockout.Clear(s.db, user.Email); err != nil {
+			log.Printf("⚠️  Could not clear failed sign-ins after a password reset: %v", err)
+		}
+	}
+	jsonOK(w, map[string]interface{}{"message": "Password updated - sign in with your new password"})
+}
+
+// humanDuration renders d in its largest whole unit, such as "48 hours",
+// "1 minute" or "30 seconds".
+func humanDuration(d time.Duration) string {
+	n, unit := int(d.Hours()), "hour"
+	if n == 0 {
+		n, unit = int(d.Minutes()), "minute"
+	}
+	if n == 0 {
+		n, unit = int((d+time.Second-1)/time.Second), "second"
+	}
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
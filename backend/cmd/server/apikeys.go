@@ -0,0 +1,202 @@
+// backend/cmd/server/apikeys.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/roles"
+)
+
+// ─── API keys ─────────────────────────────────────────────────────────────────
+//
+// Dashboards and bots authenticate with an X-API-Key header instead of a
+// bearer token. A key acts for its owner within its scopes: events:read
+// for the routes behind optionalAuth, saved:manage for saved events,
+// webhooks:manage for webhooks and admin for the routes the owner's role
+// allows. Every other signed-in
+// route takes bearer tokens only, so a key cannot manage accounts or keys.
+//
+// Each request counts against the key's daily quota; responses carry
+// X-Quota-Limit and X-Quota-Remaining, and a used-up key gets 429 until
+// midnight UTC.
+
+// defaultKeyQuota is the daily quota of new keys, and the most that users
+// other than admins can ask for.
+var defaultKeyQuota = envInt("API_KEY_DAILY_QUOTA", 10000)
+
+type APIKeyRequest struct {
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	DailyQuota *int     `json:"daily_quota"`
+}
+
+// authorize authenticates r before next. Bearer tokens may call every
+// route, API keys only routes for one of their scopes; scope "" admits no
+// key. When optional, callers without credentials or with a bad bearer
+// token are served anonymously.
+func (s *Server) authorize(scope string, optional bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := s.authenticate(r)
+		if id.Key != nil {
+			w.Header().Set("X-Quota-Limit", strconv.Itoa(id.Key.DailyQuota))
+			w.Header().Set("X-Quota-Remaining", strconv.Itoa(id.Key.Remaining()))
+		}
+		if err != nil {
+			if optional && r.Header.Get("X-API-Key") == "" {
+				next(w, r)
+				return
+			}
+			authFailed(w, err)
+			return
+		}
+		if id.Key != nil && (scope == "" || !id.Key.Allows(scope)) {
+			msg := "Forbidden - API keys cannot be used here"
+			if scope != "" {
+				msg = "Forbidden - the API key lacks the " + scope + " scope"
+			}
+			jsonError(w, msg, 403)
+			return
+		}
+		if id.Key != nil && !s.allowKey(w, id.Key) {
+			return
+		}
+		next(w, withIdentity(r, id))
+	}
+}
+
+// authFailed answers a request whose credentials were refused.
+func authFailed(w http.ResponseWriter, err error) {
+	var authErr authError
+	switch {
+	case errors.As(err, &authErr):
+		jsonError(w, authErr.Error(), 401)
+	case errors.Is(err, apikeys.ErrQuotaExceeded):
+		now := time.Now().UTC()
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		tooManyRequests(w, midnight.Sub(now), "The API key has used up its daily quota")
+	default:
+		serverError(w, "Server error", err)
+	}
+}
+
+const errBadKey authError = "Invalid API key"
+
+// authenticateKey counts a request against key and returns its owner. The
+// owner's role applies only to keys with the admin scope.
+func (s *Server) authenticateKey(key string) (identity, error) {
+	k, err := apikeys.Use(s.db, key)
+	if errors.Is(err, apikeys.ErrInvalid) {
+		return identity{}, errBadKey
+	}
+	if err != nil {
+		return identity{Key: k}, err
+	}
+	id := identity{UserID: k.UserID, Role: roles.User, Key: k}
+	if k.Allows(apikeys.ScopeAdmin) {
+		var role string
+		err := s.db.QueryRow(`SELECT role FROM users WHERE id::text = $1`, k.UserID).Scan(&role)
+		if errors.Is(err, sql.ErrNoRows) {
+			return identity{}, errBadKey
+		}
+		if err != nil {
+			return identity{}, err
+		}
+		id.Role, _ = roles.Parse(role)
+	}
+	return id, nil
+}
+
+// scopeAllowed reports whether the caller may use scope: API keys need to
+// hold it, bearer tokens always may.
+func scopeAllowed(ctx context.Context, scope string) bool {
+	key, _ := ctx.Value(apiKeyKey).(*apikeys.Key)
+	return key == nil || key.Allows(scope)
+}
+
+// GET /api/v1/api-keys
+func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := apikeys.List(s.db, getUserID(r))
+	if err != nil {
+		serverError(w, "Failed to list API keys", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"api_keys": keys, "total": len(keys)})
+}
+
+// POST /api/v1/api-keys
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	k := apikeys.Key{UserID: getUserID(r), Name: req.Name, Scopes: req.Scopes, DailyQuota: defaultKeyQuota}
+	if req.DailyQuota != nil {
+		k.DailyQuota = *req.DailyQuota
+	}
+	if err := k.Validate(); err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	role := roleFrom(r.Context())
+	if k.DailyQuota > defaultKeyQuota && !role.Includes(roles.Admin) {
+		jsonError(w, "daily_quota can be at most "+strconv.Itoa(defaultKeyQuota), 400)
+		return
+	}
+	if k.Allows(apikeys.ScopeAdmin) && !role.Includes(roles.Moderator) {
+		jsonError(w, "Forbidden - the admin scope requires the moderator or admin role", 403)
+		return
+	}
+
+	key, err := apikeys.Create(s.db, &k)
+	if errors.Is(err, apikeys.ErrTooMany) {
+		jsonError(w, err.Error(), 409)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to create API key", err)
+		return
+	}
+	// The key is only ever returned here, right after creation.
+	jsonStatus(w, map[string]interface{}{"api_key": k, "key": key}, http.StatusCreated)
+}
+
+// DELETE /api/v1/api-keys/{id}
+func (s *Server) handleDeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	s.deleteAPIKey(w, r, getUserID(r))
+}
+
+// GET /api/v1/admin/api-keys
+func (s *Server) handleAdminListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := apikeys.List(s.db, "")
+	if err != nil {
+		serverError(w, "Failed to list API keys", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"api_keys": keys, "total": len(keys)})
+}
+
+// DELETE /api/v1/admin/api-keys/{id}
+func (s *Server) handleAdminDeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	s.deleteAPIKey(w, r, "")
+}
+
+func (s *Server) deleteAPIKey(w http.ResponseWriter, r *http.Request, userID string) {
+	err := apikeys.Delete(s.db, userID, r.PathValue("id"))
+	if errors.Is(err, apikeys.ErrNotFound) {
+		jsonError(w, "API key not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to delete API key", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "API key deleted", "deleted": true})
+}
@@ -0,0 +1,394 @@
+// backend/cmd/server/collections.go
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/lib/pq"
+
+	"event-scraper/internal/collections"
+	"event-scraper/internal/rsvp"
+)
+
+// ─── Saved event notes & tags, collections and share links ───────────────────
+
+type UpdateSavedEventRequest struct {
+	Notes  *string   `json:"notes"`
+	Tags   *[]string `json:"tags"`
+	Status *string   `json:"status"`
+}
+
+type CollectionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+type CollectionItemRequest struct {
+	EventID int64 `json:"event_id"`
+}
+
+type ReorderCollectionRequest struct {
+	EventIDs []int64 `json:"event_ids"`
+}
+
+type ShareCollectionRequest struct {
+	// ExpiresAt is when the link stops working; omitted or null for never.
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// SharedCollection is what a share link shows: no notes or tags, and only
+// the owner's name.
+type SharedCollection struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	OwnerName   string    `json:"owner_name"`
+	EventCount  int       `json:"event_count"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PATCH /api/v1/events/{id}/save
+//
+// Changes the notes, tags or status of a saved event without saving it
+// again. Marking it going answers with the going events it overlaps.
+func (s *Server) handleUpdateSavedEvent(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	eventID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return
+	}
+
+	var req UpdateSavedEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	var tags interface{}
+	if req.Tags != nil {
+		clean, err := collections.CleanTags(*req.Tags)
+		if err != nil {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		tags = pq.Array(clean)
+	}
+	if req.Status != nil {
+		err := rsvp.Check(s.db, eventID, *req.Status)
+		if errors.Is(err, rsvp.ErrBadStatus) || errors.Is(err, rsvp.ErrNotStarted) || errors.Is(err, rsvp.ErrNoDate) {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		if err != nil {
+			serverError(w, "Failed to update saved event", err)
+			return
+		}
+	}
+
+	var savedEventID int64
+	err = s.db.QueryRow(`
+		UPDATE saved_events SET notes = COALESCE($3, notes), tags = COALESCE($4, tags)
+		WHERE user_id = $1 AND event_id = $2
+		RETURNING id
+	`, userID, eventID, req.Notes, tags).Scan(&savedEventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "Event was not saved", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to update saved event", err)
+		return
+	}
+	if req.Status != nil {
+		if err := s.setSavedStatus(savedEventID, *req.Status); err != nil {
+			serverError(w, "Failed to update saved event", err)
+			return
+		}
+	}
+
+	saved, err := s.querySavedEvents("", "se.user_id = $1 AND se.event_id = $2", "se.id", userID, eventID)
+	if err != nil || len(saved) == 0 {
+		serverError(w, "Failed to load saved event", err)
+		return
+	}
+	conflicts, err := s.conflictingEvents(userID, eventID)
+	if err != nil {
+		serverError(w, "Failed to check for conflicts", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"saved_event": saved[0], "conflicts": conflicts})
+}
+
+// GET /api/v1/collections
+func (s *Server) handleListCollections(w http.ResponseWriter, r *http.Request) {
+	list, err := collections.List(s.db, getUserID(r))
+	if err != nil {
+		serverError(w, "Failed to list collections", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"collections": list, "total": len(list)})
+}
+
+// POST /api/v1/collections
+func (s *Server) handleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	c := collections.Collection{UserID: getUserID(r)}
+	if req.Name != nil {
+		c.Name = *req.Name
+	}
+	if req.Description != nil {
+		c.Description = *req.Description
+	}
+	if err := c.Validate(); err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	err := collections.Create(s.db, &c)
+	if errors.Is(err, collections.ErrNameTaken) || errors.Is(err, collections.ErrTooMany) {
+		jsonError(w, err.Error(), 409)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to create collection", err)
+		return
+	}
+	jsonStatus(w, map[string]interface{}{"collection": c}, http.StatusCreated)
+}
+
+type collectionHandler func(w http.ResponseWriter, r *http.Request, c *collections.Collection)
+
+// withCollection loads the caller's collection named by {id} before calling
+// next.
+func (s *Server) withCollection(next collectionHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		collectionID, err := pathID(r, "id")
+		if err != nil {
+			jsonError(w, "Invalid collection ID", 400)
+			return
+		}
+
+		c, err := collections.Get(s.db, getUserID(r), collectionID)
+		if errors.Is(err, collections.ErrNotFound) {
+			jsonError(w, "Collection not found", 404)
+			return
+		}
+		if err != nil {
+			serverError(w, "Server error", err)
+			return
+		}
+		next(w, r, c)
+	}
+}
+
+// collectionItems returns a collection's saved events in their order.
+func (s *Server) collectionItems(c *collections.Collection) ([]SavedEventFull, error) {
+	return s.querySavedEvents("JOIN collection_items ci ON ci.saved_event_id = se.id",
+		"ci.collection_id = $1", "ci.position, ci.added_at", c.ID)
+}
+
+// GET /api/v1/collections/{id}
+func (s *Server) handleGetCollection(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	items, err := s.collectionItems(c)
+	if err != nil {
+		serverError(w, "Failed to load collection", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"collection": c, "saved_events": items})
+}
+
+// PATCH /api/v1/collections/{id}
+func (s *Server) handleUpdateCollection(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	if req.Name != nil {
+		c.Name = *req.Name
+	}
+	if req.Description != nil {
+		c.Description = *req.Description
+	}
+	if err := c.Validate(); err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	err := collections.Update(s.db, c)
+	if errors.Is(err, collections.ErrNameTaken) {
+		jsonError(w, err.Error(), 409)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to update collection", err)
+		return
+	}
+	s.withCollection(s.handleGetCollection)(w, r)
+}
+
+// DELETE /api/v1/collections/{id}
+func (s *Server) handleDeleteCollection(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	if err := collections.Delete(s.db, c.UserID, c.ID); err != nil {
+		serverError(w, "Failed to delete collection", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "Collection deleted", "deleted": true})
+}
+
+// POST /api/v1/collections/{id}/events
+//
+// Adds an event to the collection, saving it first if needed.
+func (s *Server) handleAddCollectionEvent(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	var req CollectionItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.EventID <= 0 {
+		jsonError(w, "event_id is required", 400)
+		return
+	}
+
+	var savedEventID int64
+	err := s.db.QueryRow(`
+		SELECT id FROM saved_events WHERE user_id = $1 AND event_id = $2
+	`, c.UserID, req.EventID).Scan(&savedEventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, req.EventID).Scan(&exists); err != nil {
+			serverError(w, "Failed to load event", err)
+			return
+		}
+		if !exists {
+			jsonError(w, "Event not found", 404)
+			return
+		}
+		savedEventID, err = s.saveEvent(c.UserID, req.EventID, "", nil)
+	}
+	if err != nil {
+		serverError(w, "Failed to save event", err)
+		return
+	}
+
+	if err := collections.AddItem(s.db, c.ID, savedEventID); err != nil {
+		serverError(w, "Failed to add event", err)
+		return
+	}
+	s.withCollection(s.handleGetCollection)(w, r)
+}
+
+// DELETE /api/v1/collections/{id}/events/{eventId}
+//
+// Takes an event out of the collection; it stays saved.
+func (s *Server) handleRemoveCollectionEvent(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	eventID, err := pathID(r, "eventId")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return
+	}
+	removed, err := collections.RemoveItem(s.db, c.ID, eventID)
+	if err != nil {
+		serverError(w, "Failed to remove event", err)
+		return
+	}
+	if !removed {
+		jsonError(w, "Event is not in this collection", 404)
+		return
+	}
+	s.withCollection(s.handleGetCollection)(w, r)
+}
+
+// PUT /api/v1/collections/{id}/order
+func (s *Server) handleReorderCollection(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	var req ReorderCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	err := collections.Reorder(s.db, c.ID, req.EventIDs)
+	if errors.Is(err, collections.ErrBadOrder) {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to reorder collection", err)
+		return
+	}
+	s.withCollection(s.handleGetCollection)(w, r)
+}
+
+// POST /api/v1/collections/{id}/share
+//
+// Creates a share link, replacing any earlier one.
+func (s *Server) handleShareCollection(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	var req ShareCollectionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "Invalid request body", 400)
+			return
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		jsonError(w, "expires_at must be in the future", 400)
+		return
+	}
+	if err := collections.EnableShare(s.db, c, req.ExpiresAt); err != nil {
+		serverError(w, "Failed to share collection", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{
+		"collection": c,
+		"url":        appURL + "/shared/" + c.Share.Token,
+	})
+}
+
+// DELETE /api/v1/collections/{id}/share
+func (s *Server) handleUnshareCollection(w http.ResponseWriter, r *http.Request, c *collections.Collection) {
+	if err := collections.DisableShare(s.db, c); err != nil {
+		serverError(w, "Failed to revoke share link", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"collection": c})
+}
+
+// GET /api/v1/shared/collections/{token}
+//
+// The public, read-only view of a shared collection.
+func (s *Server) handleGetSharedCollection(w http.ResponseWriter, r *http.Request) {
+	c, err := collections.ByShareToken(s.db, r.PathValue("token"))
+	if errors.Is(err, collections.ErrNotFound) {
+		jsonError(w, "Shared collection not found", 404)
+		return
+	}
+	if errors.Is(err, collections.ErrExpired) {
+		jsonError(w, "This share link has expired", 410)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to load collection", err)
+		return
+	}
+
+	items, err := s.collectionItems(c)
+	if err != nil {
+		serverError(w, "Failed to load collection", err)
+		return
+	}
+	events := make([]Event, len(items))
+	for i, item := range items {
+		events[i] = item.Event
+	}
+
+	shared := SharedCollection{
+		Name: c.Name, Description: c.Description, EventCount: len(events), UpdatedAt: c.UpdatedAt,
+	}
+	if err := s.db.QueryRow(`
+		SELECT COALESCE(full_name, '') FROM users WHERE id = $1
+	`, c.UserID).Scan(&shared.OwnerName); err != nil {
+		serverError(w, "Failed to load collection", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"collection": shared, "events": events})
+}
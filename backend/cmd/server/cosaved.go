@@ -0,0 +1,213 @@
+// backend/cmd/server/cosaved.go
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"event-scraper/internal/cosaved"
+	"event-scraper/internal/similar"
+	"event-scraper/pkg/utils"
+)
+
+// ─── "Also saved" recommendations ────────────────────────────────────────────
+//
+// internal/cosaved rebuilds, every hour, which events are saved together.
+// Where that data is sparse (few savers, or pairs below the minimum
+// support), the lists are filled up with content-based matches from
+// internal/similar. Every entry says which of the two it came from.
+
+// Where a recommendation came from.
+const (
+	sourceSaved   = "saved"
+	sourceSimilar = "similar"
+)
+
+// alsoSavedLimit is how many events GET /events/{id}/also-saved returns.
+const alsoSavedLimit = 10
+
+// contentSeeds is how many of a user's saved events seed the content-based
+// fallback of their recommendations: the ones they attended, then the most
+// recently saved. Skipped events never do.
+const contentSeeds = 5
+
+type AlsoSavedEvent struct {
+	Event
+	Source  string           `json:"source"`
+	Savers  int              `json:"savers,omitempty"`
+	Reasons []similar.Reason `json:"reasons,omitempty"`
+}
+
+type RecommendedEvent struct {
+	Event
+	Source  string           `json:"source"`
+	Because []int64          `json:"because"`
+	Reasons []similar.Reason `json:"reasons,omitempty"`
+}
+
+// GET /api/v1/events/{id}/also-saved
+func (s *Server) handleAlsoSaved(w http.ResponseWriter, r *http.Request) {
+	eventID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return
+	}
+
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, eventID).Scan(&exists); err != nil {
+		serverError(w, "Failed to load event", err)
+		return
+	}
+	if !exists {
+		jsonError(w, "Event not found", 404)
+		return
+	}
+
+	matches, err := cosaved.AlsoSaved(s.db, eventID)
+	if err != nil {
+		serverError(w, "Failed to load recommendations", err)
+		return
+	}
+	ids := make([]int64, len(matches))
+	savers := make(map[int64]int, len(matches))
+	for i, m := range matches {
+		ids[i] = m.EventID
+		savers[m.EventID] = m.Savers
+	}
+	loaded, err := s.loadEventsByID(ids)
+	if err != nil {
+		serverError(w, "Failed to load recommendations", err)
+		return
+	}
+
+	events := []AlsoSavedEvent{}
+	seen := map[int64]bool{eventID: true}
+	for _, e := range loaded {
+		if len(events) == alsoSavedLimit {
+			break
+		}
+		if utils.IsUpcoming(e.Date) {
+			events = append(events, AlsoSavedEvent{Event: e, Source: sourceSaved, Savers: savers[int64(e.ID)]})
+			seen[int64(e.ID)] = true
+		}
+	}
+
+	if len(events) < alsoSavedLimit {
+		like, err := s.similarEvents(eventID, alsoSavedLimit)
+		if err != nil {
+			serverError(w, "Failed to load recommendations", err)
+			return
+		}
+		for _, e := range like {
+			if len(events) == alsoSavedLimit {
+				break
+			}
+			if !seen[int64(e.ID)] {
+				events = append(events, AlsoSavedEvent{Event: e.Event, Source: sourceSimilar, Reasons: e.Reasons})
+				seen[int64(e.ID)] = true
+			}
+		}
+	}
+
+	jsonOK(w, map[string]interface{}{
+		"events": events,
+		"total":  len(events),
+	})
+}
+
+// GET /api/v1/events/recommended?limit=20
+//
+// Events saved by people who saved the same events as the caller, then
+// events like the ones the caller attended or most recently saved.
+func (s *Server) handleRecommendedForUser(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	picks, err := cosaved.ForUser(s.db, userID)
+	if err != nil {
+		serverError(w, "Failed to load recommendations", err)
+		return
+	}
+	ids := make([]int64, len(picks))
+	because := make(map[int64][]int64, len(picks))
+	for i, p := range picks {
+		ids[i] = p.EventID
+		because[p.EventID] = p.Because
+	}
+	loaded, err := s.loadEventsByID(ids)
+	if err != nil {
+		serverError(w, "Failed to load recommendations", err)
+		return
+	}
+
+	events := []RecommendedEvent{}
+	seen := map[int64]bool{}
+	for _, e := range loaded {
+		if len(events) == limit {
+			break
+		}
+		if utils.IsUpcoming(e.Date) {
+			events = append(events, RecommendedEvent{Event: e, Source: sourceSaved, Because: because[int64(e.ID)]})
+			seen[int64(e.ID)] = true
+		}
+	}
+
+	if len(events) < limit {
+		rows, err := s.db.Query(`
+			SELECT event_id, status = 'skipped' FROM saved_events WHERE user_id = $1
+			ORDER BY status = 'attended' DESC, saved_at DESC
+		`, userID)
+		if err != nil {
+			serverError(w, "Failed to load recommendations", err)
+			return
+		}
+		var seeds []int64
+		for rows.Next() {
+			var id int64
+			var skipped bool
+			if err := rows.Scan(&id, &skipped); err != nil {
+				rows.Close()
+				serverError(w, "Failed to load recommendations", err)
+				return
+			}
+			seen[id] = true
+			if !skipped && len(seeds) < contentSeeds {
+				seeds = append(seeds, id)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			serverError(w, "Failed to load recommendations", err)
+			return
+		}
+
+		like, err := s.similarEventsOf(seeds, limit)
+		if err != nil {
+			serverError(w, "Failed to load recommendations", err)
+			return
+		}
+		// Take the best match of every seed, then the second best, and so on.
+		for rank := 0; rank < limit && len(events) < limit; rank++ {
+			for _, seed := range seeds {
+				if rank >= len(like[seed]) || len(events) == limit {
+					continue
+				}
+				e := like[seed][rank]
+				if !seen[int64(e.ID)] {
+					events = append(events, RecommendedEvent{
+						Event: e.Event, Source: sourceSimilar, Because: []int64{seed}, Reasons: e.Reasons,
+					})
+					seen[int64(e.ID)] = true
+				}
+			}
+		}
+	}
+
+	jsonOK(w, map[string]interface{}{
+		"events": events,
+		"total":  len(events),
+	})
+}
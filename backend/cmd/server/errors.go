@@ -0,0 +1,151 @@
+// backend/cmd/server/errors.go
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ─── Error envelope ───────────────────────────────────────────────────────────
+//
+// Every error response has the same shape:
+//
+//	{"error": {"code": "not_found", "message": "Event not found", "request_id": "9f3c…"}}
+//
+// code is stable and meant for programs; message is for people and may
+// change. request_id matches the X-Request-ID response header and the
+// server log line of a 500.
+
+type APIError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type errorEnvelope struct {
+	Error APIError `json:"error"`
+}
+
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal"
+	CodeUnavailable      = "unavailable"
+
+	// CodeRefreshTokenReused is a 401: a spent refresh token came back, so
+	// its session was revoked.
+	CodeRefreshTokenReused = "refresh_token_reused"
+)
+
+// errorCode is the default code for an HTTP status.
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return CodeUnavailable
+	default:
+		return CodeInternal
+	}
+}
+
+func jsonError(w http.ResponseWriter, msg string, status int) {
+	jsonErrorCode(w, errorCode(status), msg, status)
+}
+
+// jsonErrorCode writes an error whose code is more specific than the
+// status alone, e.g. "email_taken" for a 409.
+func jsonErrorCode(w http.ResponseWriter, code, msg string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorEnvelope{Error: APIError{
+		Code:      code,
+		Message:   msg,
+		RequestID: w.Header().Get(requestIDHeader),
+	}})
+}
+
+// serverError logs err under the request ID and answers with msg alone, so
+// database and driver errors never reach clients.
+func serverError(w http.ResponseWriter, msg string, err error) {
+	log.Printf("⚠️  [%s] %s: %v", w.Header().Get(requestIDHeader), msg, err)
+	jsonError(w, msg, http.StatusInternalServerError)
+}
+
+// ─── Request IDs ──────────────────────────────────────────────────────────────
+
+const requestIDHeader = "X-Request-ID"
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// withRequestID tags every response with an X-Request-ID, reusing the
+// caller's when it sends a sane one. Handlers read it back from the
+// response header, so error helpers need only the ResponseWriter.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ─── Unmatched routes ─────────────────────────────────────────────────────────
+
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// withFallback answers requests that match no route with the error envelope
+// instead of ServeMux's plain-text 404 and 405 pages.
+func withFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		var allow []string
+		for _, m := range routeMethods {
+			probe := r.Clone(r.Context())
+			probe.Method = m
+			if _, pattern := mux.Handler(probe); pattern != "" {
+				allow = append(allow, m)
+			}
+		}
+		if len(allow) > 0 {
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		jsonError(w, "Not found", http.StatusNotFound)
+	})
+}
@@ -0,0 +1,214 @@
+// backend/cmd/server/facets.go
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ─── Facets ───────────────────────────────────────────────────────────────────
+//
+// /api/v1/events answers with a count per value for each filter dimension,
+// under the current filters. A dimension's own filter is left out of its
+// counts, so picking tech=go still shows how many rust events there are.
+// Values within a dimension are ORed; dimensions are ANDed.
+
+// FacetCount is one facet value and the number of matching events.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type Facets struct {
+	Location  []FacetCount `json:"location"`
+	Source    []FacetCount `json:"source"`
+	Tech      []FacetCount `json:"tech"`
+	Price     []FacetCount `json:"price"`
+	Organizer []FacetCount `json:"organizer"`
+	EventType []FacetCount `json:"event_type"`
+}
+
+// Price buckets. event_cleaned.price is free text ("Free", "₹499", "INR 0").
+const (
+	PriceFree    = "free"
+	PricePaid    = "paid"
+	PriceUnknown = "unknown"
+)
+
+var priceBuckets = map[string]bool{PriceFree: true, PricePaid: true, PriceUnknown: true}
+
+// priceBucketExpr sorts a price column into the buckets above.
+func priceBucketExpr(col string) string {
+	return fmt.Sprintf(`(CASE
+		WHEN %[1]s IS NULL OR btrim(%[1]s) = '' THEN 'unknown'
+		WHEN %[1]s ~* '\mfree\M' OR %[1]s ~* '^\s*(₹|rs\.?|inr)?\s*0+(\.0+)?\s*$' THEN 'free'
+		ELSE 'paid' END)`, col)
+}
+
+// facetDim describes how to count one dimension. value is an SQL expression
+// over inner_e (events) and fc (event_cleaned); lateral, when set, joins the
+// set the value is drawn from. without returns the filter minus this
+// dimension's own values.
+type facetDim struct {
+	name    string
+	value   string
+	lateral string
+	limit   int
+	without func(EventFilter) EventFilter
+	out     func(*Facets) *[]FacetCount
+}
+
+var facetDims = []facetDim{
+	{
+		name:    "location",
+		value:   "NULLIF(inner_e.city_normalized, 'Unknown')",
+		without: func(f EventFilter) EventFilter { f.Location = ""; return f },
+		out:     func(fs *Facets) *[]FacetCount { return &fs.Location },
+	},
+	{
+		name:    "source",
+		value:   "inner_e.platform",
+		without: func(f EventFilter) EventFilter { f.Source = ""; return f },
+		out:     func(fs *Facets) *[]FacetCount { return &fs.Source },
+	},
+	{
+		name:    "tech",
+		value:   "t.tech",
+		lateral: "CROSS JOIN LATERAL unnest(fc.tech_stack) AS t(tech)",
+		limit:   50,
+		without: func(f EventFilter) EventFilter { f.Tech = nil; return f },
+		out:     func(fs *Facets) *[]FacetCount { return &fs.Tech },
+	},
+	{
+		name:    "price",
+		value:   priceBucketExpr("fc.price"),
+		without: func(f EventFilter) EventFilter { f.Price = nil; return f },
+		out:     func(fs *Facets) *[]FacetCount { return &fs.Price },
+	},
+	{
+		name:    "organizer",
+		value:   "fc.organizer",
+		limit:   50,
+		without: func(f EventFilter) EventFilter { f.Organizer = nil; return f },
+		out:     func(fs *Facets) *[]FacetCount { return &fs.Organizer },
+	},
+	{
+		name:    "event_type",
+		value:   "inner_e.event_type",
+		without: func(f EventFilter) EventFilter { f.EventType = nil; return f },
+		out:     func(fs *Facets) *[]FacetCount { return &fs.EventType },
+	},
+}
+
+// eventFacets counts every dimension under f. The dimensions are queried
+// concurrently; one that fails is logged and left empty rather than failing
+// the listing.
+func (s *Server) eventFacets(f EventFilter) *Facets {
+	fs := &Facets{}
+	var wg sync.WaitGroup
+	for _, d := range facetDims {
+		wg.Add(1)
+		go func(d facetDim) {
+			defer wg.Done()
+			counts, err := s.countFacet(d, d.without(f))
+			if err != nil {
+				log.Printf("⚠️  Facet %s: %v", d.name, err)
+			}
+			*d.out(fs) = counts
+		}(d)
+	}
+	wg.Wait()
+	return fs
+}
+
+func (s *Server) countFacet(d facetDim, f EventFilter) ([]FacetCount, error) {
+	where, args := f.where()
+	limit := ""
+	if d.limit > 0 {
+		limit = fmt.Sprintf("LIMIT %d", d.limit)
+	}
+	// Values are grouped case-insensitively and shown in their most common
+	// spelling; the filters match case-insensitively too.
+	rows, err := s.db.Query(fmt.Sprintf(`
+		SELECT mode() WITHIN GROUP (ORDER BY v), COUNT(DISTINCT id)
+		FROM (
+			SELECT inner_e.id, btrim(%s) AS v
+			FROM events inner_e
+			LEFT JOIN event_cleaned fc ON fc.event_id = inner_e.id
+			%s
+			%s
+		) x
+		WHERE v IS NOT NULL AND v <> ''
+		GROUP BY lower(v)
+		ORDER BY 2 DESC, 1 ASC
+		%s
+	`, d.value, d.lateral, where, limit), args...)
+	if err != nil {
+		return []FacetCount{}, err
+	}
+	defer rows.Close()
+
+	counts := []FacetCount{}
+	for rows.Next() {
+		var c FacetCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			return counts, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// facetValues lists the values of a facet alphabetically, for the legacy
+// locations and sources arrays.
+func facetValues(counts []FacetCount) []string {
+	vals := make([]string, len(counts))
+	for i, c := range counts {
+		vals[i] = c.Value
+	}
+	sort.Strings(vals)
+	return vals
+}
+
+// queryValues collects a repeatable query parameter (tech=go&tech=rust),
+// dropping blanks and case-insensitive repeats. Values are not split on
+// commas: organizer names contain them.
+func queryValues(q url.Values, name string) []string {
+	var vals []string
+	seen := map[string]bool{}
+	for _, v := range q[name] {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		vals = append(vals, v)
+	}
+	return vals
+}
+
+// lowerAll lower-cases vals for the case-insensitive filters.
+func lowerAll(vals []string) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
+
+// parsePriceFilter lower-cases and validates price values.
+func parsePriceFilter(vals []string) ([]string, error) {
+	out := make([]string, 0, len(vals))
+	for _, v := range vals {
+		v = strings.ToLower(v)
+		if !priceBuckets[v] {
+			return nil, fmt.Errorf("price must be %s, %s or %s", PriceFree, PricePaid, PriceUnknown)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
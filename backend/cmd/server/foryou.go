@@ -0,0 +1,281 @@
+// backend/cmd/server/foryou.go
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"event-scraper/internal/interests"
+	"event-scraper/internal/reviews"
+)
+
+// ─── Interests & the "For you" feed ───────────────────────────────────────────
+//
+// Users declare interests (see internal/interests); /events/for-you ranks
+// upcoming events they have not saved by how well they match. Saved events
+// add implicit interests: the technologies and cities that come up most
+// among them count too, for less. Events the user attended weigh double
+// in that count and skipped ones not at all. Events by an organizer that
+// attendees rate highly (see internal/reviews) get a small boost, though
+// that alone never makes an event a match. Every result lists the reasons
+// behind its score, so the ranking can be explained in the UI.
+
+// Points per match. A technology counts once per matching tag.
+const (
+	pointsTech      = 3
+	pointsCity      = 2
+	pointsFormat    = 1
+	pointsPrice     = 1
+	pointsSavedTech = 1
+	pointsSavedCity = 1
+	pointsQuality   = 1
+)
+
+// qualityMinRating is the average rating from which an organizer counts as
+// highly rated.
+const qualityMinRating = 4
+
+// savedWeightExpr weighs a saved event (alias se) in the implicit interests.
+const savedWeightExpr = `CASE WHEN se.status = 'attended' THEN 2 ELSE 1 END`
+
+// How many of the technologies and cities most common among saved events
+// count as implicit interests.
+const (
+	savedTechSignals = 10
+	savedCitySignals = 3
+)
+
+// Reason is one match behind a recommendation. Source is "interest" for a
+// declared interest, "saved" for one drawn from saved events and "reviews"
+// for the organizer's rating.
+type Reason struct {
+	Kind   string `json:"kind"` // tech, city, format, price or quality
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Points int    `json:"points"`
+	Text   string `json:"text"`
+}
+
+type ForYouEvent struct {
+	Event
+	Score   int      `json:"score"`
+	Reasons []Reason `json:"reasons"`
+}
+
+type InterestsRequest struct {
+	Tech    *[]string `json:"tech"`
+	Cities  *[]string `json:"cities"`
+	Formats *[]string `json:"formats"`
+	Price   *string   `json:"price"`
+}
+
+// GET /api/v1/interests
+func (s *Server) handleGetInterests(w http.ResponseWriter, r *http.Request) {
+	in, err := interests.Get(s.db, getUserID(r))
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"interests": in})
+}
+
+// PUT /api/v1/interests
+//
+// Fields left out keep their current values.
+func (s *Server) handleUpdateInterests(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	in, err := interests.Get(s.db, userID)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+
+	var req InterestsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	if req.Tech != nil {
+		in.Tech = *req.Tech
+	}
+	if req.Cities != nil {
+		in.Cities = *req.Cities
+	}
+	if req.Formats != nil {
+		in.Formats = *req.Formats
+	}
+	if req.Price != nil {
+		in.Price = *req.Price
+	}
+	if err := in.Validate(); err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+
+	if err := interests.Save(s.db, userID, in); err != nil {
+		serverError(w, "Failed to save interests", err)
+		return
+	}
+	s.handleGetInterests(w, r)
+}
+
+// GET /api/v1/events/for-you?page=1&limit=20
+func (s *Server) handleForYou(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	in, err := interests.Get(s.db, userID)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	// Only upcoming and undated events, and none the user already saved.
+	rows, err := s.db.Query(fmt.Sprintf(`
+		WITH saved_tech AS (
+			SELECT lower(btrim(t.tech)) AS tech
+			FROM saved_events se
+			JOIN event_cleaned sc ON sc.event_id = se.event_id
+			CROSS JOIN LATERAL unnest(sc.tech_stack) AS t(tech)
+			WHERE se.user_id = $1 AND se.status <> 'skipped' AND NOT lower(btrim(t.tech)) = ANY($2)
+			GROUP BY 1 ORDER BY SUM(%[12]s) DESC, 1 LIMIT %[1]d
+		), saved_city AS (
+			SELECT lower(se_e.city_normalized) AS city
+			FROM saved_events se
+			JOIN events se_e ON se_e.id = se.event_id
+			WHERE se.user_id = $1 AND se.status <> 'skipped' AND se_e.city_normalized <> 'Unknown'
+			  AND NOT lower(se_e.city_normalized) = ANY($3)
+			GROUP BY 1 ORDER BY SUM(%[12]s) DESC, 1 LIMIT %[2]d
+		), rated AS (%[13]s
+		), matched AS (
+			SELECT inner_e.id,
+			       %[3]s AS sort_date,
+			       ARRAY(SELECT DISTINCT lower(btrim(t)) FROM unnest(mc.tech_stack) t
+			             WHERE lower(btrim(t)) = ANY($2)) AS tech_hits,
+			       ARRAY(SELECT DISTINCT lower(btrim(t)) FROM unnest(mc.tech_stack) t
+			             WHERE lower(btrim(t)) IN (SELECT tech FROM saved_tech)) AS saved_tech_hits,
+			       COALESCE(lower(inner_e.city_normalized) = ANY($3), false) AS city_hit,
+			       COALESCE(lower(inner_e.city_normalized) IN (SELECT city FROM saved_city), false) AS saved_city_hit,
+			       COALESCE(lower(btrim(inner_e.event_type)) = ANY($4), false) AS format_hit,
+			       ($5 <> '' AND %[4]s = $5) AS price_hit,
+			       btrim(mc.organizer) AS organizer,
+			       (SELECT ro.average FROM rated ro
+			        WHERE ro.organizer = lower(btrim(mc.organizer)) AND ro.average >= %[15]d) AS organizer_rating
+			FROM events inner_e
+			LEFT JOIN event_cleaned mc ON mc.event_id = inner_e.id
+			WHERE %[3]s >= CURRENT_DATE
+			  AND NOT EXISTS (SELECT 1 FROM saved_events s WHERE s.user_id = $1 AND s.event_id = inner_e.id)
+		), matched_score AS (
+			SELECT matched.*,
+			       %[5]d * cardinality(tech_hits) + %[6]d * cardinality(saved_tech_hits)
+			       + %[7]d * city_hit::int + %[8]d * saved_city_hit::int
+			       + %[9]d * format_hit::int + %[10]d * price_hit::int AS match_score
+			FROM matched
+		), scored AS (
+			SELECT matched_score.*,
+			       match_score + %[14]d * (organizer_rating IS NOT NULL)::int AS score
+			FROM matched_score
+		)
+		SELECT %[11]s,
+		       m.tech_hits, m.saved_tech_hits, m.city_hit, m.saved_city_hit, m.format_hit, m.price_hit,
+		       COALESCE(m.organizer, ''), m.organizer_rating, m.score, COUNT(*) OVER ()
+		FROM scored m
+		JOIN events e ON e.id = m.id
+		LEFT JOIN event_details ed ON e.id = ed.event_id
+		LEFT JOIN event_cleaned ec ON e.id = ec.event_id
+		LEFT JOIN event_geo eg ON e.id = eg.event_id
+		WHERE m.match_score > 0
+		ORDER BY m.score DESC, m.sort_date ASC, e.id ASC
+		LIMIT $6 OFFSET $7
+	`, savedTechSignals, savedCitySignals, sortDateExpr, priceBucketExpr("mc.price"),
+		pointsTech, pointsSavedTech, pointsCity, pointsSavedCity, pointsFormat, pointsPrice,
+		cleanedEventCols, savedWeightExpr, reviews.RatedOrganizersSQL, pointsQuality, qualityMinRating),
+		userID, pq.Array(interests.Lower(in.Tech)), pq.Array(interests.Lower(in.Cities)),
+		pq.Array(in.Formats), in.Price, limit, (page-1)*limit)
+	if err != nil {
+		serverError(w, "Failed to load recommendations", err)
+		return
+	}
+	defer rows.Close()
+
+	events := []ForYouEvent{}
+	total := 0
+	for rows.Next() {
+		var ev ForYouEvent
+		var techHits, savedTechHits []string
+		var cityHit, savedCityHit, formatHit, priceHit bool
+		var organizer string
+		var organizerRating sql.NullFloat64
+		if err := scanCleanedEvent(rows, &ev.Event,
+			pq.Array(&techHits), pq.Array(&savedTechHits),
+			&cityHit, &savedCityHit, &formatHit, &priceHit,
+			&organizer, &organizerRating, &ev.Score, &total,
+		); err != nil {
+			serverError(w, "Failed to load recommendations", err)
+			return
+		}
+		ev.Reasons = reasons(ev.Event, in.Price, techHits, savedTechHits, cityHit, savedCityHit, formatHit, priceHit)
+		if organizerRating.Valid {
+			ev.Reasons = append(ev.Reasons, Reason{"quality", organizer, "reviews", pointsQuality,
+				fmt.Sprintf("By %s, rated %.1f/5 by attendees", organizer, organizerRating.Float64)})
+		}
+		events = append(events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		serverError(w, "Failed to load recommendations", err)
+		return
+	}
+
+	totalPages := (total + limit - 1) / limit
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	jsonOK(w, map[string]interface{}{
+		"events":        events,
+		"total":         total,
+		"page":          page,
+		"limit":         limit,
+		"total_pages":   totalPages,
+		"has_interests": !in.Empty(),
+	})
+}
+
+// reasons explains the matches of one event, strongest first.
+func reasons(e Event, price string, techHits, savedTechHits []string,
+	cityHit, savedCityHit, formatHit, priceHit bool) []Reason {
+	out := []Reason{}
+	for _, t := range techHits {
+		out = append(out, Reason{"tech", t, "interest", pointsTech, "Covers " + t + ", one of your interests"})
+	}
+	if cityHit {
+		out = append(out, Reason{"city", e.CityNormalized, "interest", pointsCity, "In " + e.CityNormalized + ", one of your cities"})
+	}
+	for _, t := range savedTechHits {
+		out = append(out, Reason{"tech", t, "saved", pointsSavedTech, "Covers " + t + ", like events you saved"})
+	}
+	if savedCityHit {
+		out = append(out, Reason{"city", e.CityNormalized, "saved", pointsSavedCity, "In " + e.CityNormalized + ", like events you saved"})
+	}
+	if formatHit {
+		format := strings.TrimSpace(e.EventType)
+		out = append(out, Reason{"format", strings.ToLower(format), "interest", pointsFormat, format + ", a format you follow"})
+	}
+	if priceHit {
+		out = append(out, Reason{"price", price, "interest", pointsPrice, "A " + price + " event, as you prefer"})
+	}
+	return out
+}
@@ -0,0 +1,181 @@
+// backend/cmd/server/gazetteer.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"event-scraper/internal/changes"
+	"event-scraper/internal/database"
+	"event-scraper/internal/gazetteer"
+	"event-scraper/internal/geo"
+	"event-scraper/internal/utils"
+)
+
+// ─── Gazetteer admin ──────────────────────────────────────────────────────────
+//
+// Aliases added here extend the embedded places.tsv without a redeploy. The
+// API server swaps them in at once and re-normalizes the events that mention
+// them; the scheduler and the Python scrapers read them at the start of
+// their next run. /admin/gazetteer/unresolved lists what to map next.
+
+// AliasRequest maps name to a city (kind alias, the default), or makes it a
+// venue or locality of one. venue copies kind, city and position from an
+// existing venue or locality instead.
+type AliasRequest struct {
+	Name  string   `json:"name"`
+	City  string   `json:"city"`
+	Kind  string   `json:"kind"`
+	Lat   *float64 `json:"lat"`
+	Lng   *float64 `json:"lng"`
+	Venue string   `json:"venue"`
+}
+
+// GET /api/v1/admin/gazetteer/cities
+func (s *Server) handleGazetteerCities(w http.ResponseWriter, r *http.Request) {
+	cities := gazetteer.Current().Cities()
+	jsonOK(w, map[string]interface{}{"cities": cities, "total": len(cities)})
+}
+
+// GET /api/v1/admin/gazetteer/aliases
+func (s *Server) handleListAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := gazetteer.ListAliases(s.db)
+	if err != nil {
+		serverError(w, "Failed to list aliases", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"aliases": aliases, "total": len(aliases)})
+}
+
+// GET /api/v1/admin/gazetteer/unresolved?limit=50&samples=3
+func (s *Server) handleUnresolvedLocations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	samples, err := strconv.Atoi(q.Get("samples"))
+	if err != nil || samples < 0 || samples > 10 {
+		samples = 3
+	}
+	// Garbage ("TBA", URLs) never reaches the gazetteer, so mapping it
+	// would not help.
+	texts, unknown, err := gazetteer.UnresolvedTexts(s.db, limit, samples, utils.IsGarbageLocation)
+	if err != nil {
+		serverError(w, "Failed to list unresolved locations", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"unresolved": texts, "total": len(texts), "unknown_events": unknown})
+}
+
+// POST /api/v1/admin/gazetteer/aliases
+//
+// Adds an alias, or replaces an existing one, then re-normalizes the events
+// that mention it.
+func (s *Server) handleCreateAlias(w http.ResponseWriter, r *http.Request) {
+	var req AliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	alias := gazetteer.Alias{Name: req.Name, Kind: gazetteer.Kind(req.Kind), City: req.City, Lat: req.Lat, Lng: req.Lng}
+	if req.Venue != "" {
+		p, err := gazetteer.Current().Place(req.Venue, req.City)
+		if errors.Is(err, gazetteer.ErrUnknownPlace) {
+			jsonError(w, "venue must name a known venue or locality", 400)
+			return
+		}
+		if err != nil {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		alias.Kind, alias.City = p.Kind, p.City
+		if p.HasPoint && alias.Lat == nil && alias.Lng == nil {
+			alias.Lat, alias.Lng = &p.Lat, &p.Lng
+		}
+	}
+
+	err := alias.Validate()
+	switch {
+	case errors.Is(err, gazetteer.ErrBuiltIn):
+		jsonError(w, "'"+alias.Name+"' is already in the gazetteer", 409)
+		return
+	case errors.Is(err, gazetteer.ErrUnknownCity):
+		jsonError(w, "city must be one of /api/v1/admin/gazetteer/cities", 400)
+		return
+	case err != nil:
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	alias.CreatedBy = getUserID(r)
+	if err := gazetteer.SaveAlias(s.db, &alias); err != nil {
+		serverError(w, "Failed to save alias", err)
+		return
+	}
+
+	matched, changed := s.reloadGazetteer(alias.Name)
+	jsonStatus(w, map[string]interface{}{
+		"alias": alias, "matched_events": matched, "renormalized_events": changed,
+	}, http.StatusCreated)
+}
+
+// DELETE /api/v1/admin/gazetteer/aliases/{name}
+//
+// Events that mention the alias are re-normalized; one that no longer
+// resolves keeps its city.
+func (s *Server) handleDeleteAlias(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	err := gazetteer.DeleteAlias(s.db, name)
+	if errors.Is(err, gazetteer.ErrNotFound) {
+		jsonError(w, "Alias not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to delete alias", err)
+		return
+	}
+	s.reloadGazetteer(name)
+	jsonOK(w, map[string]interface{}{"message": "Alias deleted", "deleted": true})
+}
+
+// reloadGazetteer swaps in the stored aliases, re-normalizes the events
+// that mention name and queues them for geocoding. Events whose city
+// changed are announced the way a scrape cycle announces changes, so
+// webhooks and the live stream see them. It returns how many events mention
+// name and how many changed city. Failures are only logged, since the alias
+// itself is already saved.
+func (s *Server) reloadGazetteer(name string) (matched, changed int) {
+	if err := gazetteer.Refresh(s.db); err != nil {
+		log.Printf("⚠️  Could not load gazetteer aliases: %v", err)
+		return 0, 0
+	}
+	ids, renormalized, err := gazetteer.Renormalize(s.db, name, func(location, address string) string {
+		city := utils.ExtractCity(location)
+		if city == "Unknown" && address != "" {
+			city = utils.ExtractCity(address)
+		}
+		return city
+	})
+	if err != nil {
+		log.Printf("⚠️  Could not re-normalize events for %q: %v", name, err)
+	}
+	if found, err := database.CollectEventChangesByID(s.db, renormalized); err != nil {
+		log.Printf("⚠️  Could not collect re-normalized events for %q: %v", name, err)
+	} else {
+		changes.Announce(s.db, s.publisher, found)
+	}
+	if err := geo.Invalidate(s.db, ids); err != nil {
+		log.Printf("⚠️  Could not re-geocode events for %q: %v", name, err)
+	} else if len(ids) > 0 {
+		go func() {
+			if _, err := geo.NewGeocoder(s.db).GeocodePending(context.Background()); err != nil {
+				log.Printf("⚠️  Geocoding failed: %v", err)
+			}
+		}()
+	}
+	return len(ids), len(renormalized)
+}
@@ -0,0 +1,322 @@
+// backend/cmd/server/graphql.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// ─── GraphQL ──────────────────────────────────────────────────────────────────
+//
+// POST /api/v1/graphql {"query": "...", "variables": {...}, "operationName": "..."}
+// GET  /api/v1/graphql?query=...
+//
+// Exposes the same data as the REST endpoints in one round trip. Nested
+// fields of a list (detail, cleaned, saved, recommendations, scraperRuns) are
+// loaded once per list, not once per event — see graphql_resolvers.go.
+
+const graphqlSchema = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	# Same filters, sorts and paging as GET /api/v1/events. Pass a previous
+	# page's nextCursor as after to continue from it; page is then ignored.
+	events(q: String, location: String, source: String, from: String, to: String,
+		tech: [String!], price: [String!], organizer: [String!], eventType: [String!],
+		lat: Float, lng: Float, radiusKm: Float,
+		sort: String, after: String, page: Int = 1, limit: Int = 8): EventPage!
+	event(id: ID!): Event
+	# Requires a bearer token or an API key with saved:manage.
+	savedEvents: [SavedEvent!]!
+	# Requires the admin role.
+	scraperRuns(scraper: String, limit: Int = 20): [ScraperRun!]!
+	filters: Filters!
+}
+
+type Mutation {
+	# Require a bearer token or an API key with saved:manage.
+	saveEvent(id: ID!, notes: String): SavedEvent!
+	unsaveEvent(id: ID!): Boolean!
+}
+
+type EventPage {
+	events: [Event!]!
+	total: Int!
+	# 0 when paging by cursor.
+	page: Int!
+	limit: Int!
+	totalPages: Int!
+	sort: String!
+	nextCursor: String
+	# Counts under the page's filters, each dimension ignoring its own.
+	facets: Facets!
+}
+
+type Filters {
+	locations: [String!]!
+	sources: [String!]!
+	facets: Facets!
+}
+
+type Facets {
+	location: [FacetCount!]!
+	source: [FacetCount!]!
+	tech: [FacetCount!]!
+	# free, paid or unknown.
+	price: [FacetCount!]!
+	organizer: [FacetCount!]!
+	eventType: [FacetCount!]!
+}
+
+type FacetCount {
+	value: String!
+	count: Int!
+}
+
+type Event {
+	id: ID!
+	# Cleaned title when available, otherwise the scraped name.
+	name: String!
+	location: String!
+	city: String!
+	dateTime: String!
+	date: String!
+	time: String!
+	website: String!
+	description: String!
+	address: String!
+	eventType: String!
+	platform: String!
+	imageUrl: String!
+	createdAt: String!
+	# Position from the offline gazetteer; null for online or unplaced events.
+	lat: Float
+	lng: Float
+	# venue, locality or city (the city's centre).
+	geoConfidence: String
+	# Only set when searching near a point.
+	distanceKm: Float
+	cleaned: CleanedEvent
+	detail: EventDetail
+	# False when the request is anonymous.
+	saved: Boolean!
+	recommendations(limit: Int = 10): [Event!]!
+	# Recent runs of the scraper that produced this event. Requires the
+	# admin role.
+	scraperRuns(limit: Int = 5): [ScraperRun!]!
+}
+
+type CleanedEvent {
+	title: String!
+	description: String!
+	date: String!
+	time: String!
+	location: String!
+	address: String!
+	techStack: [String!]!
+	speakers: [String!]!
+	organizer: String!
+	price: String!
+	confidence: Int!
+	summary: String!
+	highlights: [String!]!
+}
+
+type EventDetail {
+	fullDescription: String!
+	organizer: String!
+	organizerContact: String!
+	imageUrl: String!
+	tags: String!
+	price: String!
+	registrationUrl: String!
+	duration: String!
+	agendaHtml: String!
+	speakersJson: String!
+	prerequisites: String!
+	maxAttendees: Int!
+	attendeesCount: Int!
+}
+
+type SavedEvent {
+	id: ID!
+	notes: String!
+	# interested, going, attended or skipped.
+	status: String!
+	savedAt: String!
+	event: Event!
+}
+
+type ScraperRun {
+	id: ID!
+	scraper: String!
+	success: Boolean!
+	eventsFound: Int!
+	eventsFiltered: Int!
+	error: String!
+	durationSeconds: Float!
+	runAt: String!
+}
+`
+
+// Query limits. Depth and length are enforced by graphql-go; complexity is
+// charged by the root resolvers before they touch the database.
+const (
+	graphqlMaxDepth       = 8
+	graphqlMaxQueryLength = 8 << 10
+	graphqlMaxComplexity  = 5000
+)
+
+func (s *Server) newGraphQLSchema() *graphql.Schema {
+	return graphql.MustParseSchema(graphqlSchema, &gqlRoot{s: s},
+		graphql.MaxDepth(graphqlMaxDepth),
+		graphql.MaxQueryLength(graphqlMaxQueryLength),
+		graphql.MaxParallelism(10),
+	)
+}
+
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (s *Server) handleGraphQL(schema *graphql.Schema) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req graphqlRequest
+		switch r.Method {
+		case http.MethodGet:
+			q := r.URL.Query()
+			req.Query = q.Get("query")
+			req.OperationName = q.Get("operationName")
+			if v := q.Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+					jsonError(w, "Invalid variables", 400)
+					return
+				}
+			}
+			// GET must stay side-effect free.
+			if strings.HasPrefix(strings.TrimSpace(req.Query), "mutation") {
+				jsonError(w, "Mutations require POST", http.StatusMethodNotAllowed)
+				return
+			}
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				jsonError(w, "Invalid request body", 400)
+				return
+			}
+		default:
+			jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if strings.TrimSpace(req.Query) == "" {
+			jsonError(w, "query is required", 400)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), complexityKey, &complexityBudget{remaining: graphqlMaxComplexity})
+		resp := schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+		for _, e := range resp.Errors {
+			var ue *userError
+			if e.ResolverError != nil && !errors.As(e.ResolverError, &ue) {
+				log.Printf("⚠️  [%s] GraphQL %v: %v", w.Header().Get(requestIDHeader), e.Path, e.ResolverError)
+				e.Message = "Internal error"
+				e.ResolverError = nil
+			}
+		}
+		jsonOK(w, resp)
+	}
+}
+
+// userError is a resolver error whose message is safe to show to clients.
+// Any other resolver error is logged and reported as "Internal error", so
+// database errors stay on the server.
+type userError struct{ msg string }
+
+func (e *userError) Error() string { return e.msg }
+
+func userErrorf(format string, args ...interface{}) error {
+	return &userError{msg: fmt.Sprintf(format, args...)}
+}
+
+// ─── Complexity ───────────────────────────────────────────────────────────────
+
+// listFields names the list-valued fields whose children are multiplied by
+// the list size when costing a query. Size comes from the field's limit
+// argument or the default when it is omitted.
+var listFields = map[string]int32{
+	"recommendations": 10,
+	"scraperRuns":     5,
+}
+
+const complexityKey contextKey = "graphqlComplexity"
+
+type complexityBudget struct {
+	mu        sync.Mutex
+	remaining int
+}
+
+// chargeComplexity costs the selection below the current root field and
+// deducts it from the request's budget. Every selected field costs 1 per
+// parent item; base is the number of items the root field returns and sizes
+// overrides the size of list fields whose length the root already knows.
+func chargeComplexity(ctx context.Context, base int, sizes map[string]int) error {
+	budget, _ := ctx.Value(complexityKey).(*complexityBudget)
+	if budget == nil {
+		return nil
+	}
+
+	mult := map[string]int{"": base}
+	var multiplier func(path string) int
+	multiplier = func(path string) int {
+		if m, ok := mult[path]; ok {
+			return m
+		}
+		parent, name := "", path
+		if i := strings.LastIndex(path, "."); i >= 0 {
+			parent, name = path[:i], path[i+1:]
+		}
+		size := 1
+		if n, ok := sizes[path]; ok {
+			size = n
+		} else if def, ok := listFields[name]; ok {
+			var args struct{ Limit *int32 }
+			size = int(def)
+			if ok, _ := graphql.DecodeSelectedFieldArgs(ctx, path, &args); ok && args.Limit != nil {
+				size = int(*args.Limit)
+			}
+		}
+		m := multiplier(parent) * max(size, 1)
+		mult[path] = m
+		return m
+	}
+
+	cost := base
+	for _, path := range graphql.SelectedFieldNames(ctx) {
+		parent := ""
+		if i := strings.LastIndex(path, "."); i >= 0 {
+			parent = path[:i]
+		}
+		cost += multiplier(parent)
+	}
+
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	if cost > budget.remaining {
+		return userErrorf("query too complex: cost %d exceeds the remaining budget of %d (max %d)",
+			cost, budget.remaining, graphqlMaxComplexity)
+	}
+	budget.remaining -= cost
+	return nil
+}
@@ -0,0 +1,886 @@
+// backend/cmd/server/graphql_resolvers.go
+package main
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/lib/pq"
+
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/roles"
+)
+
+// gqlRoot resolves Query and Mutation.
+type gqlRoot struct {
+	s *Server
+}
+
+func gqlUserID(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
+
+func parseGQLID(id graphql.ID) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, userErrorf("invalid id %q", id)
+	}
+	return n, nil
+}
+
+// ─── Query ────────────────────────────────────────────────────────────────────
+
+type eventsArgs struct {
+	Q         *string
+	Location  *string
+	Source    *string
+	From      *string
+	To        *string
+	Tech      *[]string
+	Price     *[]string
+	Organizer *[]string
+	EventType *[]string
+	Lat       *float64
+	Lng       *float64
+	RadiusKm  *float64
+	Sort      *string
+	After     *string
+	Page      int32
+	Limit     int32
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// gqlNearValues feeds the point arguments through parseNearFilter so both
+// APIs validate them the same way.
+func gqlNearValues(args eventsArgs) url.Values {
+	v := url.Values{}
+	for name, f := range map[string]*float64{"lat": args.Lat, "lng": args.Lng, "radius_km": args.RadiusKm} {
+		if f != nil {
+			v.Set(name, strconv.FormatFloat(*f, 'f', -1, 64))
+		}
+	}
+	return v
+}
+
+func derefList(s *[]string) []string {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func (q *gqlRoot) Events(ctx context.Context, args eventsArgs) (*eventPageResolver, error) {
+	page, limit := 1, 8
+	if args.Page > 1 {
+		page = int(args.Page)
+	}
+	if args.Limit >= 1 && args.Limit <= 100 {
+		limit = int(args.Limit)
+	}
+	if err := chargeComplexity(ctx, 1, map[string]int{"events": limit}); err != nil {
+		return nil, err
+	}
+
+	filter := EventFilter{
+		Search:    deref(args.Q),
+		Location:  deref(args.Location),
+		Source:    deref(args.Source),
+		DateFrom:  deref(args.From),
+		DateTo:    deref(args.To),
+		Tech:      derefList(args.Tech),
+		Organizer: derefList(args.Organizer),
+		EventType: derefList(args.EventType),
+	}
+	price, err := parsePriceFilter(derefList(args.Price))
+	if err != nil {
+		return nil, userErrorf("%v", err)
+	}
+	filter.Price = price
+	if filter.Near, err = parseNearFilter(gqlNearValues(args)); err != nil {
+		return nil, userErrorf("%v", err)
+	}
+	sort, err := parseEventSort(deref(args.Sort))
+	if err != nil {
+		return nil, userErrorf("%v", err)
+	}
+	cursor := deref(args.After)
+	result, err := q.s.listEvents(EventQuery{
+		Filter: filter,
+		Sort:   sort,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+		Cursor: cursor,
+	})
+	if errors.Is(err, ErrBadCursor) {
+		return nil, userErrorf("invalid cursor")
+	}
+	if errors.Is(err, ErrDistanceSort) {
+		return nil, userErrorf("%v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	total := result.Total
+	if cursor != "" {
+		page = 0
+	}
+
+	totalPages := (total + limit - 1) / limit
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	return &eventPageResolver{
+		events:     newEventBatch(q.s, gqlUserID(ctx), result.Events).resolvers(),
+		total:      int32(total),
+		page:       int32(page),
+		limit:      int32(limit),
+		totalPages: int32(totalPages),
+		sort:       result.Sort,
+		nextCursor: result.NextCursor,
+		s:          q.s,
+		filter:     filter,
+	}, nil
+}
+
+func (q *gqlRoot) Event(ctx context.Context, args struct{ ID graphql.ID }) (*eventResolver, error) {
+	id, err := parseGQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, 1, nil); err != nil {
+		return nil, err
+	}
+
+	events, err := q.s.loadEventsByID([]int64{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return newEventBatch(q.s, gqlUserID(ctx), events).resolvers()[0], nil
+}
+
+// savedEventsCap bounds the cost estimate for savedEvents, which is unpaged.
+const savedEventsCap = 50
+
+func (q *gqlRoot) SavedEvents(ctx context.Context) ([]*savedEventResolver, error) {
+	userID := gqlUserID(ctx)
+	if userID == "" {
+		return nil, userErrorf("unauthorized")
+	}
+	if !scopeAllowed(ctx, apikeys.ScopeSaved) {
+		return nil, userErrorf("forbidden: the API key lacks the %s scope", apikeys.ScopeSaved)
+	}
+	if err := chargeComplexity(ctx, savedEventsCap, nil); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.s.db.Query(`
+		SELECT se.id, se.event_id, COALESCE(se.notes, ''), se.status, se.saved_at
+		FROM saved_events se
+		WHERE se.user_id = $1
+		ORDER BY se.saved_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var saved []*savedEventResolver
+	var ids []int64
+	for rows.Next() {
+		se := &savedEventResolver{}
+		if err := rows.Scan(&se.id, &se.eventID, &se.notes, &se.status, &se.savedAt); err != nil {
+			continue
+		}
+		saved = append(saved, se)
+		ids = append(ids, se.eventID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return q.s.attachSavedEvents(userID, saved, ids)
+}
+
+func (q *gqlRoot) ScraperRuns(ctx context.Context, args struct {
+	Scraper *string
+	Limit   int32
+}) ([]*scraperRunResolver, error) {
+	if !roleFrom(ctx).Includes(roles.Admin) {
+		return nil, userErrorf("forbidden")
+	}
+	limit := 20
+	if args.Limit >= 1 && args.Limit <= 200 {
+		limit = int(args.Limit)
+	}
+	if err := chargeComplexity(ctx, limit, nil); err != nil {
+		return nil, err
+	}
+
+	rows, err := q.s.db.Query(`
+		SELECT id, scraper_name, success, COALESCE(events_found, 0), COALESCE(events_filtered, 0),
+		       COALESCE(error_message, ''), COALESCE(duration_seconds, 0), run_at
+		FROM scraper_runs
+		WHERE $1 = '' OR scraper_name = $1
+		ORDER BY run_at DESC
+		LIMIT $2
+	`, deref(args.Scraper), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []*scraperRunResolver{}
+	for rows.Next() {
+		run, err := scanScraperRun(rows)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (q *gqlRoot) Filters(ctx context.Context) (*filtersResolver, error) {
+	if err := chargeComplexity(ctx, 1, nil); err != nil {
+		return nil, err
+	}
+	return &filtersResolver{facets: q.s.eventFacets(EventFilter{})}, nil
+}
+
+// ─── Mutation ─────────────────────────────────────────────────────────────────
+
+func (q *gqlRoot) SaveEvent(ctx context.Context, args struct {
+	ID    graphql.ID
+	Notes *string
+}) (*savedEventResolver, error) {
+	userID := gqlUserID(ctx)
+	if userID == "" {
+		return nil, userErrorf("unauthorized")
+	}
+	if !scopeAllowed(ctx, apikeys.ScopeSaved) {
+		return nil, userErrorf("forbidden: the API key lacks the %s scope", apikeys.ScopeSaved)
+	}
+	eventID, err := parseGQLID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := chargeComplexity(ctx, 1, nil); err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	q.s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM events WHERE id = $1)", eventID).Scan(&exists)
+	if !exists {
+		return nil, userErrorf("event not found")
+	}
+	id, err := q.s.saveEvent(userID, eventID, deref(args.Notes), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	se := &savedEventResolver{id: id, eventID: eventID, notes: deref(args.Notes), savedAt: time.Now()}
+	if err := q.s.db.QueryRow(`SELECT status FROM saved_events WHERE id = $1`, id).Scan(&se.status); err != nil {
+		return nil, err
+	}
+	saved, err := q.s.attachSavedEvents(userID, []*savedEventResolver{se}, []int64{eventID})
+	if err != nil {
+		return nil, err
+	}
+	return saved[0], nil
+}
+
+func (q *gqlRoot) UnsaveEvent(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	userID := gqlUserID(ctx)
+	if userID == "" {
+		return false, userErrorf("unauthorized")
+	}
+	if !scopeAllowed(ctx, apikeys.ScopeSaved) {
+		return false, userErrorf("forbidden: the API key lacks the %s scope", apikeys.ScopeSaved)
+	}
+	eventID, err := parseGQLID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	res, err := q.s.db.Exec(`DELETE FROM saved_events WHERE user_id = $1 AND event_id = $2`, userID, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// attachSavedEvents loads the events behind saved rows as one batch.
+func (s *Server) attachSavedEvents(userID string, saved []*savedEventResolver, ids []int64) ([]*savedEventResolver, error) {
+	out := []*savedEventResolver{}
+	if len(ids) == 0 {
+		return out, nil
+	}
+	events, err := s.loadEventsByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*eventResolver)
+	for _, r := range newEventBatch(s, userID, events).resolvers() {
+		byID[int64(r.e.ID)] = r
+	}
+	for _, se := range saved {
+		if ev, ok := byID[se.eventID]; ok {
+			se.event = ev
+			out = append(out, se)
+		}
+	}
+	return out, nil
+}
+
+// ─── Batch loading ────────────────────────────────────────────────────────────
+
+// eventBatch is the set of sibling events in one list of a response. The
+// first resolver that needs a related record loads it for every sibling with a
+// single query, so a page of N events costs one query per nested field rather
+// than N.
+type eventBatch struct {
+	s      *Server
+	userID string
+	events []Event
+	ids    []int64
+
+	mu              sync.Mutex
+	details         map[int64]*EventDetail
+	cleaned         map[int64]*cleanedEvent
+	saved           map[int64]bool
+	recommendations map[int32]map[int64][]*eventResolver
+	runs            map[int32]map[string][]*scraperRunResolver
+}
+
+func newEventBatch(s *Server, userID string, events []Event) *eventBatch {
+	b := &eventBatch{s: s, userID: userID, events: events}
+	for _, e := range events {
+		b.ids = append(b.ids, int64(e.ID))
+	}
+	return b
+}
+
+func (b *eventBatch) resolvers() []*eventResolver {
+	out := make([]*eventResolver, len(b.events))
+	for i := range b.events {
+		out[i] = &eventResolver{e: &b.events[i], batch: b}
+	}
+	return out
+}
+
+func (b *eventBatch) loadDetails() (map[int64]*EventDetail, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.details != nil {
+		return b.details, nil
+	}
+
+	rows, err := b.s.db.Query(`
+		SELECT id, event_id, COALESCE(full_description, ''), COALESCE(organizer, ''),
+		       COALESCE(organizer_contact, ''), COALESCE(image_url, ''), COALESCE(tags, ''),
+		       COALESCE(price, ''), COALESCE(registration_url, ''), COALESCE(duration, ''),
+		       COALESCE(agenda_html, ''), COALESCE(speakers_json, ''), COALESCE(prerequisites, ''),
+		       COALESCE(max_attendees, 0), COALESCE(attendees_count, 0)
+		FROM event_details WHERE event_id = ANY($1)
+	`, pq.Array(b.ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	details := make(map[int64]*EventDetail)
+	for rows.Next() {
+		var d EventDetail
+		if err := rows.Scan(
+			&d.ID, &d.EventID, &d.FullDescription, &d.Organizer,
+			&d.OrganizerContact, &d.ImageURL, &d.Tags, &d.Price,
+			&d.RegistrationURL, &d.Duration, &d.AgendaHTML, &d.SpeakersJSON,
+			&d.Prerequisites, &d.MaxAttendees, &d.AttendeesCount,
+		); err != nil {
+			continue
+		}
+		details[d.EventID] = &d
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	b.details = details
+	return details, nil
+}
+
+type cleanedEvent struct {
+	title, description, date, time, location, address string
+	techStack, speakers, highlights                   []string
+	organizer, price, summary                         string
+	confidence                                        int32
+}
+
+func (b *eventBatch) loadCleaned() (map[int64]*cleanedEvent, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cleaned != nil {
+		return b.cleaned, nil
+	}
+
+	rows, err := b.s.db.Query(`
+		SELECT event_id, COALESCE(title_clean, ''), COALESCE(description_clean, ''),
+		       COALESCE(date_clean, ''), COALESCE(time_clean, ''),
+		       COALESCE(location_clean, ''), COALESCE(address_clean, ''),
+		       COALESCE(tech_stack, '{}'), COALESCE(speakers, '{}'),
+		       COALESCE(organizer, ''), COALESCE(price, ''), COALESCE(confidence, 0),
+		       COALESCE(summary, ''), COALESCE(highlights, '{}')
+		FROM event_cleaned WHERE event_id = ANY($1)
+	`, pq.Array(b.ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cleaned := make(map[int64]*cleanedEvent)
+	for rows.Next() {
+		var id int64
+		var c cleanedEvent
+		if err := rows.Scan(&id, &c.title, &c.description, &c.date, &c.time,
+			&c.location, &c.address, pq.Array(&c.techStack), pq.Array(&c.speakers),
+			&c.organizer, &c.price, &c.confidence, &c.summary, pq.Array(&c.highlights),
+		); err != nil {
+			continue
+		}
+		cleaned[id] = &c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	b.cleaned = cleaned
+	return cleaned, nil
+}
+
+func (b *eventBatch) loadSaved() (map[int64]bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.saved != nil {
+		return b.saved, nil
+	}
+
+	saved := make(map[int64]bool)
+	if b.userID != "" {
+		rows, err := b.s.db.Query(`
+			SELECT event_id FROM saved_events WHERE user_id = $1 AND event_id = ANY($2)
+		`, b.userID, pq.Array(b.ids))
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err == nil {
+				saved[id] = true
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	b.saved = saved
+	return saved, nil
+}
+
+// loadRecommendations mirrors GET /api/v1/events/{id}/recommended for every event
+// of the batch. The recommended events of all siblings form one new batch, so
+// their own nested fields are batched as well.
+func (b *eventBatch) loadRecommendations(limit int32) (map[int64][]*eventResolver, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if recs, ok := b.recommendations[limit]; ok {
+		return recs, nil
+	}
+
+	similarByID, err := b.s.similarEventsOf(b.ids, int(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var all []Event
+	var owners []int64
+	for _, id := range b.ids {
+		for _, e := range similarByID[id] {
+			all = append(all, e.Event)
+			owners = append(owners, id)
+		}
+	}
+
+	// One shared batch for every recommended event, so their own nested
+	// fields load together too.
+	shared := newEventBatch(b.s, b.userID, all)
+	recs := make(map[int64][]*eventResolver)
+	for i, r := range shared.resolvers() {
+		recs[owners[i]] = append(recs[owners[i]], r)
+	}
+
+	if b.recommendations == nil {
+		b.recommendations = make(map[int32]map[int64][]*eventResolver)
+	}
+	b.recommendations[limit] = recs
+	return recs, nil
+}
+
+// loadRuns returns recent scraper runs for every platform in the batch.
+// Scraper names start with their platform, e.g. "hitex (python)".
+func (b *eventBatch) loadRuns(limit int32) (map[string][]*scraperRunResolver, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if runs, ok := b.runs[limit]; ok {
+		return runs, nil
+	}
+
+	seen := make(map[string]bool)
+	var platforms []string
+	for _, e := range b.events {
+		if e.Platform != "" && !seen[e.Platform] {
+			seen[e.Platform] = true
+			platforms = append(platforms, e.Platform)
+		}
+	}
+
+	runs := make(map[string][]*scraperRunResolver)
+	if len(platforms) > 0 {
+		rows, err := b.s.db.Query(`
+			SELECT p.platform, r.id, r.scraper_name, r.success,
+			       COALESCE(r.events_found, 0), COALESCE(r.events_filtered, 0),
+			       COALESCE(r.error_message, ''), COALESCE(r.duration_seconds, 0), r.run_at
+			FROM unnest($1::text[]) AS p(platform)
+			CROSS JOIN LATERAL (
+				SELECT * FROM scraper_runs
+				WHERE scraper_name ILIKE p.platform || '%'
+				ORDER BY run_at DESC
+				LIMIT $2
+			) r
+		`, pq.Array(platforms), limit)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var platform string
+			run, err := scanScraperRun(prefixScanner{rows, []interface{}{&platform}})
+			if err != nil {
+				continue
+			}
+			runs[platform] = append(runs[platform], run)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.runs == nil {
+		b.runs = make(map[int32]map[string][]*scraperRunResolver)
+	}
+	b.runs[limit] = runs
+	return runs, nil
+}
+
+// loadEventsByID returns the events with the given IDs in the order given,
+// skipping IDs that do not exist.
+func (s *Server) loadEventsByID(ids []int64) ([]Event, error) {
+	rows, err := s.db.Query(`
+		SELECT `+cleanedEventCols+`
+		FROM events e
+		LEFT JOIN event_details ed ON e.id = ed.event_id
+		LEFT JOIN event_cleaned ec ON e.id = ec.event_id
+		LEFT JOIN event_geo eg ON e.id = eg.event_id
+		WHERE e.id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]Event)
+	for rows.Next() {
+		var e Event
+		if err := scanCleanedEvent(rows, &e); err != nil {
+			continue
+		}
+		byID[int64(e.ID)] = e
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	events := []Event{}
+	for _, id := range ids {
+		if e, ok := byID[id]; ok {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// prefixScanner lets a shared row scanner read rows that carry extra leading
+// columns.
+type prefixScanner struct {
+	row interface {
+		Scan(...interface{}) error
+	}
+	prefix []interface{}
+}
+
+func (p prefixScanner) Scan(dest ...interface{}) error {
+	return p.row.Scan(append(append([]interface{}{}, p.prefix...), dest...)...)
+}
+
+// ─── Resolvers ────────────────────────────────────────────────────────────────
+
+type eventPageResolver struct {
+	events                         []*eventResolver
+	total, page, limit, totalPages int32
+	sort, nextCursor               string
+	// Facets are only counted when selected.
+	s      *Server
+	filter EventFilter
+}
+
+func (r *eventPageResolver) Events() []*eventResolver { return r.events }
+func (r *eventPageResolver) Total() int32             { return r.total }
+func (r *eventPageResolver) Page() int32              { return r.page }
+func (r *eventPageResolver) Limit() int32             { return r.limit }
+func (r *eventPageResolver) TotalPages() int32        { return r.totalPages }
+func (r *eventPageResolver) Sort() string             { return r.sort }
+func (r *eventPageResolver) NextCursor() *string {
+	if r.nextCursor == "" {
+		return nil
+	}
+	return &r.nextCursor
+}
+
+func (r *eventPageResolver) Facets() *facetsResolver {
+	return &facetsResolver{r.s.eventFacets(r.filter)}
+}
+
+type filtersResolver struct {
+	facets *Facets
+}
+
+func (r *filtersResolver) Locations() []string     { return facetValues(r.facets.Location) }
+func (r *filtersResolver) Sources() []string       { return facetValues(r.facets.Source) }
+func (r *filtersResolver) Facets() *facetsResolver { return &facetsResolver{r.facets} }
+
+type facetsResolver struct {
+	f *Facets
+}
+
+func (r *facetsResolver) Location() []*facetCountResolver  { return facetCounts(r.f.Location) }
+func (r *facetsResolver) Source() []*facetCountResolver    { return facetCounts(r.f.Source) }
+func (r *facetsResolver) Tech() []*facetCountResolver      { return facetCounts(r.f.Tech) }
+func (r *facetsResolver) Price() []*facetCountResolver     { return facetCounts(r.f.Price) }
+func (r *facetsResolver) Organizer() []*facetCountResolver { return facetCounts(r.f.Organizer) }
+func (r *facetsResolver) EventType() []*facetCountResolver { return facetCounts(r.f.EventType) }
+
+type facetCountResolver struct {
+	c FacetCount
+}
+
+func (r *facetCountResolver) Value() string { return r.c.Value }
+func (r *facetCountResolver) Count() int32  { return int32(r.c.Count) }
+
+func facetCounts(counts []FacetCount) []*facetCountResolver {
+	out := make([]*facetCountResolver, len(counts))
+	for i, c := range counts {
+		out[i] = &facetCountResolver{c}
+	}
+	return out
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+type eventResolver struct {
+	e     *Event
+	batch *eventBatch
+}
+
+func (r *eventResolver) ID() graphql.ID      { return graphql.ID(strconv.Itoa(r.e.ID)) }
+func (r *eventResolver) Name() string        { return r.e.EventName }
+func (r *eventResolver) Location() string    { return r.e.Location }
+func (r *eventResolver) City() string        { return r.e.CityNormalized }
+func (r *eventResolver) DateTime() string    { return r.e.DateTime }
+func (r *eventResolver) Date() string        { return r.e.Date }
+func (r *eventResolver) Time() string        { return r.e.Time }
+func (r *eventResolver) Website() string     { return r.e.Website }
+func (r *eventResolver) Description() string { return r.e.Description }
+func (r *eventResolver) Address() string     { return r.e.Address }
+func (r *eventResolver) EventType() string   { return r.e.EventType }
+func (r *eventResolver) Platform() string    { return r.e.Platform }
+func (r *eventResolver) ImageURL() string    { return r.e.ImageURL }
+func (r *eventResolver) CreatedAt() string   { return r.e.CreatedAt.Format(time.RFC3339) }
+
+func (r *eventResolver) Lat() *float64        { return r.e.Lat }
+func (r *eventResolver) Lng() *float64        { return r.e.Lng }
+func (r *eventResolver) DistanceKm() *float64 { return r.e.DistanceKm }
+func (r *eventResolver) GeoConfidence() *string {
+	if r.e.GeoConfidence == "" {
+		return nil
+	}
+	return &r.e.GeoConfidence
+}
+
+func (r *eventResolver) Cleaned() (*cleanedEventResolver, error) {
+	cleaned, err := r.batch.loadCleaned()
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := cleaned[int64(r.e.ID)]; ok {
+		return &cleanedEventResolver{c}, nil
+	}
+	return nil, nil
+}
+
+func (r *eventResolver) Detail() (*eventDetailResolver, error) {
+	details, err := r.batch.loadDetails()
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := details[int64(r.e.ID)]; ok {
+		return &eventDetailResolver{d}, nil
+	}
+	return nil, nil
+}
+
+func (r *eventResolver) Saved() (bool, error) {
+	saved, err := r.batch.loadSaved()
+	if err != nil {
+		return false, err
+	}
+	return saved[int64(r.e.ID)], nil
+}
+
+func (r *eventResolver) Recommendations(args struct{ Limit int32 }) ([]*eventResolver, error) {
+	limit := int32(10)
+	if args.Limit >= 1 && args.Limit <= 20 {
+		limit = args.Limit
+	}
+	recs, err := r.batch.loadRecommendations(limit)
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := recs[int64(r.e.ID)]; ok {
+		return list, nil
+	}
+	return []*eventResolver{}, nil
+}
+
+func (r *eventResolver) ScraperRuns(ctx context.Context, args struct{ Limit int32 }) ([]*scraperRunResolver, error) {
+	if !roleFrom(ctx).Includes(roles.Admin) {
+		return nil, userErrorf("forbidden")
+	}
+	limit := int32(5)
+	if args.Limit >= 1 && args.Limit <= 50 {
+		limit = args.Limit
+	}
+	runs, err := r.batch.loadRuns(limit)
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := runs[r.e.Platform]; ok {
+		return list, nil
+	}
+	return []*scraperRunResolver{}, nil
+}
+
+type cleanedEventResolver struct{ c *cleanedEvent }
+
+func (r *cleanedEventResolver) Title() string        { return r.c.title }
+func (r *cleanedEventResolver) Description() string  { return r.c.description }
+func (r *cleanedEventResolver) Date() string         { return r.c.date }
+func (r *cleanedEventResolver) Time() string         { return r.c.time }
+func (r *cleanedEventResolver) Location() string     { return r.c.location }
+func (r *cleanedEventResolver) Address() string      { return r.c.address }
+func (r *cleanedEventResolver) TechStack() []string  { return nonNil(r.c.techStack) }
+func (r *cleanedEventResolver) Speakers() []string   { return nonNil(r.c.speakers) }
+func (r *cleanedEventResolver) Organizer() string    { return r.c.organizer }
+func (r *cleanedEventResolver) Price() string        { return r.c.price }
+func (r *cleanedEventResolver) Confidence() int32    { return r.c.confidence }
+func (r *cleanedEventResolver) Summary() string      { return r.c.summary }
+func (r *cleanedEventResolver) Highlights() []string { return nonNil(r.c.highlights) }
+
+type eventDetailResolver struct{ d *EventDetail }
+
+func (r *eventDetailResolver) FullDescription() string  { return r.d.FullDescription }
+func (r *eventDetailResolver) Organizer() string        { return r.d.Organizer }
+func (r *eventDetailResolver) OrganizerContact() string { return r.d.OrganizerContact }
+func (r *eventDetailResolver) ImageURL() string         { return r.d.ImageURL }
+func (r *eventDetailResolver) Tags() string             { return r.d.Tags }
+func (r *eventDetailResolver) Price() string            { return r.d.Price }
+func (r *eventDetailResolver) RegistrationURL() string  { return r.d.RegistrationURL }
+func (r *eventDetailResolver) Duration() string         { return r.d.Duration }
+func (r *eventDetailResolver) AgendaHTML() string       { return r.d.AgendaHTML }
+func (r *eventDetailResolver) SpeakersJSON() string     { return r.d.SpeakersJSON }
+func (r *eventDetailResolver) Prerequisites() string    { return r.d.Prerequisites }
+func (r *eventDetailResolver) MaxAttendees() int32      { return int32(r.d.MaxAttendees) }
+func (r *eventDetailResolver) AttendeesCount() int32    { return int32(r.d.AttendeesCount) }
+
+type savedEventResolver struct {
+	id      int64
+	eventID int64
+	notes   string
+	status  string
+	savedAt time.Time
+	event   *eventResolver
+}
+
+func (r *savedEventResolver) ID() graphql.ID        { return graphql.ID(strconv.FormatInt(r.id, 10)) }
+func (r *savedEventResolver) Notes() string         { return r.notes }
+func (r *savedEventResolver) Status() string        { return r.status }
+func (r *savedEventResolver) SavedAt() string       { return r.savedAt.Format(time.RFC3339) }
+func (r *savedEventResolver) Event() *eventResolver { return r.event }
+
+type scraperRunResolver struct {
+	id              int64
+	scraper         string
+	success         bool
+	eventsFound     int32
+	eventsFiltered  int32
+	errMsg          string
+	durationSeconds float64
+	runAt           time.Time
+}
+
+func scanScraperRun(row interface {
+	Scan(...interface{}) error
+}) (*scraperRunResolver, error) {
+	var r scraperRunResolver
+	err := row.Scan(&r.id, &r.scraper, &r.success, &r.eventsFound, &r.eventsFiltered,
+		&r.errMsg, &r.durationSeconds, &r.runAt)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r *scraperRunResolver) ID() graphql.ID           { return graphql.ID(strconv.FormatInt(r.id, 10)) }
+func (r *scraperRunResolver) Scraper() string          { return r.scraper }
+func (r *scraperRunResolver) Success() bool            { return r.success }
+func (r *scraperRunResolver) EventsFound() int32       { return r.eventsFound }
+func (r *scraperRunResolver) EventsFiltered() int32    { return r.eventsFiltered }
+func (r *scraperRunResolver) Error() string            { return r.errMsg }
+func (r *scraperRunResolver) DurationSeconds() float64 { return r.durationSeconds }
+func (r *scraperRunResolver) RunAt() string            { return r.runAt.Format(time.RFC3339) }
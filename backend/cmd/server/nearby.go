@@ -0,0 +1,69 @@
+// backend/cmd/server/nearby.go
+package main
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"event-scraper/internal/geo"
+)
+
+// ─── Radius search ────────────────────────────────────────────────────────────
+//
+// /api/v1/events?lat=12.97&lng=77.75&radius_km=5 keeps events placed within
+// radius_km of the point by internal/geo and sorts them nearest first.
+// Online events and events the gazetteer could not place never match.
+
+const (
+	defaultRadiusKm = 10.0
+	maxRadiusKm     = 500.0
+)
+
+// NearFilter restricts events to a circle. The zero value matches all.
+type NearFilter struct {
+	Lat, Lng float64
+	RadiusKm float64
+}
+
+func (n NearFilter) active() bool { return n.RadiusKm > 0 }
+
+// box is the half-size in degrees of a square around the circle.
+func (n NearFilter) box() (dLat, dLng float64) {
+	dLat = n.RadiusKm / geo.EarthRadiusKm * 180 / math.Pi
+	cos := math.Cos(n.Lat * math.Pi / 180)
+	if cos < 0.01 {
+		return dLat, 180
+	}
+	return dLat, math.Min(180, dLat/cos)
+}
+
+// parseNearFilter reads lat, lng and radius_km. lat and lng go together;
+// radius_km defaults to defaultRadiusKm.
+func parseNearFilter(q url.Values) (NearFilter, error) {
+	rawLat, rawLng := strings.TrimSpace(q.Get("lat")), strings.TrimSpace(q.Get("lng"))
+	rawRadius := strings.TrimSpace(q.Get("radius_km"))
+	if rawLat == "" && rawLng == "" {
+		if rawRadius != "" {
+			return NearFilter{}, errors.New("radius_km needs lat and lng")
+		}
+		return NearFilter{}, nil
+	}
+
+	n := NearFilter{RadiusKm: defaultRadiusKm}
+	var err error
+	if n.Lat, err = strconv.ParseFloat(rawLat, 64); err != nil || !(math.Abs(n.Lat) <= 90) {
+		return NearFilter{}, errors.New("lat must be a number between -90 and 90")
+	}
+	if n.Lng, err = strconv.ParseFloat(rawLng, 64); err != nil || !(math.Abs(n.Lng) <= 180) {
+		return NearFilter{}, errors.New("lng must be a number between -180 and 180")
+	}
+	if rawRadius != "" {
+		if n.RadiusKm, err = strconv.ParseFloat(rawRadius, 64); err != nil || !(n.RadiusKm > 0 && n.RadiusKm <= maxRadiusKm) {
+			return NearFilter{}, errors.New("radius_km must be greater than 0 and at most 500")
+		}
+	}
+	return n, nil
+}
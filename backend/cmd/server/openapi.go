@@ -0,0 +1,23 @@
+// backend/cmd/server/openapi.go
+package main
+
+import (
+	"net/http"
+
+	"event-scraper/api"
+)
+
+// GET /api/v1/openapi.json
+//
+// The OpenAPI 3 document from api/openapi.yaml. pkg/client is generated from
+// the same file.
+func handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	body, err := api.JSON()
+	if err != nil {
+		serverError(w, "OpenAPI spec unavailable", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Write(body)
+}
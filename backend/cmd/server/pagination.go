@@ -0,0 +1,340 @@
+// backend/cmd/server/pagination.go
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"event-scraper/internal/geo"
+)
+
+// ─── Event listing ────────────────────────────────────────────────────────────
+//
+// /api/v1/events pages with an opaque cursor: each response carries a
+// next_cursor holding the sort key of its last row, and the next request
+// continues strictly after it. Events inserted by a scrape cycle never shift
+// rows between pages, and deep pages cost the same as the first one.
+// ?page= (LIMIT/OFFSET) still works for older clients.
+
+const (
+	SortPlatform  = "platform" // interleave platforms, soonest first within each (default)
+	SortSoonest   = "soonest"
+	SortNewest    = "newest"
+	SortRelevance = "relevance" // by match strength of ?q=, then soonest
+	SortDistance  = "distance"  // nearest first; needs lat and lng (default when given)
+	SortTrending  = "trending"  // most popular first (see internal/popularity), then soonest
+)
+
+var eventSorts = map[string]bool{
+	SortPlatform: true, SortSoonest: true, SortNewest: true, SortRelevance: true, SortDistance: true,
+	SortTrending: true,
+}
+
+// ErrDistanceSort is returned for sort=distance without a point.
+var ErrDistanceSort = errors.New("sort=distance needs lat and lng")
+
+// ErrBadCursor is returned for a cursor that cannot be decoded or was issued
+// for a different filter or sort.
+var ErrBadCursor = errors.New("invalid cursor")
+
+// EventQuery is one page request over /api/v1/events.
+type EventQuery struct {
+	Filter EventFilter
+	Sort   string
+	Limit  int
+	// Either Offset (legacy ?page=) or Cursor is used; Cursor wins.
+	Offset int
+	Cursor string
+}
+
+// EventPage is one page of events. NextCursor is empty on the last page.
+type EventPage struct {
+	Events     []Event
+	Total      int
+	Sort       string
+	NextCursor string
+}
+
+// eventCursor is the decoded form of a next_cursor. Key fields hold the sort
+// key of the last row returned; Platforms holds, for the platform sort, the
+// last row and rank reached in every platform seen so far.
+type eventCursor struct {
+	Sort       string                 `json:"s"`
+	Filter     string                 `json:"f"`
+	Date       string                 `json:"d,omitempty"`
+	Time       time.Time              `json:"t,omitempty"`
+	ID         int64                  `json:"i,omitempty"`
+	Score      int                    `json:"r,omitempty"`
+	Distance   float64                `json:"k,omitempty"`
+	Popularity float64                `json:"y,omitempty"`
+	Platforms  map[string]platformPos `json:"p,omitempty"`
+}
+
+type platformPos struct {
+	Date string    `json:"d"`
+	Time time.Time `json:"t"`
+	ID   int64     `json:"i"`
+	Rank int64     `json:"n"`
+}
+
+func encodeCursor(c eventCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*eventCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadCursor
+	}
+	var c eventCursor
+	if err := json.Unmarshal(b, &c); err != nil || !eventSorts[c.Sort] {
+		return nil, ErrBadCursor
+	}
+	return &c, nil
+}
+
+// fingerprint identifies a filter so a cursor cannot be replayed against
+// different filters, which would silently skip or repeat rows.
+func (f EventFilter) fingerprint() string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%#v", f)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Sort keys over the events alias inner_e. Undated events sort last.
+const (
+	sortDateExpr = `(CASE WHEN inner_e.date ~ '^\d{4}-\d{2}-\d{2}$'
+		THEN inner_e.date::date ELSE DATE '9999-12-31' END)`
+	// Within a platform: soonest first, then most recently scraped.
+	platformOrder = sortDateExpr + ` ASC, inner_e.created_at DESC, inner_e.id DESC`
+	// Popularity as of the last rebuild; 0 for events nobody looked at.
+	popularityExpr = `COALESCE((SELECT p.score FROM event_popularity p WHERE p.event_id = inner_e.id), 0)`
+)
+
+// scoreExpr ranks a search match: title beats location beats description.
+func scoreExpr(idx int) string {
+	return fmt.Sprintf(`(CASE WHEN inner_e.event_name ILIKE $%[1]d THEN 3 ELSE 0 END +
+		CASE WHEN inner_e.location ILIKE $%[1]d THEN 2 ELSE 0 END +
+		CASE WHEN inner_e.description ILIKE $%[1]d THEN 1 ELSE 0 END)`, idx)
+}
+
+// listEvents returns one page of events matching q.Filter in q.Sort order,
+// together with the total number of matches.
+func (s *Server) listEvents(q EventQuery) (*EventPage, error) {
+	fp := q.Filter.fingerprint()
+
+	var cur *eventCursor
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if q.Sort == "" {
+			q.Sort = c.Sort
+		}
+		if c.Filter != fp || c.Sort != q.Sort {
+			return nil, ErrBadCursor
+		}
+		cur, q.Offset = c, 0
+	}
+	if q.Sort == "" {
+		q.Sort = SortPlatform
+		if q.Filter.Near.active() {
+			q.Sort = SortDistance
+		}
+	}
+	if q.Sort == SortDistance && !q.Filter.Near.active() {
+		return nil, ErrDistanceSort
+	}
+
+	where, args := q.Filter.where()
+
+	var total int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM events inner_e "+where, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("count events: %w", err)
+	}
+
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	score := "0"
+	if q.Filter.Search != "" {
+		score = scoreExpr(len(args) + 1)
+		args = append(args, "%"+q.Filter.Search+"%")
+	}
+	rank := "0"
+	popularity := "0::float8"
+	from := "events inner_e"
+	distance := "NULL::float8"
+	if q.Filter.Near.active() {
+		distance = fmt.Sprintf("(SELECT %s FROM event_geo g_d WHERE g_d.event_id = inner_e.id)",
+			geo.DistanceSQL("g_d.lat", "g_d.lng", arg(q.Filter.Near.Lat)+"::float8", arg(q.Filter.Near.Lng)+"::float8"))
+	}
+
+	var orderBy string
+	switch q.Sort {
+	case SortSoonest:
+		orderBy = "e.sort_date ASC, e.id ASC"
+		if cur != nil {
+			where += fmt.Sprintf(" AND (%s, inner_e.id) > (%s::date, %s)", sortDateExpr, arg(cur.Date), arg(cur.ID))
+		}
+	case SortNewest:
+		orderBy = "e.created_at DESC, e.id DESC"
+		if cur != nil {
+			where += fmt.Sprintf(" AND (inner_e.created_at, inner_e.id) < (%s, %s)", arg(cur.Time), arg(cur.ID))
+		}
+	case SortDistance:
+		orderBy = "e.distance_km ASC, e.id ASC"
+		if cur != nil {
+			where += fmt.Sprintf(" AND (%s, inner_e.id) > (%s::float8, %s)", distance, arg(cur.Distance), arg(cur.ID))
+		}
+	case SortTrending:
+		popularity = popularityExpr
+		orderBy = "e.popularity DESC, e.sort_date ASC, e.id ASC"
+		if cur != nil {
+			where += fmt.Sprintf(" AND (-%s, %s, inner_e.id) > (%s::float8, %s::date, %s)",
+				popularity, sortDateExpr, arg(-cur.Popularity), arg(cur.Date), arg(cur.ID))
+		}
+	case SortRelevance:
+		orderBy = "e.score DESC, e.sort_date ASC, e.id ASC"
+		if cur != nil {
+			where += fmt.Sprintf(" AND (-%s, %s, inner_e.id) > (%s, %s::date, %s)",
+				score, sortDateExpr, arg(-cur.Score), arg(cur.Date), arg(cur.ID))
+		}
+	default:
+		// Round-robin across platforms: every platform's first event, then
+		// every platform's second, and so on. The cursor remembers where each
+		// platform stopped and continues its ranks from there.
+		orderBy = "e.platform_rank ASC, e.sort_date ASC, e.platform ASC"
+		rank = "ROW_NUMBER() OVER (PARTITION BY inner_e.platform ORDER BY " + platformOrder + ")"
+		if cur != nil {
+			pos, _ := json.Marshal(platformRows(cur.Platforms))
+			from = "events inner_e LEFT JOIN jsonb_to_recordset(" + arg(string(pos)) + `::jsonb)
+				AS c(platform text, d date, t timestamptz, i bigint, n bigint) ON c.platform = inner_e.platform`
+			where += fmt.Sprintf(` AND (c.platform IS NULL OR %[1]s > c.d
+				OR (%[1]s = c.d AND (inner_e.created_at, inner_e.id) < (c.t, c.i)))`, sortDateExpr)
+			rank = "COALESCE(c.n, 0) + " + rank
+		}
+	}
+
+	// One extra row tells us whether there is a next page.
+	query := fmt.Sprintf(`
+		SELECT %s,
+		       to_char(e.sort_date, 'YYYY-MM-DD'), e.score, e.platform_rank, e.distance_km, e.popularity
+		FROM (
+			SELECT inner_e.*,
+			       %s AS sort_date,
+			       %s AS score,
+			       %s AS platform_rank,
+			       %s AS distance_km,
+			       %s AS popularity
+			FROM %s
+			%s
+		) e
+		LEFT JOIN event_details ed ON e.id = ed.event_id
+		LEFT JOIN event_cleaned ec ON e.id = ec.event_id
+		LEFT JOIN event_geo eg ON e.id = eg.event_id
+		ORDER BY %s
+		LIMIT %s OFFSET %s
+	`, cleanedEventCols, sortDateExpr, score, rank, distance, popularity, from, where, orderBy, arg(q.Limit+1), arg(q.Offset))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type keyed struct {
+		date       string
+		score      int
+		rank       int64
+		popularity float64
+	}
+	events := []Event{}
+	keys := []keyed{}
+	for rows.Next() {
+		var e Event
+		var k keyed
+		if err := scanCleanedEvent(rows, &e, &k.date, &k.score, &k.rank, &e.DistanceKm, &k.popularity); err != nil {
+			log.Printf("Row scan error: %v", err)
+			continue
+		}
+		events = append(events, e)
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &EventPage{Events: events, Total: total, Sort: q.Sort}
+	if len(events) <= q.Limit {
+		return page, nil
+	}
+	page.Events = events[:q.Limit]
+
+	// Platform positions are only known when every earlier row is, i.e. when
+	// paging by cursor from the first page.
+	if q.Sort == SortPlatform && q.Offset > 0 {
+		return page, nil
+	}
+
+	last, lastKey := page.Events[q.Limit-1], keys[q.Limit-1]
+	next := eventCursor{
+		Sort: q.Sort, Filter: fp,
+		Date: lastKey.date, Time: last.CreatedAt, ID: int64(last.ID), Score: lastKey.score, Popularity: lastKey.popularity,
+	}
+	if last.DistanceKm != nil {
+		next.Distance = *last.DistanceKm
+	}
+	if q.Sort == SortPlatform {
+		next = eventCursor{Sort: q.Sort, Filter: fp, Platforms: map[string]platformPos{}}
+		if cur != nil {
+			for p, pos := range cur.Platforms {
+				next.Platforms[p] = pos
+			}
+		}
+		for i, e := range page.Events {
+			next.Platforms[e.Platform] = platformPos{
+				Date: keys[i].date, Time: e.CreatedAt, ID: int64(e.ID), Rank: keys[i].rank,
+			}
+		}
+	}
+	page.NextCursor = encodeCursor(next)
+	return page, nil
+}
+
+type platformRow struct {
+	Platform string    `json:"platform"`
+	Date     string    `json:"d"`
+	Time     time.Time `json:"t"`
+	ID       int64     `json:"i"`
+	Rank     int64     `json:"n"`
+}
+
+func platformRows(m map[string]platformPos) []platformRow {
+	out := make([]platformRow, 0, len(m))
+	for p, pos := range m {
+		out = append(out, platformRow{Platform: p, Date: pos.Date, Time: pos.Time, ID: pos.ID, Rank: pos.Rank})
+	}
+	return out
+}
+
+// parseEventSort validates ?sort=. Empty means the cursor's sort, or the
+// platform interleave without a cursor.
+func parseEventSort(raw string) (string, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw != "" && !eventSorts[raw] {
+		return "", fmt.Errorf("sort must be one of %s, %s, %s, %s, %s or %s",
+			SortPlatform, SortSoonest, SortNewest, SortRelevance, SortDistance, SortTrending)
+	}
+	return raw, nil
+}
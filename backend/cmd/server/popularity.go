@@ -0,0 +1,164 @@
+// backend/cmd/server/popularity.go
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/popularity"
+)
+
+// ─── Popularity & trending ───────────────────────────────────────────────────
+//
+// Detail views and outbound clicks are counted (see internal/popularity), and
+// with saves they make up a popularity score per event. Links to an event's
+// website and registration page go through GET /events/{id}/out so clicks
+// can be counted. sort=trending and GET /events/trending rank by the score.
+
+// trendingLimit is how many events GET /events/trending returns by default,
+// and trendingMaxLimit the most it returns.
+const (
+	trendingLimit    = 10
+	trendingMaxLimit = 50
+)
+
+// visitorKey keys the visitor hashes of popularity.Visitor. It is
+// VISITOR_HASH_KEY when set, else derived from the JWT secret so that the
+// secret itself only ever signs tokens.
+var visitorKey = func() []byte {
+	if key := os.Getenv("VISITOR_HASH_KEY"); key != "" {
+		return []byte(key)
+	}
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("popularity-visitor"))
+	return mac.Sum(nil)
+}()
+
+type TrendingEvent struct {
+	Event
+	Popularity popularity.Score `json:"popularity"`
+}
+
+// trackInteraction counts a view or click of eventID. Bots and API keys do
+// not count; neither does a failure to record, which is only logged.
+func (s *Server) trackInteraction(r *http.Request, eventID int64, kind string) {
+	if key, _ := r.Context().Value(apiKeyKey).(*apikeys.Key); key != nil {
+		return
+	}
+	ua := r.UserAgent()
+	if popularity.IsBot(ua) {
+		return
+	}
+	// The day is UTC both in the hash and in the row, whatever the
+	// database's time zone.
+	day := time.Now().UTC()
+	visitor := popularity.Visitor(visitorKey, getUserID(r), clientIP(r), ua, day)
+	if err := popularity.Record(s.db, eventID, kind, visitor, day); err != nil {
+		log.Printf("Record %s of event %d error: %v", kind, eventID, err)
+	}
+}
+
+// GET /api/v1/events/{id}/out?to=registration
+//
+// Redirects to the event's website or registration page, counting the click.
+func (s *Server) handleOutbound(w http.ResponseWriter, r *http.Request) {
+	eventID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return
+	}
+
+	var column string
+	switch to := r.URL.Query().Get("to"); to {
+	case "", "website":
+		column = "e.website"
+	case "registration":
+		column = "ed.registration_url"
+	default:
+		jsonError(w, "to must be website or registration", 400)
+		return
+	}
+
+	var target string
+	err = s.db.QueryRow(`
+		SELECT COALESCE(`+column+`, '')
+		FROM events e
+		LEFT JOIN event_details ed ON e.id = ed.event_id
+		WHERE e.id = $1
+	`, eventID).Scan(&target)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "Event not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to load event", err)
+		return
+	}
+	// Only ever redirect to the event's own http(s) links.
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		jsonError(w, "This event has no such link", 404)
+		return
+	}
+
+	s.trackInteraction(r, eventID, popularity.KindClick)
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// GET /api/v1/events/trending?city=Bangalore&limit=10
+//
+// The most popular upcoming events, in one city or everywhere.
+func (s *Server) handleTrending(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := trendingLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > trendingMaxLimit {
+			jsonError(w, "limit must be between 1 and 50", 400)
+			return
+		}
+		limit = n
+	}
+	city := q.Get("city")
+
+	scores, err := popularity.Trending(s.db, city, limit)
+	if err != nil {
+		serverError(w, "Failed to load trending events", err)
+		return
+	}
+	ids := make([]int64, len(scores))
+	for i, sc := range scores {
+		ids[i] = sc.EventID
+	}
+	events, err := s.loadEventsByID(ids)
+	if err != nil {
+		serverError(w, "Failed to load trending events", err)
+		return
+	}
+	byID := make(map[int64]Event, len(events))
+	for _, e := range events {
+		byID[int64(e.ID)] = e
+	}
+
+	out := make([]TrendingEvent, 0, len(scores))
+	for _, sc := range scores {
+		if e, ok := byID[sc.EventID]; ok {
+			out = append(out, TrendingEvent{Event: e, Popularity: sc})
+		}
+	}
+	jsonOK(w, map[string]interface{}{
+		"events": out,
+		"city":   city,
+		"total":  len(out),
+	})
+}
@@ -0,0 +1,375 @@
+// backend/cmd/server/profile.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"event-scraper/internal/accounts"
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/collections"
+	"event-scraper/internal/interests"
+	"event-scraper/internal/lockout"
+	"event-scraper/internal/notify"
+	"event-scraper/internal/reminders"
+	"event-scraper/internal/reviews"
+	"event-scraper/internal/roles"
+	"event-scraper/internal/sessions"
+	"event-scraper/internal/sso"
+	"event-scraper/internal/webhooks"
+)
+
+// ─── Profile & account ────────────────────────────────────────────────────────
+//
+// Users edit their name and email, change their password, download what we
+// hold about them and delete their account. Changing the email, the
+// password or deleting the account asks for the current password again.
+// Accounts made through single sign-on have no password until they set one
+// with a reset link; they confirm a deletion by typing their email instead.
+//
+// Deleting a user removes everything they own through ON DELETE CASCADE:
+// saved events and their reminders, collections, reviews, preferences,
+// interests, notifications, sessions, API keys, webhooks, account tokens and
+// linked identities.
+
+type UpdateProfileRequest struct {
+	FullName        *string `json:"full_name"`
+	Email           *string `json:"email"`
+	CurrentPassword string  `json:"current_password"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type DeleteAccountRequest struct {
+	CurrentPassword string `json:"current_password"`
+	// Confirm is the account's email, for accounts without a password.
+	Confirm string `json:"confirm"`
+}
+
+// passwordHash loads the user's password hash, answering and returning
+// false if that fails. The hash is "" for accounts without a password.
+func (s *Server) passwordHash(w http.ResponseWriter, userID string) (string, bool) {
+	var hash string
+	err := s.db.QueryRow(`SELECT password_hash FROM users WHERE id = $1`, userID).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "User not found", 404)
+		return "", false
+	}
+	if err != nil {
+		serverError(w, "Server error", err)
+		return "", false
+	}
+	return hash, true
+}
+
+// checkPassword answers and returns false unless password matches hash.
+func checkPassword(w http.ResponseWriter, hash, password string) bool {
+	switch {
+	case hash == "":
+		jsonError(w, "This account has no password yet - set one with a reset link first", 400)
+	case password == "":
+		jsonError(w, "current_password is required", 400)
+	case bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil:
+		jsonError(w, "Current password is incorrect", 403)
+	default:
+		return true
+	}
+	return false
+}
+
+// PATCH /api/v1/auth/me
+//
+// Changes the name and/or email. A new email is unverified until the link
+// mailed to it is followed; the old address is told about the change.
+func (s *Server) handleUpdateMe(w http.ResponseWriter, r *http.Request) {
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	if req.FullName == nil && req.Email == nil {
+		jsonError(w, "Nothing to update - send full_name and/or email", 400)
+		return
+	}
+	userID := getUserID(r)
+	user, err := s.getUser(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "User not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+
+	fullName := user.FullName
+	if req.FullName != nil {
+		fullName = strings.TrimSpace(*req.FullName)
+		if fullName == "" || len(fullName) > 120 {
+			jsonError(w, "Full name is required and at most 120 characters", 400)
+			return
+		}
+	}
+	email := user.Email
+	if req.Email != nil {
+		email = strings.TrimSpace(strings.ToLower(*req.Email))
+		if email == "" || len(email) > 180 {
+			jsonError(w, "Email is required and at most 180 characters", 400)
+			return
+		}
+	}
+
+	emailChanged := email != user.Email
+	if emailChanged {
+		hash, ok := s.passwordHash(w, userID)
+		if !ok || !checkPassword(w, hash, req.CurrentPassword) {
+			return
+		}
+		var exists bool
+		if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email=$1)", email).Scan(&exists); err != nil {
+			serverError(w, "Server error", err)
+			return
+		}
+		if exists {
+			jsonError(w, "An account with this email already exists", 409)
+			return
+		}
+	}
+
+	if _, err := s.db.Exec(`
+		UPDATE users SET full_name = $2, email = $3, updated_at = now(),
+		       email_verified_at = CASE WHEN email = $3 THEN email_verified_at END
+		WHERE id = $1
+	`, userID, fullName, email); err != nil {
+		serverError(w, "Failed to update profile", err)
+		return
+	}
+
+	updated, err := s.getUser(userID)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	if emailChanged {
+		if err := s.sendAccountMail(*updated, accounts.PurposeVerifyEmail, "/verify-email"); err != nil {
+			log.Printf("⚠️  Could not send verification email: %v", err)
+		}
+		s.sendEmailChangedMail(*user, email)
+	}
+	jsonOK(w, map[string]interface{}{"user": updated})
+}
+
+// sendEmailChangedMail tells the old address of user that the account now
+// uses email, in case someone else made the change.
+func (s *Server) sendEmailChangedMail(user User, email string) {
+	m := notify.Mail{
+		To:      user.Email,
+		Subject: "Your email address was changed",
+		Text: fmt.Sprintf("Hi %s,\n\nThe email address of your account was changed to %s.\n\n"+
+			"If you did not do this, reset your password at %s/forgot-password and contact us.\n",
+			user.FullName, email, appURL),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.mailer.Send(ctx, m); err != nil {
+			log.Printf("⚠️  Could not send email change notice to %s: %v", user.Email, err)
+		}
+	}()
+}
+
+// POST /api/v1/auth/change-password
+//
+// Sets a new password and signs the account out everywhere else.
+func (s *Server) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	if len(req.NewPassword) < 6 {
+		jsonError(w, "Password must be at least 6 characters", 400)
+		return
+	}
+	userID := getUserID(r)
+	current, ok := s.passwordHash(w, userID)
+	if !ok || !checkPassword(w, current, req.CurrentPassword) {
+		return
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	var email string
+	if err := s.db.QueryRow(`
+		UPDATE users SET password_hash = $2, updated_at = now() WHERE id = $1 RETURNING email
+	`, userID, string(hash)).Scan(&email); err != nil {
+		serverError(w, "Failed to change password", err)
+		return
+	}
+
+	n, err := sessions.RevokeAll(s.db, userID, getSessionID(r), sessions.ReasonPasswordChange)
+	if err != nil {
+		log.Printf("⚠️  Could not end sessions after a password change: %v", err)
+	}
+	if err := lockout.Clear(s.db, email); err != nil {
+		log.Printf("⚠️  Could not clear failed sign-ins after a password change: %v", err)
+	}
+	jsonOK(w, map[string]interface{}{"message": "Password changed", "revoked": n})
+}
+
+// GET /api/v1/auth/me/export
+//
+// Returns everything held about the user as a JSON download. Secrets
+// (password and key hashes, webhook secrets) are left out, and only the
+// most recent notifications are included.
+func (s *Server) handleExportMe(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	user, err := s.getUser(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "User not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+
+	fail := func(err error) { serverError(w, "Failed to export account data", err) }
+	prefs, err := reminders.GetPreferences(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	declared, err := interests.Get(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	saved, err := s.savedEvents(userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	lists, err := collections.List(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	written, err := reviews.ByUser(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	notifications, err := notify.ListNotifications(s.db, userID, false, 200)
+	if err != nil {
+		fail(err)
+		return
+	}
+	hooks, err := webhooks.List(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	keys, err := apikeys.List(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	active, err := sessions.List(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	identities, err := sso.ListIdentities(s.db, userID)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	now := time.Now().UTC()
+	w.Header().Set("Content-Disposition",
+		fmt.Sprintf(`attachment; filename="account-export-%s.json"`, now.Format("2006-01-02")))
+	jsonOK(w, map[string]interface{}{
+		"exported_at":   now,
+		"user":          user,
+		"preferences":   prefs,
+		"interests":     declared,
+		"saved_events":  saved,
+		"collections":   lists,
+		"reviews":       written,
+		"notifications": notifications,
+		"webhooks":      hooks,
+		"api_keys":      keys,
+		"sessions":      active,
+		"identities":    identities,
+	})
+}
+
+// DELETE /api/v1/auth/me
+//
+// Deletes the account and everything it owns. The last admin cannot leave.
+func (s *Server) handleDeleteMe(w http.ResponseWriter, r *http.Request) {
+	var req DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	userID := getUserID(r)
+	user, err := s.getUser(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "User not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	hash, ok := s.passwordHash(w, userID)
+	if !ok {
+		return
+	}
+	if hash == "" {
+		if !strings.EqualFold(strings.TrimSpace(req.Confirm), user.Email) {
+			jsonError(w, "confirm must be the account's email", 400)
+			return
+		}
+	} else if !checkPassword(w, hash, req.CurrentPassword) {
+		return
+	}
+
+	err = roles.DeleteUser(s.db, userID)
+	if errors.Is(err, roles.ErrLastAdmin) {
+		jsonError(w, "The last admin cannot delete their account - make someone else an admin first", 409)
+		return
+	}
+	if errors.Is(err, roles.ErrNotFound) {
+		jsonError(w, "User not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to delete account", err)
+		return
+	}
+	if err := lockout.Clear(s.db, user.Email); err != nil {
+		log.Printf("⚠️  Could not clear failed sign-ins of a deleted account: %v", err)
+	}
+	jsonOK(w, map[string]interface{}{"message": "Account deleted", "deleted": true})
+}
@@ -0,0 +1,103 @@
+// backend/cmd/server/ratelimit.go
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-scraper/api"
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/ratelimit"
+)
+
+// ─── Rate limiting ────────────────────────────────────────────────────────────
+//
+// Every API request takes a token from its caller's bucket: the signed-in
+// user's, else the client IP's. Requests with an API key count against
+// their IP, since the key is only checked later; once it has been, they
+// also take a token from the key's own bucket. RATE_LIMIT sets the bucket
+// (see ratelimit.ParseRule). The endpoints that take passwords or
+// one-time codes also draw on a much smaller bucket per IP,
+// RATE_LIMIT_AUTH. An empty bucket answers 429 with Retry-After.
+//
+// Buckets live in memory, so with several API servers each enforces its
+// own limit. Repeated failed sign-ins also lock the account (see
+// internal/lockout), which holds across servers.
+
+const (
+	defaultAPIRate  = "300/m"
+	defaultAuthRate = "10/m"
+)
+
+// newLimiter builds a limiter from the rule in env, falling back to def.
+func newLimiter(env, def string) *ratelimit.Limiter {
+	spec := getEnv(env, def)
+	rule, err := ratelimit.ParseRule(spec)
+	if err != nil {
+		log.Printf("⚠️  Ignoring invalid %s: %v", env, err)
+		rule, _ = ratelimit.ParseRule(def)
+	}
+	log.Printf("✅ %s: %s", env, rule)
+	return ratelimit.New(rule)
+}
+
+// withRateLimit applies the per-caller limit to API requests.
+func (s *Server) withRateLimit(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, api.LegacyPrefix+"/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if ok, wait := s.apiLimits.Allow(rateKey(r)); !ok {
+			tooManyRequests(w, wait, "Too many requests - slow down")
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// limitAuth applies the per-IP limit of endpoints that check secrets.
+func (s *Server) limitAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := s.authLimits.Allow(clientIP(r)); !ok {
+			tooManyRequests(w, wait, "Too many attempts - try again later")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// allowKey takes a token from the bucket of a verified API key, answering
+// 429 when it is empty.
+func (s *Server) allowKey(w http.ResponseWriter, key *apikeys.Key) bool {
+	if ok, wait := s.apiLimits.Allow("key:" + key.ID); !ok {
+		tooManyRequests(w, wait, "Too many requests - slow down")
+		return false
+	}
+	return true
+}
+
+// rateKey names the caller's bucket before anything is verified against the
+// database. Only a valid bearer token counts as a user; anything else,
+// including an API key that may be made up, counts against the client IP,
+// so a flood of fresh keys cannot each get a bucket of their own.
+func rateKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		if claims, err := parseJWT(strings.TrimPrefix(auth, "Bearer ")); err == nil {
+			if userID, _ := claims["user_id"].(string); userID != "" {
+				return "user:" + userID
+			}
+		}
+	}
+	return "ip:" + clientIP(r)
+}
+
+// tooManyRequests answers 429, telling the client when to retry.
+func tooManyRequests(w http.ResponseWriter, wait time.Duration, msg string) {
+	secs := int((wait + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+	jsonError(w, msg, http.StatusTooManyRequests)
+}
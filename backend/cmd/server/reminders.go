@@ -0,0 +1,208 @@
+// backend/cmd/server/reminders.go
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/lib/pq"
+
+	"event-scraper/internal/notify"
+	"event-scraper/internal/reminders"
+)
+
+// savedReminders is the reminder setting of one saved event. hasOwn is
+// false when the event follows the user's default offsets.
+type savedReminders struct {
+	savedEventID int64
+	offsets      []int64
+	hasOwn       bool
+}
+
+// loadSavedReminders reads the caller's saved event named by {id}, writing
+// the error response itself when that fails.
+func (s *Server) loadSavedReminders(w http.ResponseWriter, r *http.Request) (*savedReminders, bool) {
+	eventID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return nil, false
+	}
+
+	var sr savedReminders
+	err = s.db.QueryRow(`
+		SELECT id, reminder_offsets IS NOT NULL, COALESCE(reminder_offsets, '{}')
+		FROM saved_events WHERE user_id = $1 AND event_id = $2
+	`, getUserID(r), eventID).Scan(&sr.savedEventID, &sr.hasOwn, pq.Array(&sr.offsets))
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "Event is not saved", 404)
+		return nil, false
+	}
+	if err != nil {
+		serverError(w, "Server error", err)
+		return nil, false
+	}
+	return &sr, true
+}
+
+// GET /api/v1/events/{id}/reminders
+func (s *Server) handleGetEventReminders(w http.ResponseWriter, r *http.Request) {
+	if sr, ok := s.loadSavedReminders(w, r); ok {
+		s.writeEventReminders(w, sr)
+	}
+}
+
+// PUT /api/v1/events/{id}/reminders
+//
+// Body: {"offsets": [1440, 120]} — minutes before start.
+// {"offsets": null} reverts to the user's defaults, [] disables reminders.
+func (s *Server) handleSetEventReminders(w http.ResponseWriter, r *http.Request) {
+	sr, ok := s.loadSavedReminders(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Offsets *[]int64 `json:"offsets"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+
+	var offsets interface{}
+	sr.hasOwn = body.Offsets != nil
+	if sr.hasOwn {
+		valid, err := reminders.ValidateOffsets(*body.Offsets)
+		if err != nil {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		sr.offsets = valid
+		offsets = pq.Array(valid)
+	}
+
+	if _, err := s.db.Exec(`UPDATE saved_events SET reminder_offsets = $1 WHERE id = $2`,
+		offsets, sr.savedEventID); err != nil {
+		serverError(w, "Failed to update reminders", err)
+		return
+	}
+	if err := reminders.ScheduleSavedEvent(s.db, sr.savedEventID); err != nil {
+		serverError(w, "Failed to schedule reminders", err)
+		return
+	}
+	s.writeEventReminders(w, sr)
+}
+
+func (s *Server) writeEventReminders(w http.ResponseWriter, sr *savedReminders) {
+	list, err := reminders.ListForSavedEvent(s.db, sr.savedEventID)
+	if err != nil {
+		serverError(w, "Failed to list reminders", err)
+		return
+	}
+
+	var offsets []int64
+	if sr.hasOwn {
+		offsets = sr.offsets
+	}
+	jsonOK(w, map[string]interface{}{
+		"offsets":       offsets,
+		"uses_defaults": !sr.hasOwn,
+		"reminders":     list,
+	})
+}
+
+// GET /api/v1/preferences
+func (s *Server) handleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	s.writePreferences(w, getUserID(r))
+}
+
+// PUT /api/v1/preferences
+func (s *Server) handleUpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	current, err := reminders.GetPreferences(s.db, userID)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+
+	var body struct {
+		ReminderOffsets  *[]int64  `json:"reminder_offsets"`
+		ReminderChannels *[]string `json:"reminder_channels"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	if body.ReminderOffsets != nil {
+		valid, err := reminders.ValidateOffsets(*body.ReminderOffsets)
+		if err != nil {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		current.ReminderOffsets = valid
+	}
+	if body.ReminderChannels != nil {
+		if err := reminders.ValidateChannels(*body.ReminderChannels); err != nil {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		current.ReminderChannels = *body.ReminderChannels
+	}
+
+	if err := reminders.SavePreferences(s.db, userID, current); err != nil {
+		serverError(w, "Failed to save preferences", err)
+		return
+	}
+	s.writePreferences(w, userID)
+}
+
+func (s *Server) writePreferences(w http.ResponseWriter, userID string) {
+	prefs, err := reminders.GetPreferences(s.db, userID)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"preferences": prefs})
+}
+
+// GET /api/v1/notifications?unread=true&limit=50
+func (s *Server) handleNotifications(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	list, err := notify.ListNotifications(s.db, getUserID(r), q.Get("unread") == "true", limit)
+	if err != nil {
+		serverError(w, "Failed to list notifications", err)
+		return
+	}
+
+	jsonOK(w, map[string]interface{}{"notifications": list, "total": len(list)})
+}
+
+// POST /api/v1/notifications/{id}/read
+func (s *Server) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid notification ID", 400)
+		return
+	}
+	s.markNotificationsRead(w, r, id)
+}
+
+// POST /api/v1/notifications/read-all
+func (s *Server) handleMarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	s.markNotificationsRead(w, r, 0)
+}
+
+// markNotificationsRead marks one notification read, or all of them when
+// id is 0.
+func (s *Server) markNotificationsRead(w http.ResponseWriter, r *http.Request, id int64) {
+	n, err := notify.MarkRead(s.db, getUserID(r), id)
+	if err != nil {
+		serverError(w, "Failed to update notifications", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"updated": n})
+}
@@ -0,0 +1,277 @@
+// backend/cmd/server/reviews.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"event-scraper/internal/reviews"
+)
+
+// ─── Ratings & reviews ───────────────────────────────────────────────────────
+//
+// People who were at an event rate it and may leave a short review (see
+// internal/reviews for who counts). Reviews are listed per event, per
+// organizer or across the site; moderators work through held reviews under
+// /admin/reviews.
+
+type ReviewRequest struct {
+	Rating int    `json:"rating"`
+	Body   string `json:"body"`
+}
+
+type FlagReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// reviewPage reads ?page= and ?limit= (20 by default, at most 50).
+func reviewPage(r *http.Request) (page, limit int) {
+	q := r.URL.Query()
+	page, _ = strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ = strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	return page, limit
+}
+
+// writeReviews answers one page of the reviews matching f, with the summary
+// of all of them. Public lists leave out moderation details.
+func (s *Server) writeReviews(w http.ResponseWriter, r *http.Request, f reviews.Filter, extra map[string]interface{}) {
+	page, limit := reviewPage(r)
+	if v := r.URL.Query().Get("rating"); v != "" {
+		rating, err := strconv.Atoi(v)
+		if err != nil || rating < 1 || rating > 5 {
+			jsonError(w, reviews.ErrBadRating.Error(), 400)
+			return
+		}
+		f.Rating = rating
+	}
+
+	list, total, err := reviews.List(s.db, f, limit, (page-1)*limit)
+	if err != nil {
+		serverError(w, "Failed to list reviews", err)
+		return
+	}
+	summary, err := reviews.Summarize(s.db, f)
+	if err != nil {
+		serverError(w, "Failed to list reviews", err)
+		return
+	}
+	if f.Status == "" {
+		for i := range list {
+			list[i].ModerationReason, list[i].Flags = "", 0
+		}
+	}
+
+	totalPages := (total + limit - 1) / limit
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	out := map[string]interface{}{
+		"reviews":     list,
+		"summary":     summary,
+		"total":       total,
+		"page":        page,
+		"limit":       limit,
+		"total_pages": totalPages,
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	jsonOK(w, out)
+}
+
+// GET /api/v1/events/{id}/reviews?rating=5&page=1
+//
+// Published reviews of an event, newest first. A signed-in caller also gets
+// their own review, whatever its status, as "mine".
+func (s *Server) handleEventReviews(w http.ResponseWriter, r *http.Request) {
+	eventID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return
+	}
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, eventID).Scan(&exists); err != nil {
+		serverError(w, "Failed to load event", err)
+		return
+	}
+	if !exists {
+		jsonError(w, "Event not found", 404)
+		return
+	}
+
+	extra := map[string]interface{}{}
+	if userID := getUserID(r); userID != "" {
+		mine, err := reviews.Mine(s.db, userID, eventID)
+		if err != nil && !errors.Is(err, reviews.ErrNotFound) {
+			serverError(w, "Failed to list reviews", err)
+			return
+		}
+		extra["mine"] = mine
+		extra["can_review"] = mine != nil || reviews.CheckEligible(s.db, userID, eventID) == nil
+	}
+	s.writeReviews(w, r, reviews.Filter{EventID: eventID}, extra)
+}
+
+// GET /api/v1/reviews?organizer=GDG%20Bangalore&event_id=1&rating=5
+//
+// Published reviews across events, newest first, with their summary; per
+// organizer, this is how well a recurring series is rated.
+func (s *Server) handleListReviews(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := reviews.Filter{Organizer: q.Get("organizer")}
+	if v := q.Get("event_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			jsonError(w, "Invalid event ID", 400)
+			return
+		}
+		f.EventID = id
+	}
+	s.writeReviews(w, r, f, nil)
+}
+
+// PUT /api/v1/events/{id}/review
+//
+// Rates and reviews an event, replacing the caller's earlier review.
+func (s *Server) handlePutReview(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+	eventID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return
+	}
+
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	review := reviews.Review{EventID: eventID, UserID: userID, Rating: req.Rating, Body: req.Body}
+	if err := review.Validate(); err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+
+	err = reviews.CheckEligible(s.db, userID, eventID)
+	if errors.Is(err, reviews.ErrNotEligible) || errors.Is(err, reviews.ErrNotHappened) {
+		jsonError(w, err.Error(), 403)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to save review", err)
+		return
+	}
+
+	if err := reviews.Upsert(s.db, &review); err != nil {
+		serverError(w, "Failed to save review", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"review": review})
+}
+
+// DELETE /api/v1/events/{id}/review
+func (s *Server) handleDeleteReview(w http.ResponseWriter, r *http.Request) {
+	eventID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid event ID", 400)
+		return
+	}
+	deleted, err := reviews.Delete(s.db, getUserID(r), eventID)
+	if err != nil {
+		serverError(w, "Failed to delete review", err)
+		return
+	}
+	if !deleted {
+		jsonError(w, "You have not reviewed this event", 404)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "Review deleted", "deleted": true})
+}
+
+// POST /api/v1/reviews/{id}/flag
+//
+// Reports a published review to the moderators.
+func (s *Server) handleFlagReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid review ID", 400)
+		return
+	}
+	var req FlagReviewRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "Invalid request body", 400)
+			return
+		}
+	}
+
+	err = reviews.Flag(s.db, reviewID, getUserID(r), req.Reason)
+	switch {
+	case errors.Is(err, reviews.ErrNotFound):
+		jsonError(w, "Review not found", 404)
+	case errors.Is(err, reviews.ErrOwnReview):
+		jsonError(w, err.Error(), 400)
+	case err != nil:
+		serverError(w, "Failed to flag review", err)
+	default:
+		jsonOK(w, map[string]interface{}{"message": "Thanks, a moderator will take a look", "flagged": true})
+	}
+}
+
+// GET /api/v1/admin/reviews?status=pending
+//
+// Reviews waiting for a moderator (or published or hidden ones), with their
+// flags and the reason they were held.
+func (s *Server) handleAdminReviews(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = reviews.StatusPending
+	case reviews.StatusPending, reviews.StatusPublished, reviews.StatusHidden:
+	default:
+		jsonError(w, "status must be pending, published or hidden", 400)
+		return
+	}
+	s.writeReviews(w, r, reviews.Filter{Status: status}, nil)
+}
+
+// PUT /api/v1/admin/reviews/{id}
+//
+// Publishes or hides a review.
+func (s *Server) handleModerateReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, err := pathID(r, "id")
+	if err != nil {
+		jsonError(w, "Invalid review ID", 400)
+		return
+	}
+	var req ModerateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+
+	review, err := reviews.Moderate(s.db, reviewID, getUserID(r), req.Status, req.Reason)
+	switch {
+	case errors.Is(err, reviews.ErrBadStatus):
+		jsonError(w, err.Error(), 400)
+	case errors.Is(err, reviews.ErrNotFound):
+		jsonError(w, "Review not found", 404)
+	case err != nil:
+		serverError(w, "Failed to moderate review", err)
+	default:
+		jsonOK(w, map[string]interface{}{"review": review})
+	}
+}
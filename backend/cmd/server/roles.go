@@ -0,0 +1,108 @@
+// backend/cmd/server/roles.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"event-scraper/internal/roles"
+)
+
+// ─── Users & roles (admin) ────────────────────────────────────────────────────
+//
+// Users are user, moderator or admin (see internal/roles). Moderators
+// moderate reviews and triage unresolved locations; admins also edit the
+// gazetteer, run scrapes, watch scraper health and hand out roles. The first admin is made with cmd/create-admin.
+
+type RoleRequest struct {
+	Role string `json:"role"`
+}
+
+// GET /api/v1/admin/users?q=ada&role=admin&limit=50
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	role := q.Get("role")
+	if role != "" {
+		if _, ok := roles.Parse(role); !ok {
+			jsonError(w, "role must be user, moderator or admin", 400)
+			return
+		}
+	}
+	search := strings.TrimSpace(q.Get("q"))
+
+	rows, err := s.db.Query(`
+		SELECT id::text, full_name, email, role, created_at, email_verified_at
+		FROM users
+		WHERE ($1 = '' OR role = $1)
+		  AND ($2 = '' OR email ILIKE '%' || $2 || '%' OR full_name ILIKE '%' || $2 || '%')
+		ORDER BY created_at DESC
+		LIMIT $3
+	`, role, search, limit)
+	if err != nil {
+		serverError(w, "Failed to list users", err)
+		return
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.FullName, &u.Email, &u.Role, &u.CreatedAt, &u.EmailVerifiedAt); err != nil {
+			serverError(w, "Failed to list users", err)
+			return
+		}
+		u.EmailVerified = u.EmailVerifiedAt != nil
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		serverError(w, "Failed to list users", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"users": users, "total": len(users)})
+}
+
+// PUT /api/v1/admin/users/{id}/role
+//
+// The user's current access tokens keep their old role until they refresh.
+func (s *Server) handleSetRole(w http.ResponseWriter, r *http.Request) {
+	var req RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	role, ok := roles.Parse(req.Role)
+	if !ok {
+		jsonError(w, "role must be user, moderator or admin", 400)
+		return
+	}
+
+	id := r.PathValue("id")
+	err := roles.Set(s.db, id, role)
+	switch {
+	case errors.Is(err, roles.ErrNotFound):
+		jsonError(w, "User not found", 404)
+		return
+	case errors.Is(err, roles.ErrLastAdmin):
+		jsonError(w, "Cannot remove the last admin - promote another user first", 409)
+		return
+	case err != nil:
+		serverError(w, "Failed to set role", err)
+		return
+	}
+	log.Printf("🔐 User %s made %s by %s", id, role, getUserID(r))
+
+	user, err := s.getUser(id)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"user": user})
+}
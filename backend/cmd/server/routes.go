@@ -0,0 +1,147 @@
+// backend/cmd/server/routes.go
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"event-scraper/api"
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/roles"
+)
+
+// ─── Routes ───────────────────────────────────────────────────────────────────
+//
+// Every API route is served under /api/v1 and, for clients written before
+// versioning, under /api. Only the /api/v1 paths are documented in
+// api/openapi.yaml; the aliases share their handlers.
+
+type router struct {
+	mux *http.ServeMux
+	// patterns lists the documented patterns, for api.CheckRoutes.
+	patterns []string
+}
+
+func (rt *router) handle(pattern string, h http.HandlerFunc) {
+	rt.mux.HandleFunc(pattern, h)
+	rt.patterns = append(rt.patterns, pattern)
+}
+
+// api registers h for method and path under /api/v1 and its /api alias.
+func (rt *router) api(method, path string, h http.HandlerFunc) {
+	rt.handle(method+" "+api.V1Prefix+path, h)
+	rt.mux.HandleFunc(method+" "+api.LegacyPrefix+path, h)
+}
+
+func (s *Server) routes() *router {
+	rt := &router{mux: http.NewServeMux()}
+
+	rt.api("GET", "/events", s.optionalAuth(s.handleEvents))
+	rt.api("GET", "/events/filters", s.optionalAuth(s.handleFilters))
+	rt.api("GET", "/events/for-you", s.requireScope(apikeys.ScopeEventsRead, s.handleForYou))
+	rt.api("GET", "/events/recommended", s.requireScope(apikeys.ScopeEventsRead, s.handleRecommendedForUser))
+	rt.api("GET", "/events/trending", s.optionalAuth(s.handleTrending))
+	rt.api("GET", "/events/{id}", s.optionalAuth(s.handleEventDetail))
+	rt.api("GET", "/events/{id}/recommended", s.optionalAuth(s.handleRecommendedEvents))
+	rt.api("GET", "/events/{id}/also-saved", s.optionalAuth(s.handleAlsoSaved))
+	rt.api("GET", "/events/{id}/out", s.optionalAuth(s.handleOutbound))
+	rt.api("GET", "/events/{id}/reviews", s.optionalAuth(s.handleEventReviews))
+	rt.api("PUT", "/events/{id}/review", s.requireAuth(s.handlePutReview))
+	rt.api("DELETE", "/events/{id}/review", s.requireAuth(s.handleDeleteReview))
+	rt.api("GET", "/reviews", s.optionalAuth(s.handleListReviews))
+	rt.api("POST", "/reviews/{id}/flag", s.requireAuth(s.handleFlagReview))
+	rt.api("POST", "/events/{id}/save", s.requireScope(apikeys.ScopeSaved, s.handleSaveEvent))
+	rt.api("PATCH", "/events/{id}/save", s.requireScope(apikeys.ScopeSaved, s.handleUpdateSavedEvent))
+	rt.api("DELETE", "/events/{id}/save", s.requireScope(apikeys.ScopeSaved, s.handleUnsaveEvent))
+	rt.api("GET", "/events/{id}/reminders", s.requireScope(apikeys.ScopeSaved, s.handleGetEventReminders))
+	rt.api("PUT", "/events/{id}/reminders", s.requireScope(apikeys.ScopeSaved, s.handleSetEventReminders))
+	rt.api("GET", "/saved-events", s.requireScope(apikeys.ScopeSaved, s.handleGetSavedEvents))
+	rt.api("GET", "/agenda", s.requireScope(apikeys.ScopeSaved, s.handleAgenda))
+	rt.api("GET", "/collections", s.requireScope(apikeys.ScopeSaved, s.handleListCollections))
+	rt.api("POST", "/collections", s.requireScope(apikeys.ScopeSaved, s.handleCreateCollection))
+	rt.api("GET", "/collections/{id}", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleGetCollection)))
+	rt.api("PATCH", "/collections/{id}", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleUpdateCollection)))
+	rt.api("DELETE", "/collections/{id}", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleDeleteCollection)))
+	rt.api("POST", "/collections/{id}/events", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleAddCollectionEvent)))
+	rt.api("DELETE", "/collections/{id}/events/{eventId}", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleRemoveCollectionEvent)))
+	rt.api("PUT", "/collections/{id}/order", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleReorderCollection)))
+	rt.api("POST", "/collections/{id}/share", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleShareCollection)))
+	rt.api("DELETE", "/collections/{id}/share", s.requireScope(apikeys.ScopeSaved, s.withCollection(s.handleUnshareCollection)))
+	rt.api("GET", "/shared/collections/{token}", s.optionalAuth(s.handleGetSharedCollection))
+
+	rt.api("POST", "/auth/signup", s.limitAuth(s.handleSignup))
+	rt.api("POST", "/auth/signin", s.limitAuth(s.handleSignin))
+	rt.api("GET", "/auth/me", s.requireAuth(s.handleMe))
+	rt.api("PATCH", "/auth/me", s.limitAuth(s.requireAuth(s.handleUpdateMe)))
+	rt.api("DELETE", "/auth/me", s.limitAuth(s.requireAuth(s.handleDeleteMe)))
+	rt.api("GET", "/auth/me/export", s.requireAuth(s.handleExportMe))
+	rt.api("POST", "/auth/change-password", s.limitAuth(s.requireAuth(s.handleChangePassword)))
+	rt.api("POST", "/auth/refresh", s.handleRefresh)
+	rt.api("POST", "/auth/logout", s.optionalAuth(s.handleLogout))
+	rt.api("POST", "/auth/logout-all", s.requireAuth(s.handleLogoutAll))
+	rt.api("GET", "/auth/sessions", s.requireAuth(s.handleListSessions))
+	rt.api("POST", "/auth/verify-email", s.limitAuth(s.handleVerifyEmail))
+	rt.api("POST", "/auth/verify-email/request", s.requireAuth(s.handleRequestVerification))
+	rt.api("POST", "/auth/forgot-password", s.limitAuth(s.handleForgotPassword))
+	rt.api("POST", "/auth/reset-password", s.limitAuth(s.handleResetPassword))
+	rt.api("GET", "/auth/oidc/config", s.handleSSOConfig)
+	rt.api("GET", "/auth/oidc/login", s.handleSSOLogin)
+	rt.api("GET", "/auth/oidc/callback", s.handleSSOCallback)
+	rt.api("POST", "/auth/oidc/token", s.limitAuth(s.handleSSOToken))
+
+	rt.api("GET", "/preferences", s.requireAuth(s.handleGetPreferences))
+	rt.api("PUT", "/preferences", s.requireAuth(s.handleUpdatePreferences))
+	rt.api("GET", "/interests", s.requireAuth(s.handleGetInterests))
+	rt.api("PUT", "/interests", s.requireAuth(s.handleUpdateInterests))
+	rt.api("GET", "/notifications", s.requireAuth(s.handleNotifications))
+	rt.api("POST", "/notifications/{id}/read", s.requireAuth(s.handleMarkNotificationRead))
+	rt.api("POST", "/notifications/read-all", s.requireAuth(s.handleMarkAllNotificationsRead))
+
+	rt.api("GET", "/api-keys", s.requireAuth(s.handleListAPIKeys))
+	rt.api("POST", "/api-keys", s.requireAuth(s.handleCreateAPIKey))
+	rt.api("DELETE", "/api-keys/{id}", s.requireAuth(s.handleDeleteAPIKey))
+
+	rt.api("GET", "/webhooks", s.requireWebhookAccess(s.handleListWebhooks))
+	rt.api("POST", "/webhooks", s.requireWebhookAccess(s.handleCreateWebhook))
+	rt.api("GET", "/webhooks/{id}", s.requireWebhookAccess(s.withWebhook(s.handleGetWebhook)))
+	rt.api("PUT", "/webhooks/{id}", s.requireWebhookAccess(s.withWebhook(s.handleUpdateWebhook)))
+	rt.api("DELETE", "/webhooks/{id}", s.requireWebhookAccess(s.withWebhook(s.handleDeleteWebhook)))
+	rt.api("GET", "/webhooks/{id}/deliveries", s.requireWebhookAccess(s.withWebhook(s.handleWebhookDeliveries)))
+	rt.api("POST", "/webhooks/{id}/deliveries/{deliveryId}/replay", s.requireWebhookAccess(s.withWebhook(s.handleReplayDelivery)))
+
+	rt.api("POST", "/scrape/details", s.requireRole(roles.Admin, s.handleManualDetailScrape))
+	rt.api("GET", "/admin/scraper-health", s.requireRole(roles.Admin, s.handleScraperHealth))
+	rt.api("GET", "/admin/users", s.requireRole(roles.Admin, s.handleListUsers))
+	rt.api("PUT", "/admin/users/{id}/role", s.requireRole(roles.Admin, s.handleSetRole))
+	rt.api("GET", "/admin/api-keys", s.requireRole(roles.Admin, s.handleAdminListAPIKeys))
+	rt.api("DELETE", "/admin/api-keys/{id}", s.requireRole(roles.Admin, s.handleAdminDeleteAPIKey))
+	rt.api("GET", "/admin/gazetteer/cities", s.requireRole(roles.Moderator, s.handleGazetteerCities))
+	rt.api("GET", "/admin/gazetteer/unresolved", s.requireRole(roles.Moderator, s.handleUnresolvedLocations))
+	rt.api("GET", "/admin/gazetteer/aliases", s.requireRole(roles.Moderator, s.handleListAliases))
+	rt.api("POST", "/admin/gazetteer/aliases", s.requireRole(roles.Admin, s.handleCreateAlias))
+	rt.api("DELETE", "/admin/gazetteer/aliases/{name}", s.requireRole(roles.Admin, s.handleDeleteAlias))
+	rt.api("GET", "/admin/reviews", s.requireRole(roles.Moderator, s.handleAdminReviews))
+	rt.api("PUT", "/admin/reviews/{id}", s.requireRole(roles.Moderator, s.handleModerateReview))
+	rt.api("GET", "/stream", s.handleStream)
+
+	graphqlHandler := s.optionalAuth(s.handleGraphQL(s.newGraphQLSchema()))
+	rt.api("GET", "/graphql", graphqlHandler)
+	rt.api("POST", "/graphql", graphqlHandler)
+
+	rt.api("GET", "/openapi.json", handleOpenAPI)
+	rt.handle("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		jsonOK(w, map[string]string{"status": "ok"})
+	})
+
+	return rt
+}
+
+// pathID parses a numeric path wildcard such as {id}.
+func pathID(r *http.Request, name string) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err == nil && id <= 0 {
+		err = errors.New("id must be positive")
+	}
+	return id, err
+}
@@ -0,0 +1,149 @@
+// backend/cmd/server/rsvp.go
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"event-scraper/internal/reminders"
+	"event-scraper/internal/rsvp"
+	"event-scraper/pkg/utils"
+)
+
+// ─── RSVP status & agenda ────────────────────────────────────────────────────
+//
+// Saved events carry a status (see internal/rsvp). Marking an event going
+// answers with the other going events it overlaps, as a warning; GET
+// /agenda lays out the saved events of a date range.
+
+// Default and longest agenda range, in days.
+const (
+	agendaDays    = 30
+	agendaMaxDays = 366
+)
+
+type AgendaEntry struct {
+	rsvp.Entry
+	Event Event `json:"event"`
+}
+
+// setSavedStatus changes the status of a saved event. Reminders are
+// rescheduled, so a skipped event stops reminding.
+func (s *Server) setSavedStatus(savedEventID int64, status string) error {
+	if _, err := s.db.Exec(`
+		UPDATE saved_events
+		SET status = $2,
+		    status_changed_at = CASE WHEN status <> $2 THEN now() ELSE status_changed_at END
+		WHERE id = $1
+	`, savedEventID, status); err != nil {
+		return err
+	}
+	if err := reminders.ScheduleSavedEvent(s.db, savedEventID); err != nil {
+		log.Printf("Schedule reminders error: %v", err)
+	}
+	return nil
+}
+
+// conflictingEvents returns the other events userID is going to that overlap
+// eventID, if they are going to eventID too; otherwise none.
+func (s *Server) conflictingEvents(userID string, eventID int64) ([]Event, error) {
+	var status string
+	err := s.db.QueryRow(`
+		SELECT status FROM saved_events WHERE user_id = $1 AND event_id = $2
+	`, userID, eventID).Scan(&status)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && status != rsvp.StatusGoing) {
+		return []Event{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := rsvp.Conflicts(s.db, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.loadEventsByID(ids)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []Event{}
+	}
+	return events, nil
+}
+
+// GET /api/v1/agenda?from=2026-03-01&to=2026-03-31
+//
+// The caller's saved events taking place between from and to (inclusive,
+// India time; today and 30 days on by default), earliest first, with the
+// conflicts between going events. Skipped events are left out.
+func (s *Server) handleAgenda(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	loc := utils.EventLocation()
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if v := q.Get("from"); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			jsonError(w, "from must be a date such as 2026-03-01", 400)
+			return
+		}
+		from = d
+	}
+	to := from.AddDate(0, 0, agendaDays)
+	if v := q.Get("to"); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, loc)
+		if err != nil {
+			jsonError(w, "to must be a date such as 2026-03-31", 400)
+			return
+		}
+		to = d.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		jsonError(w, "to must not be before from", 400)
+		return
+	}
+	if to.After(from.AddDate(0, 0, agendaMaxDays)) {
+		jsonError(w, "The agenda covers at most 366 days", 400)
+		return
+	}
+
+	entries, err := rsvp.Agenda(s.db, getUserID(r), from, to)
+	if err != nil {
+		serverError(w, "Failed to load agenda", err)
+		return
+	}
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.EventID
+	}
+	events, err := s.loadEventsByID(ids)
+	if err != nil {
+		serverError(w, "Failed to load agenda", err)
+		return
+	}
+	byID := make(map[int64]Event, len(events))
+	for _, e := range events {
+		byID[int64(e.ID)] = e
+	}
+
+	agenda := make([]AgendaEntry, 0, len(entries))
+	conflicts := 0
+	for _, e := range entries {
+		agenda = append(agenda, AgendaEntry{Entry: e, Event: byID[e.EventID]})
+		if len(e.Conflicts) > 0 {
+			conflicts++
+		}
+	}
+	jsonOK(w, map[string]interface{}{
+		"from":      from.Format("2006-01-02"),
+		"to":        to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"entries":   agenda,
+		"total":     len(agenda),
+		"conflicts": conflicts,
+	})
+}
@@ -18,229 +18,34 @@ import (
 	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 
+	"event-scraper/api"
+	"event-scraper/internal/accounts"
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/collections"
+	"event-scraper/internal/cosaved"
+	"event-scraper/internal/gazetteer"
+	"event-scraper/internal/geo"
+	"event-scraper/internal/interests"
+	"event-scraper/internal/lockout"
+	"event-scraper/internal/notify"
+	"event-scraper/internal/popularity"
+	"event-scraper/internal/ratelimit"
+	"event-scraper/internal/reminders"
+	"event-scraper/internal/reviews"
+	"event-scraper/internal/roles"
+	"event-scraper/internal/rsvp"
 	"event-scraper/internal/scrapers"
+	"event-scraper/internal/sessions"
+	"event-scraper/internal/similar"
+	"event-scraper/internal/sso"
+	"event-scraper/internal/stream"
+	"event-scraper/internal/webhooks"
 )
 
 // ─── JWT Secret ───────────────────────────────────────────────────────────────
 
 var jwtSecret = []byte(getEnv("JWT_SECRET", "event-scraper-secret-key-change-me"))
 
-var cityKeywords = []struct {
-	keyword string
-	display string
-}{
-	// ── Bengaluru ─────────────────────────────────────────────────────────
-	{"bengaluru", "Bengaluru"},
-	{"bangalore", "Bengaluru"},
-	{"koramangala", "Bengaluru"},
-	{"indiranagar", "Bengaluru"},
-	{"whitefield", "Bengaluru"},
-	{"hsr layout", "Bengaluru"},
-	{"hsr", "Bengaluru"},
-	{"marathahalli", "Bengaluru"},
-	{"hebbal", "Bengaluru"},
-	{"electronic city", "Bengaluru"},
-	{"jp nagar", "Bengaluru"},
-	{"jayanagar", "Bengaluru"},
-	{"malleswaram", "Bengaluru"},
-	{"yelahanka", "Bengaluru"},
-	{"devanahalli", "Bengaluru"},
-	{"bellandur", "Bengaluru"},
-	{"sarjapur", "Bengaluru"},
-	{"domlur", "Bengaluru"},
-	{"btm layout", "Bengaluru"},
-	{"btm", "Bengaluru"},
-	{"mg road", "Bengaluru"},
-	{"ulsoor", "Bengaluru"},
-	{"kaikondrahalli", "Bengaluru"},
-	{"jakkur", "Bengaluru"},
-	{"rajajinagar", "Bengaluru"},
-	{"yeshwanthpur", "Bengaluru"},
-	{"peenya", "Bengaluru"},
-	{"biec", "Bengaluru"},
-	{"bangalore international exhibition", "Bengaluru"},
-	{"ktpo", "Bengaluru"},
-	{"nimhans convention", "Bengaluru"},
-	{"draper startup", "Bengaluru"},
-	{"91springboard", "Bengaluru"},
-	{"wework galaxy", "Bengaluru"},
-
-	// ── Mumbai ────────────────────────────────────────────────────────────
-	{"mumbai", "Mumbai"},
-	{"bombay", "Mumbai"},
-	{"andheri", "Mumbai"},
-	{"bandra", "Mumbai"},
-	{"powai", "Mumbai"},
-	{"lower parel", "Mumbai"},
-	{"goregaon", "Mumbai"},
-	{"malad", "Mumbai"},
-	{"borivali", "Mumbai"},
-	{"thane", "Mumbai"},
-	{"navi mumbai", "Mumbai"},
-	{"worli", "Mumbai"},
-	{"dadar", "Mumbai"},
-	{"juhu", "Mumbai"},
-	{"kurla", "Mumbai"},
-	{"vikhroli", "Mumbai"},
-	{"mulund", "Mumbai"},
-	{"bkc", "Mumbai"},
-	{"bandra kurla", "Mumbai"},
-	{"kandivali", "Mumbai"},
-	{"chembur", "Mumbai"},
-	{"ghatkopar", "Mumbai"},
-	{"nesco", "Mumbai"},
-	{"bombay exhibition", "Mumbai"},
-	{"nehru centre", "Mumbai"},
-	{"nsci dome", "Mumbai"},
-	{"world trade centre", "Mumbai"},
-	{"jio world", "Mumbai"},
-
-	// ── Hyderabad ─────────────────────────────────────────────────────────
-	{"hyderabad", "Hyderabad"},
-	{"gachibowli", "Hyderabad"},
-	{"hitech city", "Hyderabad"},
-	{"hitec city", "Hyderabad"},
-	{"madhapur", "Hyderabad"},
-	{"banjara hills", "Hyderabad"},
-	{"jubilee hills", "Hyderabad"},
-	{"kondapur", "Hyderabad"},
-	{"miyapur", "Hyderabad"},
-	{"secunderabad", "Hyderabad"},
-	{"kukatpally", "Hyderabad"},
-	{"begumpet", "Hyderabad"},
-	{"manikonda", "Hyderabad"},
-	{"nanakramguda", "Hyderabad"},
-	{"durgam cheruvu", "Hyderabad"},
-	{"financial district", "Hyderabad"},
-	{"hitex", "Hyderabad"},
-	{"hitex exhibition", "Hyderabad"},
-	{"hyderabad international convention", "Hyderabad"},
-	{"hicc", "Hyderabad"},
-	{"novotel hyderabad", "Hyderabad"},
-	{"cokarma", "Hyderabad"},
-	{"t-hub", "Hyderabad"},
-	{"t hub", "Hyderabad"},
-
-	// ── Pune ──────────────────────────────────────────────────────────────
-	{"pune", "Pune"},
-	{"koregaon park", "Pune"},
-	{"viman nagar", "Pune"},
-	{"hinjewadi", "Pune"},
-	{"wakad", "Pune"},
-	{"kothrud", "Pune"},
-	{"baner", "Pune"},
-	{"aundh", "Pune"},
-	{"hadapsar", "Pune"},
-	{"kharadi", "Pune"},
-	{"magarpatta", "Pune"},
-	{"shivajinagar", "Pune"},
-	{"ideas to impacts", "Pune"},
-
-	// ── Delhi / NCR ───────────────────────────────────────────────────────
-	{"new delhi", "New Delhi"},
-	{"delhi", "New Delhi"},
-	{"connaught place", "New Delhi"},
-	{"aerocity", "New Delhi"},
-	{"nehru place", "New Delhi"},
-	{"karol bagh", "New Delhi"},
-	{"vasant kunj", "New Delhi"},
-	{"dwarka", "New Delhi"},
-	{"rohini", "New Delhi"},
-	{"saket", "New Delhi"},
-	{"hauz khas", "New Delhi"},
-	{"india expo mart", "New Delhi"},
-	{"pragati maidan", "New Delhi"},
-	{"bharat mandapam", "New Delhi"},
-	{"yashobhoomi", "New Delhi"},
-
-	// ── Noida ─────────────────────────────────────────────────────────────
-	{"noida", "Noida"},
-	{"greater noida", "Noida"},
-	{"india expo centre", "Noida"},
-
-	// ── Gurugram ──────────────────────────────────────────────────────────
-	{"gurugram", "Gurugram"},
-	{"gurgaon", "Gurugram"},
-	{"cyber hub", "Gurugram"},
-	{"cyberhub", "Gurugram"},
-	{"cyber city", "Gurugram"},
-	{"dlf cyber", "Gurugram"},
-	{"sohna road", "Gurugram"},
-	{"golf course road", "Gurugram"},
-
-	// ── Chennai ───────────────────────────────────────────────────────────
-	{"chennai", "Chennai"},
-	{"madras", "Chennai"},
-	{"t nagar", "Chennai"},
-	{"anna nagar", "Chennai"},
-	{"velachery", "Chennai"},
-	{"adyar", "Chennai"},
-	{"nungambakkam", "Chennai"},
-	{"sholinganallur", "Chennai"},
-	{"perungudi", "Chennai"},
-	{"tidel park", "Chennai"},
-
-	// ── Kolkata ───────────────────────────────────────────────────────────
-	{"kolkata", "Kolkata"},
-	{"calcutta", "Kolkata"},
-	{"salt lake", "Kolkata"},
-	{"new town kolkata", "Kolkata"},
-	{"rajarhat", "Kolkata"},
-	{"park street", "Kolkata"},
-	{"sector v", "Kolkata"},
-
-	// ── Ahmedabad ─────────────────────────────────────────────────────────
-	{"ahmedabad", "Ahmedabad"},
-	{"sg highway", "Ahmedabad"},
-	{"cg road", "Ahmedabad"},
-	{"navrangpura", "Ahmedabad"},
-	{"prahlad nagar", "Ahmedabad"},
-	{"bodakdev", "Ahmedabad"},
-
-	// ── Jaipur ────────────────────────────────────────────────────────────
-	{"jaipur", "Jaipur"},
-	{"malviya nagar jaipur", "Jaipur"},
-	{"vaishali nagar", "Jaipur"},
-	{"sitapura", "Jaipur"},
-	{"jecc", "Jaipur"},
-
-	// ── Online ────────────────────────────────────────────────────────────
-	{"online", "Online"},
-	{"virtual", "Online"},
-	{"remote", "Online"},
-	{"zoom", "Online"},
-	{"webinar", "Online"},
-}
-
-func extractCity(location string) string {
-	lower := strings.ToLower(location)
-	for _, ck := range cityKeywords {
-		if strings.Contains(lower, ck.keyword) {
-			return ck.display
-		}
-	}
-	return ""
-}
-
-func cityToCondition(alias string, city string, argIdx int) (string, []interface{}) {
-	var patterns []string
-	var args []interface{}
-
-	for _, ck := range cityKeywords {
-		if ck.display == city {
-			patterns = append(patterns, fmt.Sprintf("%s.location ILIKE $%d", alias, argIdx))
-			args = append(args, "%"+ck.keyword+"%")
-			argIdx++
-		}
-	}
-
-	if len(patterns) == 0 {
-		return fmt.Sprintf("%s.location ILIKE $%d", alias, argIdx), []interface{}{"%" + city + "%"}
-	}
-
-	return "(" + strings.Join(patterns, " OR ") + ")", args
-}
-
 // ─── Models ───────────────────────────────────────────────────────────────────
 
 // ✅ city_normalized added — canonical city name for display on cards/detail
@@ -273,6 +78,13 @@ type Event struct {
 	Summary       string   `json:"summary,omitempty"`
 	Highlights    []string `json:"highlights,omitempty"`
 
+	// From the offline gazetteer; absent for online and unplaced events.
+	// GeoConfidence is venue, locality or city (the city's centre).
+	Lat           *float64 `json:"lat,omitempty"`
+	Lng           *float64 `json:"lng,omitempty"`
+	GeoConfidence string   `json:"geo_confidence,omitempty"`
+	// Set when searching near a point.
+	DistanceKm *float64 `json:"distance_km,omitempty"`
 }
 
 type EventDetail struct {
@@ -294,21 +106,30 @@ type EventDetail struct {
 }
 
 type EventsResponse struct {
-	Events     []Event  `json:"events"`
-	Total      int      `json:"total"`
-	Page       int      `json:"page"`
-	Limit      int      `json:"limit"`
-	TotalPages int      `json:"total_pages"`
-	Locations  []string `json:"locations"`
-	Sources    []string `json:"sources"`
+	Events     []Event `json:"events"`
+	Sort       string  `json:"sort"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	Total      int     `json:"total"`
+	// Page is 0 when paging by cursor.
+	Page       int     `json:"page"`
+	Limit      int     `json:"limit"`
+	TotalPages int     `json:"total_pages"`
+	Facets     *Facets `json:"facets"`
+	// Values of the location and source facets, for older clients.
+	Locations []string `json:"locations"`
+	Sources   []string `json:"sources"`
 }
 
 type User struct {
-	ID           string    `json:"id"`
-	FullName     string    `json:"full_name"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           string     `json:"id"`
+	FullName     string     `json:"full_name"`
+	Email        string     `json:"email"`
+	Role         roles.Role `json:"role"`
+	PasswordHash string     `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+
+	EmailVerified   bool       `json:"email_verified"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
 }
 
 type SignupRequest struct {
@@ -322,15 +143,26 @@ type SigninRequest struct {
 	Password string `json:"password"`
 }
 
+// Token is the access token; ExpiresIn is its lifetime in seconds.
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	User         User   `json:"user"`
 }
 
 // ─── Server ───────────────────────────────────────────────────────────────────
 
 type Server struct {
-	db *sql.DB
+	db        *sql.DB
+	stream    *stream.Broker
+	publisher stream.Publisher
+	mailer    notify.Mailer
+	// sso is nil unless OIDC_* is configured.
+	sso *sso.Provider
+	// limits are nil when switched off (see ratelimit.go).
+	apiLimits  *ratelimit.Limiter
+	authLimits *ratelimit.Limiter
 }
 
 func main() {
@@ -438,37 +270,186 @@ func main() {
 		log.Println("✅ Saved events table ready")
 	}
 
-	s := &Server{db: db}
-
-	mux := http.NewServeMux()
-	mux.HandleFunc("/api/events", s.withCORS(s.handleEvents))
-	mux.HandleFunc("/api/events/filters", s.withCORS(s.handleFilters))
-	mux.HandleFunc("/api/auth/signup", s.withCORS(s.handleSignup))
-	mux.HandleFunc("/api/auth/signin", s.withCORS(s.handleSignin))
-	mux.HandleFunc("/api/auth/me", s.withCORS(s.handleMe))
-	mux.HandleFunc("/api/events/", s.withCORS(s.handleEventRoutes))
-	mux.HandleFunc("/api/saved-events", s.withCORS(s.requireAuth(s.handleGetSavedEvents)))
-	mux.HandleFunc("/api/scrape/details", s.withCORS(s.handleManualDetailScrape))
-	mux.HandleFunc("/api/admin/scraper-health", s.withCORS(s.handleScraperHealth))
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(200)
-		w.Write([]byte(`{"status":"ok"}`))
-	})
+	if err := webhooks.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure webhook tables: %v", err)
+	} else {
+		log.Println("✅ Webhook tables ready")
+	}
+
+	if err := notify.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure notifications table: %v", err)
+	} else {
+		log.Println("✅ Notifications table ready")
+	}
+
+	if err := reminders.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure reminder tables: %v", err)
+	} else {
+		log.Println("✅ Reminder tables ready")
+	}
+
+	if err := geo.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure event_geo table: %v", err)
+	} else {
+		log.Println("✅ Event geo table ready")
+	}
+
+	if err := sessions.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure session tables: %v", err)
+	} else if n, err := sessions.Purge(db); err != nil {
+		log.Printf("⚠️  Could not purge old sessions: %v", err)
+	} else {
+		log.Printf("✅ Session tables ready (purged %d ended sessions)", n)
+	}
+
+	if err := roles.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure users.role column: %v", err)
+	} else {
+		log.Println("✅ User roles ready")
+	}
+
+	if err := apikeys.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure api_keys table: %v", err)
+	} else {
+		log.Println("✅ API keys table ready")
+	}
+
+	if err := accounts.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure account_tokens table: %v", err)
+	} else if _, err := accounts.Purge(db); err != nil {
+		log.Printf("⚠️  Could not purge expired account tokens: %v", err)
+	} else {
+		log.Println("✅ Account tokens table ready")
+	}
+
+	if err := sso.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure SSO tables: %v", err)
+	} else {
+		log.Println("✅ SSO tables ready")
+	}
+
+	if err := lockout.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure signin_failures table: %v", err)
+	} else if _, err := lockout.Purge(db); err != nil {
+		log.Printf("⚠️  Could not purge old sign-in failures: %v", err)
+	} else {
+		log.Println("✅ Sign-in lockout table ready")
+	}
+
+	if err := interests.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure user_interests table: %v", err)
+	} else {
+		log.Println("✅ User interests table ready")
+	}
+
+	if err := similar.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure event_similar table: %v", err)
+	} else {
+		log.Println("✅ Similar events cache ready")
+	}
+
+	if err := cosaved.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure also-saved tables: %v", err)
+	} else {
+		log.Println("✅ Also-saved tables ready")
+	}
+
+	if err := collections.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure collection tables: %v", err)
+	} else {
+		log.Println("✅ Collection tables ready")
+	}
+
+	if err := rsvp.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure saved event statuses: %v", err)
+	} else {
+		log.Println("✅ Saved event statuses ready")
+	}
+
+	if err := reviews.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure review tables: %v", err)
+	} else {
+		log.Println("✅ Review tables ready")
+	}
+
+	if err := popularity.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure popularity tables: %v", err)
+	} else {
+		log.Println("✅ Popularity tables ready")
+	}
+
+	if err := gazetteer.EnsureSchema(db); err != nil {
+		log.Printf("⚠️  Could not ensure gazetteer_aliases table: %v", err)
+	} else if err := gazetteer.Refresh(db); err != nil {
+		log.Printf("⚠️  Could not load gazetteer aliases: %v", err)
+	} else {
+		log.Println("✅ Gazetteer aliases loaded")
+	}
+
+	s := &Server{db: db, stream: stream.NewBroker(256), mailer: notify.NewMailerFromEnv()}
+	if cfg, ok := sso.ConfigFromEnv(appURL); ok {
+		s.sso = sso.New(cfg)
+		log.Printf("✅ Single sign-on through %s", cfg.Issuer)
+	}
+	s.apiLimits = newLimiter("RATE_LIMIT", defaultAPIRate)
+	s.authLimits = newLimiter("RATE_LIMIT_AUTH", defaultAuthRate)
+
+	// Fan out through LISTEN/NOTIFY so a scheduler in another process reaches
+	// our subscribers; without a listener, publish straight to the broker.
+	if err := stream.Listen(context.Background(), connStr, s.stream); err != nil {
+		log.Printf("⚠️  Could not listen for stream notifications: %v", err)
+		s.publisher = s.stream
+	} else {
+		log.Println("✅ Listening for stream notifications")
+		s.publisher = stream.NewPGNotifier(db)
+	}
+
+	notifier := notify.NewNotifier(
+		notify.NewEmailChannel(s.mailer),
+		notify.NewInAppChannel(db),
+		notify.NewWebhookChannel(db),
+	)
+
+	go webhooks.NewDispatcher(db).Run(context.Background())
+	go reminders.NewDispatcher(db, notifier).Run(context.Background())
+	go geo.NewGeocoder(db).Run(context.Background())
+	go cosaved.NewBuilder(db).Run(context.Background())
+	go popularity.NewBuilder(db).Run(context.Background())
+
+	rt := s.routes()
+
+	// The spec in api/openapi.yaml must describe every route above.
+	if problems, err := api.CheckRoutes(rt.mux, rt.patterns); err != nil {
+		log.Printf("⚠️  Could not load OpenAPI spec: %v", err)
+	} else if len(problems) > 0 {
+		for _, p := range problems {
+			log.Printf("⚠️  OpenAPI drift: %s", p)
+		}
+	} else {
+		log.Println("✅ OpenAPI spec covers all routes")
+	}
+
+	var handler http.Handler = withRequestID(s.withCORS(s.withRateLimit(withFallback(rt.mux))))
+	if getEnv("OPENAPI_VALIDATE", "") == "true" {
+		validated, err := api.ValidateResponses(handler, func(r *http.Request, err error) {
+			log.Printf("⚠️  OpenAPI: %v", err)
+		})
+		if err != nil {
+			log.Printf("⚠️  Could not enable response validation: %v", err)
+		} else {
+			log.Println("✅ Validating responses against the OpenAPI spec")
+			handler = validated
+		}
+	}
 
 	port := getEnv("PORT", "8080")
 	log.Printf("🚀 API server running at http://localhost:%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, mux))
+	log.Fatal(http.ListenAndServe(":"+port, handler))
 }
 
 // ─── Auth Handlers ────────────────────────────────────────────────────────────
 
 func (s *Server) handleSignup(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req SignupRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		jsonError(w, "Invalid request body", 400)
@@ -489,7 +470,7 @@ func (s *Server) handleSignup(w http.ResponseWriter, r *http.Request) {
 
 	var exists bool
 	if err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email=$1)", req.Email).Scan(&exists); err != nil {
-		jsonError(w, "Server error", 500)
+		serverError(w, "Server error", err)
 		return
 	}
 	if exists {
@@ -499,7 +480,7 @@ func (s *Server) handleSignup(w http.ResponseWriter, r *http.Request) {
 
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		jsonError(w, "Server error", 500)
+		serverError(w, "Server error", err)
 		return
 	}
 
@@ -507,30 +488,27 @@ func (s *Server) handleSignup(w http.ResponseWriter, r *http.Request) {
 	err = s.db.QueryRow(
 		`INSERT INTO users (full_name, email, password_hash)
 		 VALUES ($1, $2, $3)
-		 RETURNING id::text, full_name, email, created_at`,
+		 RETURNING id::text, full_name, email, role, created_at`,
 		req.FullName, req.Email, string(hash),
-	).Scan(&user.ID, &user.FullName, &user.Email, &user.CreatedAt)
+	).Scan(&user.ID, &user.FullName, &user.Email, &user.Role, &user.CreatedAt)
 	if err != nil {
-		log.Printf("Signup DB error: %v", err)
-		jsonError(w, "Failed to create account", 500)
+		serverError(w, "Failed to create account", err)
 		return
 	}
+	if err := s.sendAccountMail(user, accounts.PurposeVerifyEmail, "/verify-email"); err != nil {
+		log.Printf("⚠️  Could not send verification email: %v", err)
+	}
 
-	token, err := generateJWT(user.ID, user.Email)
+	resp, err := s.issueTokens(r, user)
 	if err != nil {
-		jsonError(w, "Failed to generate token", 500)
+		serverError(w, "Failed to generate token", err)
 		return
 	}
 
-	jsonOK(w, AuthResponse{Token: token, User: user})
+	jsonOK(w, resp)
 }
 
 func (s *Server) handleSignin(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	var req SigninRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		jsonError(w, "Invalid request body", 400)
@@ -543,71 +521,58 @@ func (s *Server) handleSignin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var user User
-	err := s.db.QueryRow(
-		`SELECT id::text, full_name, email, password_hash, created_at FROM users WHERE email=$1`,
-		req.Email,
-	).Scan(&user.ID, &user.FullName, &user.Email, &user.PasswordHash, &user.CreatedAt)
-
-	if errors.Is(err, sql.ErrNoRows) {
-		jsonError(w, "Invalid email or password", 401)
-		return
-	}
+	locked, err := lockout.Locked(s.db, req.Email)
 	if err != nil {
-		jsonError(w, "Server error", 500)
-		return
-	}
-
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		jsonError(w, "Invalid email or password", 401)
+		serverError(w, "Server error", err)
 		return
 	}
-
-	token, err := generateJWT(user.ID, user.Email)
-	if err != nil {
-		jsonError(w, "Failed to generate token", 500)
+	if locked > 0 {
+		tooManyRequests(w, locked, "Too many failed sign-ins - try again in "+humanDuration(locked))
 		return
 	}
 
-	jsonOK(w, AuthResponse{Token: token, User: user})
-}
+	var user User
+	err = s.db.QueryRow(
+		`SELECT id::text, full_name, email, role, password_hash, created_at, email_verified_at FROM users WHERE email=$1`,
+		req.Email,
+	).Scan(&user.ID, &user.FullName, &user.Email, &user.Role, &user.PasswordHash, &user.CreatedAt, &user.EmailVerifiedAt)
+	user.EmailVerified = user.EmailVerifiedAt != nil
 
-func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		serverError(w, "Server error", err)
 		return
 	}
 
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-		jsonError(w, "Unauthorized", 401)
+	// Unknown addresses count as failures too, so a lockout does not tell
+	// whether an account exists.
+	if err != nil || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		if _, err := lockout.Fail(s.db, req.Email); err != nil {
+			log.Printf("⚠️  Could not record a failed sign-in: %v", err)
+		}
+		jsonError(w, "Invalid email or password", 401)
 		return
 	}
-
-	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-	claims, err := parseJWT(tokenStr)
-	if err != nil {
-		jsonError(w, "Invalid or expired token", 401)
-		return
+	if err := lockout.Clear(s.db, req.Email); err != nil {
+		log.Printf("⚠️  Could not clear failed sign-ins: %v", err)
 	}
 
-	userID, ok := claims["user_id"].(string)
-	if !ok || userID == "" {
-		jsonError(w, "Invalid token", 401)
+	resp, err := s.issueTokens(r, user)
+	if err != nil {
+		serverError(w, "Failed to generate token", err)
 		return
 	}
 
-	var user User
-	err = s.db.QueryRow(
-		`SELECT id::text, full_name, email, created_at FROM users WHERE id=$1`, userID,
-	).Scan(&user.ID, &user.FullName, &user.Email, &user.CreatedAt)
+	jsonOK(w, resp)
+}
 
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
+	user, err := s.getUser(getUserID(r))
 	if errors.Is(err, sql.ErrNoRows) {
 		jsonError(w, "User not found", 404)
 		return
 	}
 	if err != nil {
-		jsonError(w, "Server error", 500)
+		serverError(w, "Server error", err)
 		return
 	}
 
@@ -616,11 +581,14 @@ func (s *Server) handleMe(w http.ResponseWriter, r *http.Request) {
 
 // ─── JWT Helpers ──────────────────────────────────────────────────────────────
 
-func generateJWT(userID string, email string) (string, error) {
+// generateJWT issues an access token for a session.
+func generateJWT(user User, sessionID string) (string, error) {
 	claims := jwt.MapClaims{
-		"user_id": userID,
-		"email":   email,
-		"exp":     time.Now().Add(72 * time.Hour).Unix(),
+		"user_id": user.ID,
+		"email":   user.Email,
+		"role":    string(user.Role),
+		"sid":     sessionID,
+		"exp":     time.Now().Add(accessTokenTTL).Unix(),
 		"iat":     time.Now().Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -687,19 +655,90 @@ func eventSelectCols(eAlias, edAlias string) string {
 	)
 }
 
-// GET /api/events
+// cleanedEventCols is the SELECT column list for an event with its
+// LLM-cleaned values layered over the scraped ones. Expects the aliases
+// e (events), ed (event_details), ec (event_cleaned) and eg (event_geo);
+// scan the row with scanCleanedEvent.
+const cleanedEventCols = `
+		e.id,
+		COALESCE(ec.title_clean, e.event_name) as event_name,
+		e.location,
+		COALESCE(ec.location_clean, e.city_normalized) as city_normalized,
+		e.date_time,
+		COALESCE(ec.date_clean, e.date) as date,
+		COALESCE(ec.time_clean, e.time) as time,
+		e.website,
+		COALESCE(ec.description_clean, e.description) as description,
+		COALESCE(ec.address_clean, e.address) as address,
+		e.event_type,
+		e.platform,
+		COALESCE(ed.image_url, '') as image_url,
+		e.created_at,
+		COALESCE(ec.title_clean, '') as title_clean,
+		COALESCE(ec.date_clean, '') as date_clean,
+		COALESCE(ec.time_clean, '') as time_clean,
+		COALESCE(ec.location_clean, '') as location_clean,
+		COALESCE(ec.address_clean, '') as address_clean,
+		COALESCE(ec.tech_stack, '{}') as tech_stack,
+		COALESCE(ec.speakers, '{}') as speakers,
+		COALESCE(ec.organizer, '') as organizer,
+		COALESCE(ec.price, '') as price,
+		COALESCE(ec.confidence, 0) as confidence,
+		COALESCE(ec.summary, '') as summary,
+		COALESCE(ec.highlights, '{}') as highlights,
+		eg.lat, eg.lng,
+		COALESCE(eg.confidence, '') as geo_confidence
+`
+
+// scanCleanedEvent scans cleanedEventCols into e, followed by any extra
+// columns the query selects after them.
+func scanCleanedEvent(row interface {
+	Scan(...interface{}) error
+}, e *Event, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&e.ID, &e.EventName, &e.Location, &e.CityNormalized,
+		&e.DateTime, &e.Date, &e.Time,
+		&e.Website, &e.Description, &e.Address,
+		&e.EventType, &e.Platform, &e.ImageURL,
+		&e.CreatedAt,
+		&e.TitleClean, &e.DateClean, &e.TimeClean,
+		&e.LocationClean, &e.AddressClean,
+		pq.Array(&e.TechStack), pq.Array(&e.Speakers),
+		&e.Organizer, &e.Price, &e.Confidence,
+		&e.Summary, pq.Array(&e.Highlights),
+		&e.Lat, &e.Lng, &e.GeoConfidence,
+	}, extra...)...)
+}
+
+// GET /api/v1/events
 func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
+	q := r.URL.Query()
+	filter := EventFilter{
+		Search:    strings.TrimSpace(q.Get("q")),
+		Location:  strings.TrimSpace(q.Get("location")),
+		Source:    strings.TrimSpace(q.Get("source")),
+		DateFrom:  strings.TrimSpace(q.Get("from")),
+		DateTo:    strings.TrimSpace(q.Get("to")),
+		Tech:      queryValues(q, "tech"),
+		Organizer: queryValues(q, "organizer"),
+		EventType: queryValues(q, "event_type"),
+	}
+	price, err := parsePriceFilter(queryValues(q, "price"))
+	if err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	filter.Price = price
+	if filter.Near, err = parseNearFilter(q); err != nil {
+		jsonError(w, err.Error(), 400)
 		return
 	}
 
-	q := r.URL.Query()
-	search := strings.TrimSpace(q.Get("q"))
-	location := strings.TrimSpace(q.Get("location"))
-	source := strings.TrimSpace(q.Get("source"))
-	dateFrom := strings.TrimSpace(q.Get("from"))
-	dateTo := strings.TrimSpace(q.Get("to"))
+	sort, err := parseEventSort(q.Get("sort"))
+	if err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
 
 	page, _ := strconv.Atoi(q.Get("page"))
 	if page < 1 {
@@ -709,261 +748,189 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 	if limit < 1 || limit > 100 {
 		limit = 8
 	}
-	offset := (page - 1) * limit
+	cursor := strings.TrimSpace(q.Get("cursor"))
 
+	result, err := s.listEvents(EventQuery{
+		Filter: filter,
+		Sort:   sort,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+		Cursor: cursor,
+	})
+	if errors.Is(err, ErrBadCursor) {
+		jsonError(w, "Invalid cursor; restart from the first page", 400)
+		return
+	}
+	if errors.Is(err, ErrDistanceSort) {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to fetch events", err)
+		return
+	}
+	total := result.Total
+	if cursor != "" {
+		page = 0
+	}
+
+	facets := s.eventFacets(filter)
+
+	totalPages := (total + limit - 1) / limit
+	if totalPages < 1 {
+		totalPages = 1
+	}
+
+	jsonOK(w, EventsResponse{
+		Events:     result.Events,
+		Sort:       result.Sort,
+		NextCursor: result.NextCursor,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		Facets:     facets,
+		Locations:  facetValues(facets.Location),
+		Sources:    facetValues(facets.Source),
+	})
+}
+
+// EventFilter holds the /api/events query filters. Empty fields match all.
+type EventFilter struct {
+	Search   string
+	Location string
+	Source   string
+	DateFrom string
+	DateTo   string
+	// Multi-valued; any value matches, case-insensitively.
+	Tech      []string
+	Price     []string // PriceFree, PricePaid, PriceUnknown
+	Organizer []string
+	EventType []string
+	Near      NearFilter
+}
+
+// where builds the WHERE clause over the events alias "inner_e" and returns
+// it with its arguments. Placeholders start at $1.
+func (f EventFilter) where() (string, []interface{}) {
 	conditions := []string{"1=1"}
 	args := []interface{}{}
 	idx := 1
 
-	if search != "" {
+	if f.Search != "" {
 		conditions = append(conditions, fmt.Sprintf(
 			"(inner_e.event_name ILIKE $%d OR inner_e.description ILIKE $%d OR inner_e.location ILIKE $%d)",
 			idx, idx, idx,
 		))
-		args = append(args, "%"+search+"%")
+		args = append(args, "%"+f.Search+"%")
 		idx++
 	}
 
-	// ✅ Filter on city_normalized for clean city matching
-	if location != "" {
-		conditions = append(conditions, fmt.Sprintf("inner_e.city_normalized = $%d", idx))
-		args = append(args, location)
+	// ✅ Filter on city_normalized for clean city matching, in any case
+	if f.Location != "" {
+		conditions = append(conditions, fmt.Sprintf("lower(inner_e.city_normalized) = lower($%d)", idx))
+		args = append(args, f.Location)
 		idx++
 	}
 
-	if source != "" {
+	if f.Source != "" {
 		conditions = append(conditions, fmt.Sprintf("inner_e.platform = $%d", idx))
-		args = append(args, source)
+		args = append(args, f.Source)
 		idx++
 	}
-	if dateFrom != "" {
+	if f.DateFrom != "" {
 		conditions = append(conditions, fmt.Sprintf("inner_e.date >= $%d", idx))
-		args = append(args, dateFrom)
+		args = append(args, f.DateFrom)
 		idx++
 	}
-	if dateTo != "" {
+	if f.DateTo != "" {
 		conditions = append(conditions, fmt.Sprintf("inner_e.date <= $%d", idx))
-		args = append(args, dateTo)
+		args = append(args, f.DateTo)
 		idx++
 	}
 
-	where := "WHERE " + strings.Join(conditions, " AND ")
-
-	var total int
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM events inner_e %s", where)
-	if err := s.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
-		jsonError(w, "Failed to count events: "+err.Error(), 500)
-		return
+	if len(f.Tech) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM event_cleaned ec_f, unnest(ec_f.tech_stack) AS t(tech)
+			WHERE ec_f.event_id = inner_e.id AND lower(btrim(t.tech)) = ANY($%d))`, idx))
+		args = append(args, pq.Array(lowerAll(f.Tech)))
+		idx++
 	}
-
-	eventsQuery := fmt.Sprintf(`
-		SELECT 
-			e.id,
-			COALESCE(ec.title_clean, e.event_name) as event_name,
-			e.location,
-			COALESCE(ec.location_clean, e.city_normalized) as city_normalized,
-			e.date_time,
-			COALESCE(ec.date_clean, e.date) as date,
-			COALESCE(ec.time_clean, e.time) as time,
-			e.website,
-			COALESCE(ec.description_clean, e.description) as description,
-			COALESCE(ec.address_clean, e.address) as address,
-			e.event_type,
-			e.platform,
-			COALESCE(ed.image_url, '') as image_url,
-			e.created_at,
-			COALESCE(ec.title_clean, '') as title_clean,
-			COALESCE(ec.date_clean, '') as date_clean,
-			COALESCE(ec.time_clean, '') as time_clean,
-			COALESCE(ec.location_clean, '') as location_clean,
-			COALESCE(ec.address_clean, '') as address_clean,
-			COALESCE(ec.tech_stack, '{}') as tech_stack,
-			COALESCE(ec.speakers, '{}') as speakers,
-			COALESCE(ec.organizer, '') as organizer,
-			COALESCE(ec.price, '') as price,
-			COALESCE(ec.confidence, 0) as confidence,
-			COALESCE(ec.summary, '') as summary,
-			COALESCE(ec.highlights, '{}') as highlights
-		FROM (
-			SELECT inner_e.*,
-				ROW_NUMBER() OVER (
-					PARTITION BY inner_e.platform
-					ORDER BY
-						CASE WHEN inner_e.date ~ '^\d{4}-\d{2}-\d{2}$'
-							THEN inner_e.date::date
-							ELSE CURRENT_DATE + INTERVAL '100 years'
-						END ASC,
-						inner_e.created_at DESC
-				) AS platform_rank
-			FROM events inner_e
-			%s
-		) e
-		LEFT JOIN event_details ed ON e.id = ed.event_id
-		LEFT JOIN event_cleaned ec ON e.id = ec.event_id
-		ORDER BY
-			e.platform_rank ASC,
-			CASE WHEN e.date ~ '^\d{4}-\d{2}-\d{2}$'
-				THEN e.date::date
-				ELSE CURRENT_DATE + INTERVAL '100 years'
-			END ASC,
-			e.platform ASC
-		LIMIT $%d OFFSET $%d
-	`, where, idx, idx+1)
-
-	rows, err := s.db.Query(eventsQuery, append(args, limit, offset)...)
-	if err != nil {
-		jsonError(w, "Failed to fetch events: "+err.Error(), 500)
-		return
+	if len(f.Price) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`COALESCE((
+			SELECT %s FROM event_cleaned ec_f WHERE ec_f.event_id = inner_e.id
+		), 'unknown') = ANY($%d)`, priceBucketExpr("ec_f.price"), idx))
+		args = append(args, pq.Array(f.Price))
+		idx++
 	}
-	defer rows.Close()
-
-	events := []Event{}
-	for rows.Next() {
-		var e Event
-		// Initialize slices for scanning
-		var techStack, speakers, highlights []string
-		
-		err := rows.Scan(
-			&e.ID, 
-			&e.EventName, 
-			&e.Location, 
-			&e.CityNormalized,
-			&e.DateTime, 
-			&e.Date, 
-			&e.Time,
-			&e.Website, 
-			&e.Description, 
-			&e.Address,
-			&e.EventType, 
-			&e.Platform, 
-			&e.ImageURL,
-			&e.CreatedAt,
-			&e.TitleClean, 
-			&e.DateClean, 
-			&e.TimeClean,
-			&e.LocationClean, 
-			&e.AddressClean,
-			pq.Array(&techStack), 
-			pq.Array(&speakers),
-			&e.Organizer, 
-			&e.Price, 
-			&e.Confidence,
-			&e.Summary, 
-			pq.Array(&highlights),
-		)
-		
-		if err != nil {
-			log.Printf("Row scan error: %v", err)
-			continue
-		}
-		
-		// Assign arrays
-		e.TechStack = techStack
-		e.Speakers = speakers
-		e.Highlights = highlights
-		
-		events = append(events, e)
+	if len(f.Organizer) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM event_cleaned ec_f
+			WHERE ec_f.event_id = inner_e.id AND lower(btrim(ec_f.organizer)) = ANY($%d))`, idx))
+		args = append(args, pq.Array(lowerAll(f.Organizer)))
+		idx++
 	}
-
-	// ✅ Locations from city_normalized column — clean city names only
-	locations := s.distinctCities()
-	sources := s.distinctValues("platform")
-
-	totalPages := (total + limit - 1) / limit
-	if totalPages < 1 {
-		totalPages = 1
+	if len(f.EventType) > 0 {
+		conditions = append(conditions, fmt.Sprintf("lower(btrim(inner_e.event_type)) = ANY($%d)", idx))
+		args = append(args, pq.Array(lowerAll(f.EventType)))
+		idx++
 	}
-
-	jsonOK(w, EventsResponse{
-		Events:     events,
-		Total:      total,
-		Page:       page,
-		Limit:      limit,
-		TotalPages: totalPages,
-		Locations:  locations,
-		Sources:    sources,
-	})
+	if f.Near.active() {
+		// The bounding box lets idx_event_geo_point narrow the rows before
+		// the exact distance check.
+		dLat, dLng := f.Near.box()
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM event_geo g_f
+			WHERE g_f.event_id = inner_e.id
+			  AND g_f.lat BETWEEN $%[1]d::float8 - %[3]g AND $%[1]d::float8 + %[3]g
+			  AND g_f.lng BETWEEN $%[2]d::float8 - %[4]g AND $%[2]d::float8 + %[4]g
+			  AND %[5]s <= $%[6]d)`,
+			idx, idx+1, dLat, dLng,
+			geo.DistanceSQL("g_f.lat", "g_f.lng", fmt.Sprintf("$%d::float8", idx), fmt.Sprintf("$%d::float8", idx+1)),
+			idx+2))
+		args = append(args, f.Near.Lat, f.Near.Lng, f.Near.RadiusKm)
+		idx += 3
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
 }
 
-// GET /api/events/filters
+// GET /api/v1/events/filters
+//
+// Facet counts over all events, for building filter controls before the
+// first search.
 func (s *Server) handleFilters(w http.ResponseWriter, r *http.Request) {
+	facets := s.eventFacets(EventFilter{})
 	jsonOK(w, map[string]interface{}{
-		"locations": s.distinctCities(),
-		"sources":   s.distinctValues("platform"),
+		"facets":    facets,
+		"locations": facetValues(facets.Location),
+		"sources":   facetValues(facets.Source),
 	})
 }
 
-// GET /api/events/:id
+// GET /api/v1/events/{id}
 func (s *Server) handleEventDetail(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	path := strings.TrimPrefix(r.URL.Path, "/api/events/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 1 || parts[0] == "" {
-		jsonError(w, "Invalid event ID", 400)
-		return
-	}
-
-	eventID, err := strconv.ParseInt(parts[0], 10, 64)
+	eventID, err := pathID(r, "id")
 	if err != nil {
 		jsonError(w, "Invalid event ID", 400)
 		return
 	}
 
 	var e Event
-	err = s.db.QueryRow(`
-		SELECT 
-			e.id,
-			COALESCE(ec.title_clean, e.event_name) as event_name,
-			e.location,
-			COALESCE(ec.location_clean, e.city_normalized) as city_normalized,
-			e.date_time,
-			COALESCE(ec.date_clean, e.date) as date,
-			COALESCE(ec.time_clean, e.time) as time,
-			e.website,
-			COALESCE(ec.description_clean, e.description) as description,
-			COALESCE(ec.address_clean, e.address) as address,
-			e.event_type,
-			e.platform,
-			COALESCE(ed.image_url, '') as image_url,
-			e.created_at,
-			COALESCE(ec.title_clean, '') as title_clean,
-			COALESCE(ec.date_clean, '') as date_clean,
-			COALESCE(ec.time_clean, '') as time_clean,
-			COALESCE(ec.location_clean, '') as location_clean,
-			COALESCE(ec.address_clean, '') as address_clean,
-			COALESCE(ec.tech_stack, '{}') as tech_stack,
-			COALESCE(ec.speakers, '{}') as speakers,
-			COALESCE(ec.organizer, '') as organizer,
-			COALESCE(ec.price, '') as price,
-			COALESCE(ec.confidence, 0) as confidence,
-			COALESCE(ec.summary, '') as summary,
-			COALESCE(ec.highlights, '{}') as highlights
+	err = scanCleanedEvent(s.db.QueryRow(`
+		SELECT `+cleanedEventCols+`
 		FROM events e
 		LEFT JOIN event_details ed ON e.id = ed.event_id
 		LEFT JOIN event_cleaned ec ON e.id = ec.event_id
+		LEFT JOIN event_geo eg ON e.id = eg.event_id
 		WHERE e.id = $1
-	`, eventID).Scan(
-		&e.ID, &e.EventName, &e.Location, &e.CityNormalized,
-		&e.DateTime, &e.Date, &e.Time,
-		&e.Website, &e.Description, &e.Address,
-		&e.EventType, &e.Platform, &e.ImageURL,
-		&e.CreatedAt,
-		&e.TitleClean, &e.DateClean, &e.TimeClean,
-		&e.LocationClean, &e.AddressClean,
-		pq.Array(&e.TechStack), pq.Array(&e.Speakers),
-		&e.Organizer, &e.Price, &e.Confidence,
-		&e.Summary, pq.Array(&e.Highlights),
-	)
+	`, eventID), &e)
 	if errors.Is(err, sql.ErrNoRows) {
 		jsonError(w, "Event not found", 404)
 		return
 	}
 	if err != nil {
-		jsonError(w, "Database error: "+err.Error(), 500)
+		serverError(w, "Failed to load event", err)
 		return
 	}
 
@@ -986,7 +953,7 @@ func (s *Server) handleEventDetail(w http.ResponseWriter, r *http.Request) {
 	if err == nil {
 		detailPtr = &detail
 	} else if !errors.Is(err, sql.ErrNoRows) {
-		jsonError(w, "Database error fetching details: "+err.Error(), 500)
+		serverError(w, "Failed to load event details", err)
 		return
 	}
 
@@ -1000,108 +967,61 @@ func (s *Server) handleEventDetail(w http.ResponseWriter, r *http.Request) {
 		isSaved = count > 0
 	}
 
-	var recommendedCount int
-	s.db.QueryRow(`
-		SELECT COUNT(*) FROM events
-		WHERE id != $1 AND platform = $2 AND location ILIKE $3
-		LIMIT 10
-	`, eventID, e.Platform, "%"+e.Location+"%").Scan(&recommendedCount)
+	recommended, _ := s.similarEvents(eventID, recommendedLimit)
+	recommendedCount := len(recommended)
+
+	rating, err := reviews.Summarize(s.db, reviews.Filter{EventID: eventID})
+	if err != nil {
+		serverError(w, "Failed to load ratings", err)
+		return
+	}
+
+	s.trackInteraction(r, eventID, popularity.KindView)
 
 	jsonOK(w, map[string]interface{}{
 		"event":             e,
 		"event_detail":      detailPtr,
 		"is_saved":          isSaved,
 		"recommended_count": recommendedCount,
+		"rating":            rating,
 	})
 }
 
-// GET /api/events/:id/recommended
+// GET /api/v1/events/{id}/recommended
+//
+// Upcoming events most like this one (see internal/similar), each with its
+// score and the reasons behind it.
 func (s *Server) handleRecommendedEvents(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	path := strings.TrimPrefix(r.URL.Path, "/api/events/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 1 || parts[0] == "" {
-		jsonOK(w, map[string]interface{}{"events": []Event{}, "total": 0})
-		return
-	}
-
-	eventID, err := strconv.ParseInt(parts[0], 10, 64)
+	eventID, err := pathID(r, "id")
 	if err != nil {
-		jsonOK(w, map[string]interface{}{"events": []Event{}, "total": 0})
+		jsonError(w, "Invalid event ID", 400)
 		return
 	}
 
-	var platform, cityNorm string
-	err = s.db.QueryRow(`
-		SELECT COALESCE(platform, ''), COALESCE(city_normalized, '') FROM events WHERE id = $1
-	`, eventID).Scan(&platform, &cityNorm)
-	if err != nil {
-		jsonOK(w, map[string]interface{}{"events": []Event{}, "total": 0})
+	events, err := s.similarEvents(eventID, recommendedLimit)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "Event not found", 404)
 		return
 	}
-
-	rows, err := s.db.Query(fmt.Sprintf(`
-		SELECT %s
-		FROM events e
-		LEFT JOIN event_details ed ON e.id = ed.event_id
-		WHERE e.id != $1
-		  AND (e.platform = $2 OR e.city_normalized = $3)
-		ORDER BY
-			CASE WHEN e.platform = $2 THEN 0 ELSE 1 END,
-			e.created_at DESC
-		LIMIT 10
-	`, eventSelectCols("e", "ed")), eventID, platform, cityNorm)
 	if err != nil {
-		jsonOK(w, map[string]interface{}{"events": []Event{}, "total": 0})
+		serverError(w, "Failed to load recommendations", err)
 		return
 	}
-	defer rows.Close()
-
-	var events []Event
-	for rows.Next() {
-		var ev Event
-		if err := rows.Scan(
-			&ev.ID, &ev.EventName, &ev.Location, &ev.CityNormalized,
-			&ev.DateTime, &ev.Date, &ev.Time,
-			&ev.Website, &ev.Description, &ev.Address,
-			&ev.EventType, &ev.Platform, &ev.ImageURL,
-			&ev.CreatedAt,
-		); err == nil {
-			events = append(events, ev)
-		}
-	}
-
 	jsonOK(w, map[string]interface{}{
 		"events": events,
 		"total":  len(events),
 	})
 }
 
-// POST /api/events/:id/save
+// POST /api/v1/events/{id}/save
 func (s *Server) handleSaveEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	userID := getUserID(r)
 	if userID == "" {
 		jsonError(w, "Unauthorized", 401)
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/api/events/")
-	parts := strings.Split(path, "/")
-	if len(parts) < 1 {
-		jsonError(w, "Invalid URL", 400)
-		return
-	}
-
-	eventID, err := strconv.ParseInt(parts[0], 10, 64)
+	eventID, err := pathID(r, "id")
 	if err != nil {
 		jsonError(w, "Invalid event ID", 400)
 		return
@@ -1116,41 +1036,96 @@ func (s *Server) handleSaveEvent(w http.ResponseWriter, r *http.Request) {
 
 	var body struct {
 		Notes string `json:"notes"`
+		// ReminderOffsets are minutes before the event starts. Omitted keeps
+		// the current setting (user defaults for a new save); [] disables.
+		ReminderOffsets *[]int64 `json:"reminder_offsets"`
+		// Status is interested, going, attended or skipped. Omitted keeps
+		// the current status (interested for a new save).
+		Status string `json:"status"`
 	}
 	if r.Body != nil {
 		json.NewDecoder(r.Body).Decode(&body)
 	}
 
-	_, err = s.db.Exec(`
-		INSERT INTO saved_events (user_id, event_id, notes, saved_at)
-		VALUES ($1, $2, $3, NOW())
-		ON CONFLICT (user_id, event_id)
-		DO UPDATE SET notes = EXCLUDED.notes, saved_at = NOW()
-	`, userID, eventID, body.Notes)
+	if body.Status != "" {
+		err := rsvp.Check(s.db, eventID, body.Status)
+		if errors.Is(err, rsvp.ErrBadStatus) || errors.Is(err, rsvp.ErrNotStarted) || errors.Is(err, rsvp.ErrNoDate) {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		if err != nil {
+			serverError(w, "Failed to save event", err)
+			return
+		}
+	}
+
+	if body.ReminderOffsets != nil {
+		valid, err := reminders.ValidateOffsets(*body.ReminderOffsets)
+		if err != nil {
+			jsonError(w, err.Error(), 400)
+			return
+		}
+		body.ReminderOffsets = &valid
+	}
+
+	savedEventID, err := s.saveEvent(userID, eventID, body.Notes, body.ReminderOffsets)
 	if err != nil {
-		jsonError(w, "Failed to save event: "+err.Error(), 500)
+		serverError(w, "Failed to save event", err)
 		return
 	}
+	if body.Status != "" {
+		if err := s.setSavedStatus(savedEventID, body.Status); err != nil {
+			serverError(w, "Failed to save event", err)
+			return
+		}
+	}
 
-	jsonOK(w, map[string]interface{}{"message": "Event saved successfully", "saved": true})
+	conflicts, err := s.conflictingEvents(userID, eventID)
+	if err != nil {
+		serverError(w, "Failed to check for conflicts", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "Event saved successfully", "saved": true, "conflicts": conflicts})
 }
 
-// DELETE /api/events/:id/save
-func (s *Server) handleUnsaveEvent(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
+// saveEvent saves (or re-saves) an event for a user and schedules its
+// reminders. A nil offsets keeps the current reminder setting.
+func (s *Server) saveEvent(userID string, eventID int64, notes string, offsets *[]int64) (int64, error) {
+	var offsetsArg interface{}
+	if offsets != nil {
+		offsetsArg = pq.Array(*offsets)
+	}
+
+	var savedEventID int64
+	err := s.db.QueryRow(`
+		INSERT INTO saved_events (user_id, event_id, notes, reminder_offsets, saved_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, event_id)
+		DO UPDATE SET notes = EXCLUDED.notes,
+		              reminder_offsets = CASE WHEN $5 THEN EXCLUDED.reminder_offsets
+		                                      ELSE saved_events.reminder_offsets END,
+		              saved_at = NOW()
+		RETURNING id
+	`, userID, eventID, notes, offsetsArg, offsets != nil).Scan(&savedEventID)
+	if err != nil {
+		return 0, err
 	}
 
+	if err := reminders.ScheduleSavedEvent(s.db, savedEventID); err != nil {
+		log.Printf("Schedule reminders error: %v", err)
+	}
+	return savedEventID, nil
+}
+
+// DELETE /api/v1/events/{id}/save
+func (s *Server) handleUnsaveEvent(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 	if userID == "" {
 		jsonError(w, "Unauthorized", 401)
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/api/events/")
-	parts := strings.Split(path, "/")
-	eventID, err := strconv.ParseInt(parts[0], 10, 64)
+	eventID, err := pathID(r, "id")
 	if err != nil {
 		jsonError(w, "Invalid event ID", 400)
 		return
@@ -1160,7 +1135,7 @@ func (s *Server) handleUnsaveEvent(w http.ResponseWriter, r *http.Request) {
 		DELETE FROM saved_events WHERE user_id = $1 AND event_id = $2
 	`, userID, eventID)
 	if err != nil {
-		jsonError(w, "Failed to unsave event: "+err.Error(), 500)
+		serverError(w, "Failed to unsave event", err)
 		return
 	}
 
@@ -1173,49 +1148,81 @@ func (s *Server) handleUnsaveEvent(w http.ResponseWriter, r *http.Request) {
 	jsonOK(w, map[string]interface{}{"message": "Event unsaved successfully", "saved": false})
 }
 
-// GET /api/saved-events
+// GET /api/v1/saved-events?tag=go&status=going
 func (s *Server) handleGetSavedEvents(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	userID := getUserID(r)
 	if userID == "" {
 		jsonError(w, "Unauthorized", 401)
 		return
 	}
 
+	where := []string{"se.user_id = $1"}
+	args := []interface{}{userID}
+	if tag := strings.TrimSpace(r.URL.Query().Get("tag")); tag != "" {
+		args = append(args, tag)
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(se.tags) t WHERE lower(t) = lower($%d))", len(args)))
+	}
+	if status := r.URL.Query().Get("status"); status != "" {
+		if !rsvp.Valid(status) {
+			jsonError(w, rsvp.ErrBadStatus.Error(), 400)
+			return
+		}
+		args = append(args, status)
+		where = append(where, fmt.Sprintf("se.status = $%d", len(args)))
+	}
+
+	savedEvents, err := s.querySavedEvents("", strings.Join(where, " AND "), "se.saved_at DESC", args...)
+	if err != nil {
+		serverError(w, "Failed to load saved events", err)
+		return
+	}
+
+	jsonOK(w, map[string]interface{}{
+		"saved_events": savedEvents,
+		"total":        len(savedEvents),
+	})
+}
+
+type SavedEventFull struct {
+	ID      int64    `json:"id"`
+	EventID int64    `json:"event_id"`
+	Notes   string   `json:"notes"`
+	Tags    []string `json:"tags"`
+	Status  string   `json:"status"`
+	SavedAt string   `json:"saved_at"`
+	Event   Event    `json:"event"`
+}
+
+// savedEvents returns a user's saved events with their notes, newest first.
+func (s *Server) savedEvents(userID string) ([]SavedEventFull, error) {
+	return s.querySavedEvents("", "se.user_id = $1", "se.saved_at DESC", userID)
+}
+
+// querySavedEvents returns the saved events (alias se) matching where, in
+// orderBy order. join can bring in further tables to filter or sort on.
+func (s *Server) querySavedEvents(join, where, orderBy string, args ...interface{}) ([]SavedEventFull, error) {
 	rows, err := s.db.Query(fmt.Sprintf(`
 		SELECT
-			se.id, se.event_id, COALESCE(se.notes, ''), se.saved_at,
+			se.id, se.event_id, COALESCE(se.notes, ''), se.tags, se.status, se.saved_at,
 			%s
 		FROM saved_events se
 		JOIN events e ON se.event_id = e.id
 		LEFT JOIN event_details ed ON e.id = ed.event_id
-		WHERE se.user_id = $1
-		ORDER BY se.saved_at DESC
-	`, eventSelectCols("e", "ed")), userID)
+		%s
+		WHERE %s
+		ORDER BY %s
+	`, eventSelectCols("e", "ed"), join, where, orderBy), args...)
 	if err != nil {
-		jsonError(w, "Database error: "+err.Error(), 500)
-		return
+		return nil, err
 	}
 	defer rows.Close()
 
-	type SavedEventFull struct {
-		ID      int64  `json:"id"`
-		EventID int64  `json:"event_id"`
-		Notes   string `json:"notes"`
-		SavedAt string `json:"saved_at"`
-		Event   Event  `json:"event"`
-	}
-
-	var savedEvents []SavedEventFull
+	savedEvents := []SavedEventFull{}
 	for rows.Next() {
 		var se SavedEventFull
 		var ev Event
 		err := rows.Scan(
-			&se.ID, &se.EventID, &se.Notes, &se.SavedAt,
+			&se.ID, &se.EventID, &se.Notes, pq.Array(&se.Tags), &se.Status, &se.SavedAt,
 			&ev.ID, &ev.EventName, &ev.Location, &ev.CityNormalized,
 			&ev.DateTime, &ev.Date, &ev.Time,
 			&ev.Website, &ev.Description, &ev.Address,
@@ -1228,20 +1235,11 @@ func (s *Server) handleGetSavedEvents(w http.ResponseWriter, r *http.Request) {
 		se.Event = ev
 		savedEvents = append(savedEvents, se)
 	}
-
-	jsonOK(w, map[string]interface{}{
-		"saved_events": savedEvents,
-		"total":        len(savedEvents),
-	})
+	return savedEvents, rows.Err()
 }
 
-// POST /api/scrape/details
+// POST /api/v1/scrape/details
 func (s *Server) handleManualDetailScrape(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	fmt.Println("\n🚀 Manual detail scraping triggered...")
 
 	detailScraper := scrapers.NewDetailScraper(s.db, 30*time.Second, 3)
@@ -1265,11 +1263,12 @@ func (s *Server) handleManualDetailScrape(w http.ResponseWriter, r *http.Request
 		} else {
 			updated++
 		}
+		s.publishEventUpdated(detail.EventID)
 		return nil
 	})
 
 	if err != nil {
-		jsonError(w, fmt.Sprintf("Scraping failed: %v", err), 500)
+		serverError(w, "Scraping failed", err)
 		return
 	}
 
@@ -1343,11 +1342,6 @@ type ScraperSummary struct {
 }
 
 func (s *Server) handleScraperHealth(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		jsonError(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
 	rows, err := s.db.Query(`
 		SELECT id, scraper_name, success, events_found, events_filtered,
 		       COALESCE(error_message, ''), duration_seconds,
@@ -1361,7 +1355,7 @@ func (s *Server) handleScraperHealth(w http.ResponseWriter, r *http.Request) {
 		ORDER BY scraper_name, run_at DESC
 	`)
 	if err != nil {
-		jsonError(w, "Failed to fetch scraper health: "+err.Error(), 500)
+		serverError(w, "Failed to fetch scraper health", err)
 		return
 	}
 	defer rows.Close()
@@ -1381,7 +1375,7 @@ func (s *Server) handleScraperHealth(w http.ResponseWriter, r *http.Request) {
 		scraperMap[row.ScraperName] = append(scraperMap[row.ScraperName], row)
 	}
 
-	var summaries []ScraperSummary
+	summaries := []ScraperSummary{}
 	for _, name := range orderedNames {
 		runs := scraperMap[name]
 		successCount := 0
@@ -1409,96 +1403,14 @@ func (s *Server) handleScraperHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ─── Routing ──────────────────────────────────────────────────────────────────
-
-func (s *Server) handleEventRoutes(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/api/events/")
-	parts := strings.Split(path, "/")
-
-	if len(parts) < 1 {
-		jsonError(w, "Invalid URL", 400)
-		return
-	}
-
-	if _, err := strconv.ParseInt(parts[0], 10, 64); err != nil {
-		jsonError(w, "Invalid event ID", 400)
-		return
-	}
-
-	if len(parts) == 1 {
-		s.optionalAuth(s.handleEventDetail)(w, r)
-	} else if len(parts) == 2 {
-		switch parts[1] {
-		case "recommended":
-			s.handleRecommendedEvents(w, r)
-		case "save":
-			if r.Method == http.MethodPost {
-				s.requireAuth(s.handleSaveEvent)(w, r)
-			} else if r.Method == http.MethodDelete {
-				s.requireAuth(s.handleUnsaveEvent)(w, r)
-			} else {
-				jsonError(w, "Method not allowed", 405)
-			}
-		default:
-			jsonError(w, "Not found", 404)
-		}
-	} else {
-		jsonError(w, "Not found", 404)
-	}
-}
-
 // ─── Helpers ──────────────────────────────────────────────────────────────────
 
-// ✅ distinctCities now reads from city_normalized column — clean names only
-func (s *Server) distinctCities() []string {
-	rows, err := s.db.Query(`
-		SELECT DISTINCT city_normalized
-		FROM events
-		WHERE city_normalized IS NOT NULL
-		  AND city_normalized != ''
-		  AND city_normalized != 'Unknown'
-		ORDER BY city_normalized ASC
-	`)
-	if err != nil {
-		return []string{}
-	}
-	defer rows.Close()
-
-	var cities []string
-	for rows.Next() {
-		var c string
-		if err := rows.Scan(&c); err == nil && c != "" {
-			cities = append(cities, c)
-		}
-	}
-	return cities
-}
-
-func (s *Server) distinctValues(column string) []string {
-	rows, err := s.db.Query(fmt.Sprintf(
-		"SELECT DISTINCT %s FROM events WHERE %s IS NOT NULL AND %s != '' ORDER BY %s",
-		column, column, column, column,
-	))
-	if err != nil {
-		return []string{}
-	}
-	defer rows.Close()
-	var vals []string
-	for rows.Next() {
-		var v string
-		_ = rows.Scan(&v)
-		if v != "" {
-			vals = append(vals, v)
-		}
-	}
-	return vals
-}
-
 func (s *Server) withCORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, X-Quota-Limit, X-Quota-Remaining, Retry-After")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -1516,44 +1428,35 @@ func getUserID(r *http.Request) string {
 	return userID
 }
 
+// optionalAuth identifies the caller when it sends credentials. Callers
+// without, or with a bad bearer token, are served anonymously; a bad API
+// key is refused. These routes serve event data, so keys need events:read.
 func (s *Server) optionalAuth(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
-			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-			claims, err := parseJWT(tokenStr)
-			if err == nil {
-				if userID, ok := claims["user_id"].(string); ok && userID != "" {
-					ctx := context.WithValue(r.Context(), userIDKey, userID)
-					r = r.WithContext(ctx)
-				}
-			}
-		}
-		next(w, r)
-	}
+	return s.authorize(apikeys.ScopeEventsRead, true, next)
 }
 
+// requireAuth admits signed-in users. API keys are refused: routes that
+// take them use requireScope.
 func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			jsonError(w, "Unauthorized - No token provided", 401)
-			return
-		}
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := parseJWT(tokenStr)
-		if err != nil {
-			jsonError(w, "Invalid or expired token", 401)
-			return
-		}
-		userID, ok := claims["user_id"].(string)
-		if !ok || userID == "" {
-			jsonError(w, "Invalid token claims", 401)
+	return s.authorize("", false, next)
+}
+
+// requireScope is requireAuth that also admits API keys holding scope.
+func (s *Server) requireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return s.authorize(scope, false, next)
+}
+
+// requireRole is requireAuth for users with at least role, and for API keys
+// of such users with the admin scope. Roles come from the access token, so
+// a changed role applies from the next refresh.
+func (s *Server) requireRole(role roles.Role, next http.HandlerFunc) http.HandlerFunc {
+	return s.requireScope(apikeys.ScopeAdmin, func(w http.ResponseWriter, r *http.Request) {
+		if !roleFrom(r.Context()).Includes(role) {
+			jsonError(w, "Forbidden - requires the "+string(role)+" role", 403)
 			return
 		}
-		ctx := context.WithValue(r.Context(), userIDKey, userID)
-		next(w, r.WithContext(ctx))
-	}
+		next(w, r)
+	})
 }
 
 func jsonOK(w http.ResponseWriter, data interface{}) {
@@ -1561,10 +1464,10 @@ func jsonOK(w http.ResponseWriter, data interface{}) {
 	_ = json.NewEncoder(w).Encode(data)
 }
 
-func jsonError(w http.ResponseWriter, msg string, code int) {
+func jsonStatus(w http.ResponseWriter, data interface{}, code int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
-	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+	_ = json.NewEncoder(w).Encode(data)
 }
 
 func getEnv(key, fallback string) string {
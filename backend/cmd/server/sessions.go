@@ -0,0 +1,288 @@
+// backend/cmd/server/sessions.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/roles"
+	"event-scraper/internal/sessions"
+)
+
+// ─── Sessions ─────────────────────────────────────────────────────────────────
+//
+// Signing in returns a short-lived access token (the JWT sent as Bearer)
+// and a refresh token. POST /auth/refresh trades the refresh token for a
+// new pair; the old one is spent, and spending it twice revokes the
+// session. Access tokens name their session in the "sid" claim, so logging
+// out takes effect at once rather than when the token expires.
+
+var accessTokenTTL = envDuration("ACCESS_TOKEN_TTL", 15*time.Minute)
+
+const (
+	sessionIDKey contextKey = "sessionID"
+	roleKey      contextKey = "role"
+	apiKeyKey    contextKey = "apiKey"
+)
+
+// identity is who a request is authenticated as.
+type identity struct {
+	UserID string
+	// SessionID is "" for requests made with an API key.
+	SessionID string
+	Role      roles.Role
+	// Key is the API key the request was made with, if any.
+	Key *apikeys.Key
+}
+
+// getSessionID is the session of the access token, or "" for requests made
+// with an API key.
+func getSessionID(r *http.Request) string {
+	sid, _ := r.Context().Value(sessionIDKey).(string)
+	return sid
+}
+
+// roleFrom is the role of the authenticated user, or "" without one.
+func roleFrom(ctx context.Context) roles.Role {
+	role, _ := ctx.Value(roleKey).(roles.Role)
+	return role
+}
+
+// authError is a 401 whose text is shown to the client.
+type authError string
+
+func (e authError) Error() string { return string(e) }
+
+const (
+	errNoToken      authError = "Unauthorized - No token provided"
+	errBadToken     authError = "Invalid or expired token"
+	errBadClaims    authError = "Invalid token claims"
+	errSessionEnded authError = "Session has ended - sign in again"
+)
+
+// authenticate checks the X-API-Key or, without one, the Bearer token of r
+// and returns who it belongs to. Tokens without a session, issued before
+// sessions existed, are refused so that logging out reaches them too; tokens
+// without a role claim count as a plain user.
+func (s *Server) authenticate(r *http.Request) (identity, error) {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return s.authenticateKey(key)
+	}
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		return identity{}, errNoToken
+	}
+	claims, err := parseJWT(strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil {
+		return identity{}, errBadToken
+	}
+	var id identity
+	id.UserID, _ = claims["user_id"].(string)
+	if id.UserID == "" {
+		return identity{}, errBadClaims
+	}
+	id.Role = roles.User
+	if role, ok := claims["role"].(string); ok {
+		if id.Role, ok = roles.Parse(role); !ok {
+			return identity{}, errBadClaims
+		}
+	}
+	if id.SessionID, _ = claims["sid"].(string); id.SessionID == "" {
+		return identity{}, errSessionEnded
+	}
+	active, err := sessions.Active(s.db, id.SessionID)
+	if err != nil {
+		return identity{}, err
+	}
+	if !active {
+		return identity{}, errSessionEnded
+	}
+	return id, nil
+}
+
+// withIdentity stores who is calling in the request context.
+func withIdentity(r *http.Request, id identity) *http.Request {
+	ctx := context.WithValue(r.Context(), userIDKey, id.UserID)
+	ctx = context.WithValue(ctx, sessionIDKey, id.SessionID)
+	ctx = context.WithValue(ctx, roleKey, id.Role)
+	ctx = context.WithValue(ctx, apiKeyKey, id.Key)
+	return r.WithContext(ctx)
+}
+
+// issueTokens opens a session for user and returns the response for a
+// successful sign-in.
+func (s *Server) issueTokens(r *http.Request, user User) (*AuthResponse, error) {
+	sess, refresh, err := sessions.Create(s.db, user.ID, r.UserAgent(), clientIP(r))
+	if err != nil {
+		return nil, err
+	}
+	token, err := generateJWT(user, sess.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResponse{Token: token, RefreshToken: refresh, ExpiresIn: int(accessTokenTTL.Seconds()), User: user}, nil
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// POST /api/v1/auth/refresh
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		jsonError(w, "refresh_token is required", 400)
+		return
+	}
+
+	sess, refresh, err := sessions.Rotate(s.db, req.RefreshToken)
+	switch {
+	case errors.Is(err, sessions.ErrReused):
+		log.Printf("⚠️  Refresh token reused; revoked a session")
+		jsonErrorCode(w, CodeRefreshTokenReused, "This sign-in was revoked for your safety - sign in again", 401)
+		return
+	case errors.Is(err, sessions.ErrInvalid):
+		jsonError(w, err.Error(), 401)
+		return
+	case err != nil:
+		serverError(w, "Failed to refresh session", err)
+		return
+	}
+
+	user, err := s.getUser(sess.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		jsonError(w, "User not found", 401)
+		return
+	}
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	token, err := generateJWT(*user, sess.ID)
+	if err != nil {
+		serverError(w, "Failed to generate token", err)
+		return
+	}
+	jsonOK(w, AuthResponse{Token: token, RefreshToken: refresh, ExpiresIn: int(accessTokenTTL.Seconds()), User: *user})
+}
+
+// POST /api/v1/auth/logout
+//
+// Ends the session of the refresh token in the body or, without one, of
+// the access token. Logging out of an ended session succeeds.
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			jsonError(w, "Invalid request body", 400)
+			return
+		}
+	}
+
+	userID, sessionID := getUserID(r), getSessionID(r)
+	if req.RefreshToken != "" {
+		sess, err := sessions.SessionOf(s.db, req.RefreshToken)
+		if errors.Is(err, sessions.ErrNotFound) {
+			jsonOK(w, map[string]interface{}{"message": "Signed out"})
+			return
+		}
+		if err != nil {
+			serverError(w, "Failed to sign out", err)
+			return
+		}
+		userID, sessionID = sess.UserID, sess.ID
+	}
+	if sessionID == "" {
+		jsonError(w, "refresh_token is required", 400)
+		return
+	}
+
+	if err := sessions.Revoke(s.db, userID, sessionID, sessions.ReasonLogout); err != nil && !errors.Is(err, sessions.ErrNotFound) {
+		serverError(w, "Failed to sign out", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "Signed out"})
+}
+
+// POST /api/v1/auth/logout-all
+func (s *Server) handleLogoutAll(w http.ResponseWriter, r *http.Request) {
+	n, err := sessions.RevokeAll(s.db, getUserID(r), "", sessions.ReasonLogoutAll)
+	if err != nil {
+		serverError(w, "Failed to sign out", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "Signed out on all devices", "revoked": n})
+}
+
+// GET /api/v1/auth/sessions
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	list, err := sessions.List(s.db, getUserID(r))
+	if err != nil {
+		serverError(w, "Failed to list sessions", err)
+		return
+	}
+	current := getSessionID(r)
+	for i := range list {
+		list[i].Current = list[i].ID == current
+	}
+	jsonOK(w, map[string]interface{}{"sessions": list, "total": len(list)})
+}
+
+// getUser loads a user by ID.
+func (s *Server) getUser(id string) (*User, error) {
+	var user User
+	err := s.db.QueryRow(
+		`SELECT id::text, full_name, email, role, created_at, email_verified_at FROM users WHERE id=$1`, id,
+	).Scan(&user.ID, &user.FullName, &user.Email, &user.Role, &user.CreatedAt, &user.EmailVerifiedAt)
+	if err != nil {
+		return nil, err
+	}
+	user.EmailVerified = user.EmailVerifiedAt != nil
+	return &user, nil
+}
+
+// clientIP is the address of the client: the first X-Forwarded-For entry
+// when TRUST_PROXY=true, otherwise the peer address.
+func clientIP(r *http.Request) string {
+	if getEnv("TRUST_PROXY", "") == "true" {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			return strings.TrimSpace(strings.Split(fwd, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// envDuration reads a duration such as "15m" from the environment.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if v := getEnv(key, ""); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+		log.Printf("⚠️  Ignoring invalid %s=%q", key, v)
+	}
+	return fallback
+}
+
+// envInt reads a positive integer from the environment.
+func envInt(key string, fallback int) int {
+	if v := getEnv(key, ""); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+		log.Printf("⚠️  Ignoring invalid %s=%q", key, v)
+	}
+	return fallback
+}
@@ -0,0 +1,79 @@
+// backend/cmd/server/similar.go
+package main
+
+import (
+	"database/sql"
+
+	"event-scraper/internal/similar"
+	"event-scraper/pkg/utils"
+)
+
+// ─── Similar events ──────────────────────────────────────────────────────────
+//
+// GET /events/{id}/recommended and the GraphQL recommendations field both
+// serve the matches internal/similar computes and caches per event.
+
+// recommendedLimit is how many recommendations the REST endpoint returns.
+const recommendedLimit = 10
+
+type SimilarEvent struct {
+	Event
+	Score   int              `json:"score"`
+	Reasons []similar.Reason `json:"reasons"`
+}
+
+// similarEvents returns up to limit events like eventID, or sql.ErrNoRows
+// when it does not exist.
+func (s *Server) similarEvents(eventID int64, limit int) ([]SimilarEvent, error) {
+	all, err := s.similarEventsOf([]int64{eventID}, limit)
+	if err != nil {
+		return nil, err
+	}
+	events, ok := all[eventID]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return events, nil
+}
+
+// similarEventsOf returns up to limit events like each of ids, keyed by ID.
+// IDs that do not exist are missing from the result. Cached matches that
+// have since passed or been deleted are skipped.
+func (s *Server) similarEventsOf(ids []int64, limit int) (map[int64][]SimilarEvent, error) {
+	matches, err := similar.Get(s.db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var matchIDs []int64
+	for _, ms := range matches {
+		for _, m := range ms {
+			matchIDs = append(matchIDs, m.EventID)
+		}
+	}
+	events, err := s.loadEventsByID(matchIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]Event, len(events))
+	for _, e := range events {
+		byID[int64(e.ID)] = e
+	}
+
+	out := make(map[int64][]SimilarEvent, len(matches))
+	for id, ms := range matches {
+		list := []SimilarEvent{}
+		for _, m := range ms {
+			e, ok := byID[m.EventID]
+			if !ok || !utils.IsUpcoming(e.Date) {
+				continue
+			}
+			if len(list) == limit {
+				break
+			}
+			list = append(list, SimilarEvent{e, m.Score, m.Reasons})
+		}
+		out[id] = list
+	}
+	return out, nil
+}
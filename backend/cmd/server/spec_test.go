@@ -0,0 +1,176 @@
+// backend/cmd/server/spec_test.go
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"event-scraper/api"
+	"event-scraper/internal/database"
+	"event-scraper/internal/models"
+	"event-scraper/internal/notify"
+	"event-scraper/internal/sessions"
+	"event-scraper/internal/stream"
+)
+
+// ─── Spec drift ───────────────────────────────────────────────────────────────
+//
+// api/openapi.yaml must describe every route and every response. The route
+// check needs nothing; the response checks drive real handlers through
+// api.ValidateResponses, and those that read data need TEST_DATABASE_URL to
+// name a database the server has already migrated (they are skipped
+// otherwise). Rows they create are removed again.
+
+func TestRoutesMatchSpec(t *testing.T) {
+	s := &Server{}
+	rt := s.routes()
+	problems, err := api.CheckRoutes(rt.mux, rt.patterns)
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+// specHandler serves s the way main does, failing t on every response that
+// does not match the spec.
+func specHandler(t *testing.T, s *Server) http.Handler {
+	t.Helper()
+	rt := s.routes()
+	h, err := api.ValidateResponses(withRequestID(s.withCORS(s.withRateLimit(withFallback(rt.mux)))),
+		func(r *http.Request, err error) { t.Error(err) })
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	return h
+}
+
+// call sends one request to h, with token as bearer token unless it is
+// empty, and checks the status.
+func call(t *testing.T, h http.Handler, method, path, token, body string, want int) []byte {
+	t.Helper()
+	var rd io.Reader
+	if body != "" {
+		rd = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, rd)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != want {
+		t.Errorf("%s %s = %d, want %d: %s", method, path, rec.Code, want, rec.Body.String())
+	}
+	return rec.Body.Bytes()
+}
+
+func TestErrorEnvelopeMatchesSpec(t *testing.T) {
+	h := specHandler(t, &Server{})
+
+	tests := []struct {
+		name, method, path string
+		want               int
+		code               string
+	}{
+		{"bad id", "GET", "/api/v1/events/abc", 400, CodeBadRequest},
+		{"no token", "GET", "/api/v1/saved-events", 401, CodeUnauthorized},
+		{"unknown path", "GET", "/api/v1/no-such-thing", 404, CodeNotFound},
+		{"wrong method", "DELETE", "/api/v1/events", 405, CodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := call(t, h, tt.method, tt.path, "", "", tt.want)
+			var envelope errorEnvelope
+			if err := json.Unmarshal(body, &envelope); err != nil {
+				t.Fatalf("body is not an error envelope: %s", body)
+			}
+			if envelope.Error.Code != tt.code || envelope.Error.Message == "" || envelope.Error.RequestID == "" {
+				t.Errorf("envelope = %+v, want code %q with a message and request ID", envelope.Error, tt.code)
+			}
+		})
+	}
+}
+
+// testDB opens TEST_DATABASE_URL, skipping the test without it.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Fatalf("connect to TEST_DATABASE_URL: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestResponsesMatchSpec(t *testing.T) {
+	db := testDB(t)
+	broker := stream.NewBroker(16)
+	s := &Server{db: db, stream: broker, publisher: broker, mailer: notify.NewMailerFromEnv()}
+	h := specHandler(t, s)
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	event := &models.Event{
+		EventName: "Spec check meetup " + suffix,
+		Location:  "Bangalore",
+		Date:      time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		Time:      "6:00 PM",
+		Website:   "https://example.com/spec-check/" + suffix,
+		Platform:  "spec-test",
+	}
+	events, err := database.New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := events.InsertEvent(event); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM events WHERE id = $1`, event.ID) })
+
+	user := User{FullName: "Spec Check", Email: "spec-check-" + suffix + "@example.com"}
+	err = db.QueryRow(`
+		INSERT INTO users (full_name, email, password_hash) VALUES ($1, $2, '')
+		RETURNING id::text, role
+	`, user.FullName, user.Email).Scan(&user.ID, &user.Role)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id::text = $1`, user.ID) })
+	sess, _, err := sessions.Create(db, user.ID, "spec-test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	token, err := generateJWT(user, sess.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail := fmt.Sprintf("/api/v1/events/%d", event.ID)
+	call(t, h, "GET", "/api/v1/events", "", "", 200)
+	call(t, h, "GET", "/api/v1/events?q=Spec+check&sort=soonest&limit=5", token, "", 200)
+	call(t, h, "GET", detail, "", "", 200)
+	call(t, h, "GET", "/api/v1/events/0", "", "", 404)
+	call(t, h, "POST", detail+"/save", token, `{"notes":"from the spec test"}`, 200)
+	call(t, h, "GET", detail, token, "", 200)
+	call(t, h, "GET", "/api/v1/saved-events", token, "", 200)
+	call(t, h, "GET", "/api/v1/saved-events?status=bogus", token, "", 400)
+}
@@ -0,0 +1,178 @@
+// backend/cmd/server/sso.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"event-scraper/internal/accounts"
+	"event-scraper/internal/sso"
+)
+
+// ─── Single sign-on (OIDC) ────────────────────────────────────────────────────
+//
+// GET /auth/oidc/login sends the browser to the identity provider, which
+// sends it back to /auth/oidc/callback. The callback signs the user in
+// and redirects to the frontend's /auth/callback with a short-lived,
+// single-use code; the frontend trades that for the usual token pair at
+// POST /auth/oidc/token, so no token ever appears in a URL.
+//
+// Configured with OIDC_* (see sso.ConfigFromEnv); cmd/mock-oidc is a
+// provider for local development.
+
+const ssoStateCookie = "sso_state"
+
+type SSOTokenRequest struct {
+	Code string `json:"code"`
+}
+
+// GET /api/v1/auth/oidc/config
+func (s *Server) handleSSOConfig(w http.ResponseWriter, r *http.Request) {
+	if s.sso == nil {
+		jsonOK(w, map[string]interface{}{"enabled": false})
+		return
+	}
+	jsonOK(w, map[string]interface{}{"enabled": true, "name": s.sso.Name()})
+}
+
+// GET /api/v1/auth/oidc/login?redirect=/events
+func (s *Server) handleSSOLogin(w http.ResponseWriter, r *http.Request) {
+	if s.sso == nil {
+		jsonError(w, "Single sign-on is not configured", 404)
+		return
+	}
+	login, err := sso.StartLogin(s.db, safeRedirect(r.URL.Query().Get("redirect")))
+	if err != nil {
+		serverError(w, "Failed to start sign-in", err)
+		return
+	}
+	target, err := s.sso.AuthCodeURL(login.State, login.Nonce, login.Verifier)
+	if err != nil {
+		log.Printf("⚠️  SSO: %v", err)
+		jsonError(w, "The identity provider is unreachable", http.StatusServiceUnavailable)
+		return
+	}
+	// The cookie ties the callback to this browser, so nobody can sign a
+	// victim into the attacker's account with a callback link.
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookie,
+		Value:    login.State,
+		Path:     "/",
+		MaxAge:   int(sso.LoginTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// GET /api/v1/auth/oidc/callback?code=…&state=…
+//
+// Always answers with a redirect to the frontend, carrying either a code or
+// an error message.
+func (s *Server) handleSSOCallback(w http.ResponseWriter, r *http.Request) {
+	fail := func(msg string) {
+		http.Redirect(w, r, appURL+"/auth/callback?error="+url.QueryEscape(msg), http.StatusFound)
+	}
+	if s.sso == nil {
+		fail("Single sign-on is not configured")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: ssoStateCookie, Path: "/", MaxAge: -1})
+
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		msg := q.Get("error_description")
+		if msg == "" {
+			msg = e
+		}
+		fail("The identity provider refused the sign-in: " + msg)
+		return
+	}
+	state := q.Get("state")
+	if c, err := r.Cookie(ssoStateCookie); err != nil || state == "" || c.Value != state {
+		fail("This sign-in was started in another browser or has expired - try again")
+		return
+	}
+	login, err := sso.TakeLogin(s.db, state)
+	if errors.Is(err, sso.ErrUnknownLogin) {
+		fail("This sign-in has expired - try again")
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️  SSO: %v", err)
+		fail("Sign-in failed - try again")
+		return
+	}
+
+	identity, err := s.sso.Exchange(r.Context(), q.Get("code"), login.Verifier, login.Nonce)
+	if err != nil {
+		log.Printf("⚠️  SSO: %v", err)
+		fail("The identity provider's answer could not be verified")
+		return
+	}
+	userID, created, err := sso.LinkUser(s.db, identity)
+	if errors.Is(err, sso.ErrEmailUnverified) {
+		fail("Your identity provider has not verified your email address")
+		return
+	}
+	if err != nil {
+		log.Printf("⚠️  SSO: %v", err)
+		fail("Sign-in failed - try again")
+		return
+	}
+	if created {
+		log.Printf("👤 SSO created user %s for %s", userID, identity.Email)
+	}
+
+	code, err := accounts.Issue(s.db, userID, identity.Email, accounts.PurposeSignIn)
+	if err != nil {
+		log.Printf("⚠️  SSO: %v", err)
+		fail("Sign-in failed - try again")
+		return
+	}
+	http.Redirect(w, r, appURL+"/auth/callback?code="+url.QueryEscape(code)+
+		"&redirect="+url.QueryEscape(login.RedirectTo), http.StatusFound)
+}
+
+// POST /api/v1/auth/oidc/token
+func (s *Server) handleSSOToken(w http.ResponseWriter, r *http.Request) {
+	var req SSOTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		jsonError(w, "code is required", 400)
+		return
+	}
+	userID, err := accounts.Redeem(s.db, req.Code, accounts.PurposeSignIn)
+	if errors.Is(err, accounts.ErrInvalid) {
+		jsonError(w, "Invalid or expired sign-in code", 401)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to sign in", err)
+		return
+	}
+	user, err := s.getUser(userID)
+	if err != nil {
+		serverError(w, "Server error", err)
+		return
+	}
+	resp, err := s.issueTokens(r, *user)
+	if err != nil {
+		serverError(w, "Failed to generate token", err)
+		return
+	}
+	jsonOK(w, resp)
+}
+
+// safeRedirect keeps a post-sign-in redirect on the frontend: only a path
+// is allowed, not another origin.
+func safeRedirect(p string) string {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") || strings.ContainsAny(p, "\\\r\n") {
+		return "/"
+	}
+	return p
+}
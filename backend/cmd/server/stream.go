@@ -0,0 +1,115 @@
+// backend/cmd/server/stream.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-scraper/internal/models"
+	"event-scraper/internal/stream"
+)
+
+const streamHeartbeat = 25 * time.Second
+
+// GET /api/v1/stream?types=event-inserted,cycle-finished&city=Bengaluru&platform=meetup
+//
+// Server-Sent Events. Each message carries its broker ID, so browsers resume
+// with Last-Event-ID after a reconnect.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		jsonError(w, "Streaming unsupported", 500)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := stream.Filter{
+		City:     strings.TrimSpace(q.Get("city")),
+		Platform: strings.TrimSpace(q.Get("platform")),
+	}
+	if raw := strings.TrimSpace(q.Get("types")); raw != "" {
+		filter.Types = make(map[string]bool)
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimSpace(t)
+			if !validStreamType(t) {
+				jsonError(w, fmt.Sprintf("Unknown stream type %q", t), 400)
+				return
+			}
+			filter.Types[t] = true
+		}
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = q.Get("last_event_id")
+	}
+	since, _ := strconv.ParseInt(lastID, 10, 64)
+
+	sub := s.stream.Subscribe(filter, since)
+	defer s.stream.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case msg, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and replays.
+				return
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+func validStreamType(t string) bool {
+	for _, known := range stream.Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// publishEventUpdated announces that an event changed outside a scheduler
+// cycle, e.g. after the manual detail scrape saved new details for it.
+func (s *Server) publishEventUpdated(eventID int64) {
+	var e models.Event
+	err := s.db.QueryRow(`
+		SELECT id, COALESCE(event_name, ''), COALESCE(location, ''), COALESCE(city_normalized, ''),
+		       COALESCE(date, ''), COALESCE(time, ''), COALESCE(website, ''),
+		       COALESCE(event_type, ''), COALESCE(platform, '')
+		FROM events WHERE id = $1
+	`, eventID).Scan(&e.ID, &e.EventName, &e.Location, &e.CityNormalized,
+		&e.Date, &e.Time, &e.Website, &e.EventType, &e.Platform)
+	if err != nil {
+		return
+	}
+	if err := s.publisher.Publish(stream.EventMessage(stream.TypeEventUpdated, e)); err != nil {
+		log.Printf("⚠️  Failed to publish stream message: %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+// backend/cmd/server/webhooks.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"event-scraper/internal/apikeys"
+	"event-scraper/internal/roles"
+	"event-scraper/internal/webhooks"
+)
+
+type WebhookRequest struct {
+	URL     string            `json:"url"`
+	Secret  *string           `json:"secret"`
+	Events  []string          `json:"events"`
+	Filters *webhooks.Filters `json:"filters"`
+	Active  *bool             `json:"active"`
+}
+
+// requireWebhookAccess admits admins, and API keys with the webhooks:manage
+// scope. Webhooks make the server call out to other systems, so other users
+// cannot manage them with a bearer token.
+func (s *Server) requireWebhookAccess(next http.HandlerFunc) http.HandlerFunc {
+	return s.requireScope(apikeys.ScopeWebhooks, func(w http.ResponseWriter, r *http.Request) {
+		key, _ := r.Context().Value(apiKeyKey).(*apikeys.Key)
+		if key == nil && !roleFrom(r.Context()).Includes(roles.Admin) {
+			jsonError(w, "Forbidden - webhooks need the admin role or an API key with the "+apikeys.ScopeWebhooks+" scope", 403)
+			return
+		}
+		next(w, r)
+	})
+}
+
+// GET /api/v1/webhooks
+func (s *Server) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := webhooks.List(s.db, getUserID(r))
+	if err != nil {
+		serverError(w, "Failed to list webhooks", err)
+		return
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	jsonOK(w, map[string]interface{}{"webhooks": hooks, "total": len(hooks)})
+}
+
+// POST /api/v1/webhooks
+func (s *Server) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	hook := webhooks.Webhook{UserID: getUserID(r), URL: req.URL, Events: req.Events, Active: true}
+	if req.Secret != nil {
+		hook.Secret = strings.TrimSpace(*req.Secret)
+	}
+	if req.Filters != nil {
+		hook.Filters = *req.Filters
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+	if err := hook.Validate(); err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	if err := webhooks.Create(s.db, &hook); err != nil {
+		serverError(w, "Failed to create webhook", err)
+		return
+	}
+	// The secret is only ever returned here, right after creation.
+	jsonStatus(w, map[string]interface{}{"webhook": hook}, http.StatusCreated)
+}
+
+type webhookHandler func(w http.ResponseWriter, r *http.Request, hook *webhooks.Webhook)
+
+// withWebhook loads the caller's webhook named by {id} before calling next.
+func (s *Server) withWebhook(next webhookHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		webhookID, err := pathID(r, "id")
+		if err != nil {
+			jsonError(w, "Invalid webhook ID", 400)
+			return
+		}
+
+		hook, err := webhooks.Get(s.db, getUserID(r), webhookID)
+		if errors.Is(err, webhooks.ErrNotFound) {
+			jsonError(w, "Webhook not found", 404)
+			return
+		}
+		if err != nil {
+			serverError(w, "Server error", err)
+			return
+		}
+		next(w, r, hook)
+	}
+}
+
+// GET /api/v1/webhooks/{id}
+func (s *Server) handleGetWebhook(w http.ResponseWriter, r *http.Request, hook *webhooks.Webhook) {
+	hook.Secret = ""
+	jsonOK(w, map[string]interface{}{"webhook": hook})
+}
+
+// PUT /api/v1/webhooks/{id}
+func (s *Server) handleUpdateWebhook(w http.ResponseWriter, r *http.Request, hook *webhooks.Webhook) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "Invalid request body", 400)
+		return
+	}
+	if req.URL != "" {
+		hook.URL = req.URL
+	}
+	if req.Secret != nil && strings.TrimSpace(*req.Secret) != "" {
+		hook.Secret = strings.TrimSpace(*req.Secret)
+	}
+	if req.Events != nil {
+		hook.Events = req.Events
+	}
+	if req.Filters != nil {
+		hook.Filters = *req.Filters
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+	if err := hook.Validate(); err != nil {
+		jsonError(w, err.Error(), 400)
+		return
+	}
+	if err := webhooks.Update(s.db, hook); err != nil {
+		serverError(w, "Failed to update webhook", err)
+		return
+	}
+	hook.Secret = ""
+	jsonOK(w, map[string]interface{}{"webhook": hook})
+}
+
+// DELETE /api/v1/webhooks/{id}
+func (s *Server) handleDeleteWebhook(w http.ResponseWriter, r *http.Request, hook *webhooks.Webhook) {
+	if err := webhooks.Delete(s.db, hook.UserID, hook.ID); err != nil {
+		serverError(w, "Failed to delete webhook", err)
+		return
+	}
+	jsonOK(w, map[string]interface{}{"message": "Webhook deleted", "deleted": true})
+}
+
+// GET /api/v1/webhooks/{id}/deliveries?status=failed&limit=50
+func (s *Server) handleWebhookDeliveries(w http.ResponseWriter, r *http.Request, hook *webhooks.Webhook) {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	deliveries, err := webhooks.ListDeliveries(s.db, hook.ID, strings.TrimSpace(q.Get("status")), limit)
+	if err != nil {
+		serverError(w, "Failed to list deliveries", err)
+		return
+	}
+
+	jsonOK(w, map[string]interface{}{"deliveries": deliveries, "total": len(deliveries)})
+}
+
+// POST /api/v1/webhooks/{id}/deliveries/{deliveryId}/replay
+func (s *Server) handleReplayDelivery(w http.ResponseWriter, r *http.Request, hook *webhooks.Webhook) {
+	deliveryID, err := pathID(r, "deliveryId")
+	if err != nil {
+		jsonError(w, "Invalid delivery ID", 400)
+		return
+	}
+
+	if !hook.Active {
+		jsonError(w, "Webhook is inactive; activate it before replaying deliveries", 409)
+		return
+	}
+
+	delivery, err := webhooks.Replay(s.db, hook.ID, deliveryID)
+	if errors.Is(err, webhooks.ErrNotFound) {
+		jsonError(w, "Delivery not found", 404)
+		return
+	}
+	if err != nil {
+		serverError(w, "Failed to replay delivery", err)
+		return
+	}
+
+	jsonStatus(w, map[string]interface{}{"delivery": delivery}, http.StatusAccepted)
+}
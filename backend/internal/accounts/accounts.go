@@ -0,0 +1,195 @@
+// Package accounts issues the single-use tokens mailed to users to verify
+// their email address or reset a forgotten password, and the one that hands
+// a single sign-on over to the frontend.
+//
+// A token is random, expires, and is stored only as a hash. Issuing a new
+// token of a purpose invalidates the user's earlier unused ones, so only
+// the latest link in their inbox works.
+package accounts
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Purpose says what a token may be used for.
+type Purpose string
+
+const (
+	PurposeVerifyEmail   Purpose = "verify_email"
+	PurposeResetPassword Purpose = "reset_password"
+	// PurposeSignIn is redeemed by the frontend for a session after the
+	// identity provider sends the browser back.
+	PurposeSignIn Purpose = "sign_in"
+)
+
+// TTL is how long a token of each purpose stays valid.
+var TTL = map[Purpose]time.Duration{
+	PurposeVerifyEmail:   48 * time.Hour,
+	PurposeResetPassword: time.Hour,
+	PurposeSignIn:        2 * time.Minute,
+}
+
+// ErrInvalid is returned for unknown, expired and used tokens.
+var ErrInvalid = errors.New("invalid or expired link")
+
+// Schema holds the DDL for account tokens and the verified flag on users.
+// email is the address a token was sent to; a verification token stops
+// working if the user's email changes.
+var Schema = []string{
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ`,
+	`CREATE TABLE IF NOT EXISTS account_tokens (
+		token_hash TEXT PRIMARY KEY,
+		user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		purpose    VARCHAR(20) NOT NULL,
+		email      VARCHAR(180) NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		expires_at TIMESTAMPTZ NOT NULL,
+		used_at    TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_account_tokens_user_id ON account_tokens(user_id, purpose)`,
+}
+
+// EnsureSchema creates the account_tokens table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("accounts migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// Issue creates a token for the user, sent to email, and returns it. The
+// user's earlier unused tokens of the same purpose stop working.
+func Issue(db *sql.DB, userID, email string, purpose Purpose) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE account_tokens SET used_at = now()
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, purpose); err != nil {
+		return "", err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO account_tokens (token_hash, user_id, purpose, email, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, hashToken(token), userID, purpose, email, time.Now().Add(TTL[purpose])); err != nil {
+		return "", err
+	}
+	return token, tx.Commit()
+}
+
+// consume spends a token inside tx and returns its user and email.
+func consume(tx *sql.Tx, token string, purpose Purpose) (userID, email string, err error) {
+	err = tx.QueryRow(`
+		UPDATE account_tokens SET used_at = now()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id::text, email
+	`, hashToken(token), purpose).Scan(&userID, &email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", ErrInvalid
+	}
+	return userID, email, err
+}
+
+// Redeem spends a token of a purpose that needs nothing else done, and
+// returns its user.
+func Redeem(db *sql.DB, token string, purpose Purpose) (string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	userID, _, err := consume(tx, token, purpose)
+	if err != nil {
+		return "", err
+	}
+	return userID, tx.Commit()
+}
+
+// VerifyEmail spends a verification token and marks the address it was
+// sent to as verified. It returns the user.
+func VerifyEmail(db *sql.DB, token string) (string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	userID, email, err := consume(tx, token, PurposeVerifyEmail)
+	if err != nil {
+		return "", err
+	}
+	res, err := tx.Exec(`
+		UPDATE users SET email_verified_at = COALESCE(email_verified_at, now()), updated_at = now()
+		WHERE id = $1 AND email = $2
+	`, userID, email)
+	if err != nil {
+		return "", err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return "", ErrInvalid
+	}
+	return userID, tx.Commit()
+}
+
+// ResetPassword spends a reset token and stores passwordHash as the user's
+// password. It returns the user. The link was mailed to the account's
+// address, so following it also verifies that address.
+func ResetPassword(db *sql.DB, token, passwordHash string) (string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	userID, email, err := consume(tx, token, PurposeResetPassword)
+	if err != nil {
+		return "", err
+	}
+	res, err := tx.Exec(`
+		UPDATE users SET password_hash = $3, updated_at = now(),
+		       email_verified_at = COALESCE(email_verified_at, now())
+		WHERE id = $1 AND email = $2
+	`, userID, email, passwordHash)
+	if err != nil {
+		return "", err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return "", ErrInvalid
+	}
+	return userID, tx.Commit()
+}
+
+// Purge deletes tokens that expired more than a week ago.
+func Purge(db *sql.DB) (int, error) {
+	res, err := db.Exec(`DELETE FROM account_tokens WHERE expires_at < now() - INTERVAL '7 days'`)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
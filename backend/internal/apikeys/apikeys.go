@@ -0,0 +1,262 @@
+// Package apikeys issues API keys for programmatic access.
+//
+// A key acts for the user who created it, but only within its scopes, and
+// only for its daily quota of requests. Only a hash of the key is stored;
+// the key itself is shown once, when it is created.
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Scopes a key can hold.
+const (
+	// ScopeEventsRead allows reading events, as anyone can without a key.
+	ScopeEventsRead = "events:read"
+	// ScopeSaved allows reading and changing the user's saved events,
+	// collections and reminders.
+	ScopeSaved = "saved:manage"
+	// ScopeWebhooks allows registering and managing the user's webhooks.
+	ScopeWebhooks = "webhooks:manage"
+	// ScopeAdmin allows the admin routes the user's role permits.
+	ScopeAdmin = "admin"
+)
+
+// Scopes lists every scope.
+var Scopes = []string{ScopeEventsRead, ScopeSaved, ScopeWebhooks, ScopeAdmin}
+
+// Prefix starts every key, so leaked keys are easy to recognise.
+const Prefix = "esk_"
+
+// MaxPerUser is how many keys one user may hold.
+const MaxPerUser = 20
+
+var (
+	// ErrInvalid is returned for unknown and deleted keys.
+	ErrInvalid = errors.New("invalid API key")
+	// ErrQuotaExceeded is returned once a key has used up today's quota.
+	ErrQuotaExceeded = errors.New("API key quota exceeded")
+	ErrNotFound      = errors.New("API key not found")
+	ErrTooMany       = fmt.Errorf("at most %d API keys per user", MaxPerUser)
+)
+
+// Key is an API key without its secret.
+type Key struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	// Hint is the start of the key, to tell keys apart.
+	Hint   string   `json:"hint"`
+	Scopes []string `json:"scopes"`
+	// DailyQuota is how many requests the key may make per UTC day.
+	DailyQuota int        `json:"daily_quota"`
+	UsedToday  int        `json:"used_today"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// Allows reports whether the key holds scope.
+func (k *Key) Allows(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Remaining is how many requests the key has left today.
+func (k *Key) Remaining() int {
+	if k.UsedToday >= k.DailyQuota {
+		return 0
+	}
+	return k.DailyQuota - k.UsedToday
+}
+
+// Validate normalizes the name and scopes of k.
+func (k *Key) Validate() error {
+	k.Name = strings.TrimSpace(k.Name)
+	if k.Name == "" || len(k.Name) > 100 {
+		return errors.New("name is required and at most 100 characters")
+	}
+	if len(k.Scopes) == 0 {
+		return errors.New("scopes must name at least one of " + strings.Join(Scopes, ", "))
+	}
+	seen := map[string]bool{}
+	scopes := k.Scopes[:0]
+	for _, s := range k.Scopes {
+		if !valid(s) {
+			return fmt.Errorf("unknown scope %q: use %s", s, strings.Join(Scopes, ", "))
+		}
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	k.Scopes = scopes
+	if k.DailyQuota < 1 {
+		return errors.New("daily_quota must be positive")
+	}
+	return nil
+}
+
+func valid(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema holds the DDL for API keys. Usage is counted per UTC day in
+// quota_day and used_today.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS api_keys (
+		id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		name         VARCHAR(100) NOT NULL,
+		key_hash     TEXT NOT NULL UNIQUE,
+		hint         VARCHAR(20) NOT NULL,
+		scopes       TEXT[] NOT NULL,
+		daily_quota  INT NOT NULL,
+		quota_day    DATE,
+		used_today   INT NOT NULL DEFAULT 0,
+		created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_used_at TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id)`,
+}
+
+// EnsureSchema creates the api_keys table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("apikeys migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func hashKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// today is the current UTC day, the unit of quotas.
+const today = `(now() AT TIME ZONE 'UTC')::date`
+
+const keyCols = `id::text, user_id::text, name, hint, scopes, daily_quota,
+	CASE WHEN quota_day = ` + today + ` THEN used_today ELSE 0 END,
+	created_at, last_used_at`
+
+func scanKey(row interface{ Scan(...interface{}) error }, k *Key) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Name, &k.Hint, pq.Array(&k.Scopes),
+		&k.DailyQuota, &k.UsedToday, &k.CreatedAt, &k.LastUsedAt)
+}
+
+// Create stores k, which must be valid, for k.UserID and returns the key.
+func Create(db *sql.DB, k *Key) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	key := Prefix + base64.RawURLEncoding.EncodeToString(b)
+	k.Hint = key[:len(Prefix)+6]
+
+	err := scanKey(db.QueryRow(`
+		INSERT INTO api_keys (user_id, name, key_hash, hint, scopes, daily_quota)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE (SELECT COUNT(*) FROM api_keys WHERE user_id = $1) < $7
+		RETURNING `+keyCols,
+		k.UserID, k.Name, hashKey(key), k.Hint, pq.Array(k.Scopes), k.DailyQuota, MaxPerUser,
+	), k)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrTooMany
+	}
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Use looks up key and counts one request against its quota. Once the
+// quota is used up it returns the key with ErrQuotaExceeded.
+func Use(db *sql.DB, key string) (*Key, error) {
+	if !strings.HasPrefix(key, Prefix) {
+		return nil, ErrInvalid
+	}
+	var k Key
+	err := scanKey(db.QueryRow(`
+		UPDATE api_keys SET
+			used_today   = CASE WHEN quota_day = `+today+` THEN used_today + 1 ELSE 1 END,
+			quota_day    = `+today+`,
+			last_used_at = now()
+		WHERE key_hash = $1
+		  AND (quota_day IS DISTINCT FROM `+today+` OR used_today < daily_quota)
+		RETURNING `+keyCols,
+		hashKey(key)), &k)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Either there is no such key or its quota is used up.
+		err = scanKey(db.QueryRow(`SELECT `+keyCols+` FROM api_keys WHERE key_hash = $1`, hashKey(key)), &k)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalid
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &k, ErrQuotaExceeded
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// List returns the keys of a user, or of everyone when userID is "".
+func List(db *sql.DB, userID string) ([]Key, error) {
+	rows, err := db.Query(`
+		SELECT `+keyCols+` FROM api_keys
+		WHERE $1 = '' OR user_id::text = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []Key{}
+	for rows.Next() {
+		var k Key
+		if err := scanKey(rows, &k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+// Delete deletes a key of a user, or of anyone when userID is "".
+func Delete(db *sql.DB, userID, keyID string) error {
+	res, err := db.Exec(`
+		DELETE FROM api_keys
+		WHERE id::text = $2 AND ($1 = '' OR user_id::text = $1)
+	`, userID, keyID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Package changes announces new and changed events: it queues a webhook
+// delivery for every matching subscription, publishes them to the live
+// stream, drops the cached recommendations and reschedules the reminders of
+// changed events. Scrape cycles and edits made through the API, such as
+// re-normalizing cities after a gazetteer alias is saved, both go through
+// Announce so subscribers see every change the same way.
+package changes
+
+import (
+	"database/sql"
+	"log"
+
+	"event-scraper/internal/models"
+	"event-scraper/internal/reminders"
+	"event-scraper/internal/similar"
+	"event-scraper/internal/stream"
+	"event-scraper/internal/webhooks"
+)
+
+// Announce fans out changes and returns how many webhook deliveries it
+// queued. Delivery itself happens in the dispatchers, so a slow endpoint
+// never holds up the caller. pub may be nil. Failures are only logged: the
+// events themselves are already saved.
+func Announce(db *sql.DB, pub stream.Publisher, changes []models.EventChange) int {
+	if len(changes) == 0 {
+		return 0
+	}
+
+	queued, err := webhooks.Enqueue(db, changes)
+	if err != nil {
+		log.Printf("⚠️  Failed to queue webhook deliveries: %v", err)
+	}
+
+	if pub != nil {
+		for _, change := range changes {
+			typ := stream.TypeEventInserted
+			if change.Kind == models.EventUpdated {
+				typ = stream.TypeEventUpdated
+			}
+			if err := pub.Publish(stream.EventMessage(typ, change.Event)); err != nil {
+				log.Printf("⚠️  Failed to publish stream message: %v", err)
+			}
+		}
+	}
+
+	// Any change can reorder recommendations, so drop the cached ones.
+	if err := similar.Clear(db); err != nil {
+		log.Printf("⚠️  Failed to clear similar events: %v", err)
+	}
+
+	// A changed date/time moves every reminder users set for the event.
+	for _, change := range changes {
+		if change.Kind != models.EventUpdated {
+			continue
+		}
+		if err := reminders.RescheduleEvent(db, change.Event.ID); err != nil {
+			log.Printf("⚠️  Failed to reschedule reminders of event %d: %v", change.Event.ID, err)
+		}
+	}
+	return queued
+}
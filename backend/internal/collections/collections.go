@@ -0,0 +1,389 @@
+// Package collections groups a user's saved events into named, ordered
+// collections such as "Go meetups", and tags saved events. A collection can
+// be shared read-only through a link that may expire.
+//
+// Items point at saved_events rows, so unsaving an event also takes it out
+// of every collection.
+package collections
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Limits on names, descriptions, tags and collections per user.
+const (
+	MaxNameLen        = 100
+	MaxDescriptionLen = 2000
+	MaxTags           = 20
+	MaxTagLen         = 40
+	MaxPerUser        = 100
+)
+
+var (
+	ErrNotFound  = errors.New("collection not found")
+	ErrNameTaken = errors.New("you already have a collection with this name")
+	ErrTooMany   = fmt.Errorf("at most %d collections per user", MaxPerUser)
+	ErrExpired   = errors.New("share link expired")
+	ErrBadOrder  = errors.New("event_ids must list every event of the collection exactly once")
+)
+
+type Collection struct {
+	ID          int64     `json:"id"`
+	UserID      string    `json:"-"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	EventCount  int       `json:"event_count"`
+	Share       *Share    `json:"share"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Share is the public link of a collection. The token is kept in the clear
+// so the owner can copy the link again; it only grants read access to one
+// collection.
+type Share struct {
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Schema holds the DDL for collections and saved event tags.
+var Schema = []string{
+	`ALTER TABLE saved_events ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}'`,
+	`CREATE INDEX IF NOT EXISTS idx_saved_events_tags ON saved_events USING GIN(tags)`,
+	`CREATE TABLE IF NOT EXISTS collections (
+		id               SERIAL PRIMARY KEY,
+		user_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		name             VARCHAR(100) NOT NULL,
+		description      TEXT NOT NULL DEFAULT '',
+		share_token      TEXT UNIQUE,
+		share_expires_at TIMESTAMPTZ,
+		shared_at        TIMESTAMPTZ,
+		created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_collections_user_name ON collections(user_id, lower(name))`,
+	`CREATE TABLE IF NOT EXISTS collection_items (
+		collection_id  INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+		saved_event_id INTEGER NOT NULL REFERENCES saved_events(id) ON DELETE CASCADE,
+		position       INTEGER NOT NULL,
+		added_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (collection_id, saved_event_id)
+	)`,
+}
+
+// EnsureSchema creates the collection tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("collections migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// ─── Validation ──────────────────────────────────────────────────────────────
+
+// Validate trims the name and description and checks their length.
+func (c *Collection) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Description = strings.TrimSpace(c.Description)
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(c.Name) > MaxNameLen {
+		return fmt.Errorf("name is at most %d characters", MaxNameLen)
+	}
+	if len(c.Description) > MaxDescriptionLen {
+		return fmt.Errorf("description is at most %d characters", MaxDescriptionLen)
+	}
+	return nil
+}
+
+// CleanTags trims tags, drops empty ones and duplicates (ignoring case) and
+// checks the limits.
+func CleanTags(tags []string) ([]string, error) {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[strings.ToLower(t)] {
+			continue
+		}
+		if len(t) > MaxTagLen {
+			return nil, fmt.Errorf("tags are at most %d characters", MaxTagLen)
+		}
+		seen[strings.ToLower(t)] = true
+		out = append(out, t)
+	}
+	if len(out) > MaxTags {
+		return nil, fmt.Errorf("at most %d tags per saved event", MaxTags)
+	}
+	return out, nil
+}
+
+// ─── Collections ─────────────────────────────────────────────────────────────
+
+const collectionCols = `c.id, c.user_id::text, c.name, c.description,
+	(SELECT COUNT(*) FROM collection_items i WHERE i.collection_id = c.id),
+	c.share_token, c.share_expires_at, c.shared_at, c.created_at, c.updated_at`
+
+type scanner interface {
+	Scan(...interface{}) error
+}
+
+func scanCollection(row scanner, c *Collection) error {
+	var token sql.NullString
+	var expiresAt, sharedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Description, &c.EventCount,
+		&token, &expiresAt, &sharedAt, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return err
+	}
+	c.Share = nil
+	if token.Valid {
+		c.Share = &Share{Token: token.String, CreatedAt: sharedAt.Time}
+		if expiresAt.Valid {
+			c.Share.ExpiresAt = &expiresAt.Time
+		}
+	}
+	return nil
+}
+
+// List returns a user's collections by name.
+func List(db *sql.DB, userID string) ([]Collection, error) {
+	rows, err := db.Query(`
+		SELECT `+collectionCols+` FROM collections c
+		WHERE c.user_id = $1
+		ORDER BY lower(c.name), c.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Collection{}
+	for rows.Next() {
+		var c Collection
+		if err := scanCollection(rows, &c); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Get returns one of a user's collections.
+func Get(db *sql.DB, userID string, id int64) (*Collection, error) {
+	var c Collection
+	err := scanCollection(db.QueryRow(`
+		SELECT `+collectionCols+` FROM collections c WHERE c.id = $1 AND c.user_id = $2
+	`, id, userID), &c)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create stores a new, validated collection for c.UserID.
+func Create(db *sql.DB, c *Collection) error {
+	var id int64
+	err := db.QueryRow(`
+		INSERT INTO collections (user_id, name, description)
+		SELECT $1, $2, $3
+		WHERE (SELECT COUNT(*) FROM collections WHERE user_id = $1) < $4
+		RETURNING id
+	`, c.UserID, c.Name, c.Description, MaxPerUser).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTooMany
+	}
+	if err != nil {
+		return nameTaken(err)
+	}
+	created, err := Get(db, c.UserID, id)
+	if err != nil {
+		return err
+	}
+	*c = *created
+	return nil
+}
+
+// Update saves a validated collection's name and description.
+func Update(db *sql.DB, c *Collection) error {
+	_, err := db.Exec(`
+		UPDATE collections SET name = $3, description = $4, updated_at = now()
+		WHERE id = $1 AND user_id = $2
+	`, c.ID, c.UserID, c.Name, c.Description)
+	return nameTaken(err)
+}
+
+// Delete removes a collection. Its events stay saved.
+func Delete(db *sql.DB, userID string, id int64) error {
+	_, err := db.Exec(`DELETE FROM collections WHERE id = $1 AND user_id = $2`, id, userID)
+	return err
+}
+
+func nameTaken(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrNameTaken
+	}
+	return err
+}
+
+// ─── Items ───────────────────────────────────────────────────────────────────
+
+// AddItem appends a saved event to a collection; adding it again keeps its
+// place.
+func AddItem(db *sql.DB, collectionID, savedEventID int64) error {
+	_, err := db.Exec(`
+		INSERT INTO collection_items (collection_id, saved_event_id, position)
+		SELECT $1, $2, COALESCE(MAX(position), 0) + 1 FROM collection_items WHERE collection_id = $1
+		ON CONFLICT (collection_id, saved_event_id) DO NOTHING
+	`, collectionID, savedEventID)
+	if err == nil {
+		err = touch(db, collectionID)
+	}
+	return err
+}
+
+// RemoveItem takes an event out of a collection and reports whether it was
+// in it.
+func RemoveItem(db *sql.DB, collectionID, eventID int64) (bool, error) {
+	res, err := db.Exec(`
+		DELETE FROM collection_items i USING saved_events se
+		WHERE i.collection_id = $1 AND i.saved_event_id = se.id AND se.event_id = $2
+	`, collectionID, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	if n > 0 {
+		err = touch(db, collectionID)
+	}
+	return n > 0, err
+}
+
+// Reorder puts a collection's events in the order of eventIDs, which must
+// list each of them exactly once.
+func Reorder(db *sql.DB, collectionID int64, eventIDs []int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT se.event_id, se.id FROM collection_items i
+		JOIN saved_events se ON se.id = i.saved_event_id
+		WHERE i.collection_id = $1
+		FOR UPDATE OF i
+	`, collectionID)
+	if err != nil {
+		return err
+	}
+	items := map[int64]int64{}
+	for rows.Next() {
+		var eventID, savedID int64
+		if err := rows.Scan(&eventID, &savedID); err != nil {
+			rows.Close()
+			return err
+		}
+		items[eventID] = savedID
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(eventIDs) != len(items) {
+		return ErrBadOrder
+	}
+	seen := map[int64]bool{}
+	for _, id := range eventIDs {
+		if _, ok := items[id]; !ok || seen[id] {
+			return ErrBadOrder
+		}
+		seen[id] = true
+	}
+
+	for i, id := range eventIDs {
+		if _, err := tx.Exec(`
+			UPDATE collection_items SET position = $3 WHERE collection_id = $1 AND saved_event_id = $2
+		`, collectionID, items[id], i+1); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE collections SET updated_at = now() WHERE id = $1`, collectionID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func touch(db *sql.DB, collectionID int64) error {
+	_, err := db.Exec(`UPDATE collections SET updated_at = now() WHERE id = $1`, collectionID)
+	return err
+}
+
+// ─── Sharing ─────────────────────────────────────────────────────────────────
+
+// EnableShare gives a collection a new share link, replacing any earlier
+// one, that expires at expiresAt (nil for never).
+func EnableShare(db *sql.DB, c *Collection, expiresAt *time.Time) error {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	var at time.Time
+	if err := db.QueryRow(`
+		UPDATE collections SET share_token = $3, share_expires_at = $4, shared_at = now()
+		WHERE id = $1 AND user_id = $2
+		RETURNING shared_at
+	`, c.ID, c.UserID, token, expiresAt).Scan(&at); err != nil {
+		return err
+	}
+	c.Share = &Share{Token: token, ExpiresAt: expiresAt, CreatedAt: at}
+	return nil
+}
+
+// DisableShare revokes a collection's share link.
+func DisableShare(db *sql.DB, c *Collection) error {
+	_, err := db.Exec(`
+		UPDATE collections SET share_token = NULL, share_expires_at = NULL, shared_at = NULL
+		WHERE id = $1 AND user_id = $2
+	`, c.ID, c.UserID)
+	if err == nil {
+		c.Share = nil
+	}
+	return err
+}
+
+// ByShareToken returns the collection a share link points at, ErrNotFound
+// for unknown or revoked links and ErrExpired once the link expired.
+func ByShareToken(db *sql.DB, token string) (*Collection, error) {
+	var c Collection
+	err := scanCollection(db.QueryRow(`
+		SELECT `+collectionCols+` FROM collections c WHERE c.share_token = $1
+	`, token), &c)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.Share.ExpiresAt != nil && !c.Share.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpired
+	}
+	return &c, nil
+}
@@ -0,0 +1,263 @@
+// Package cosaved turns saved_events into collaborative recommendations:
+// "people who saved this also saved" lists per event, and personal picks
+// per user drawn from the lists of the events they saved.
+//
+// A Builder rebuilds both tables periodically. Two events only count as
+// saved together when enough different users saved both (DefaultMinSupport,
+// or COSAVED_MIN_SUPPORT), so a list never reveals what a single user saved.
+// Events a user marked skipped do not count as saved; events they attended
+// weigh double in their personal picks.
+package cosaved
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DefaultMinSupport is the smallest number of users who must have saved two
+// events for them to count as saved together. COSAVED_MIN_SUPPORT overrides
+// it; values below 2 are raised to 2.
+const DefaultMinSupport = 3
+
+// Schema holds the DDL for the co-occurrence tables. event_cosaved keeps the
+// best matches of every event; user_cosaved the best picks for every user,
+// with the saved events that led to each.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS event_cosaved (
+		event_id   INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		other_id   INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		savers     INTEGER NOT NULL,
+		score      DOUBLE PRECISION NOT NULL,
+		built_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (event_id, other_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS user_cosaved (
+		user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		event_id   INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		score      DOUBLE PRECISION NOT NULL,
+		because    INTEGER[] NOT NULL DEFAULT '{}',
+		built_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (user_id, event_id)
+	)`,
+}
+
+// EnsureSchema creates the co-occurrence tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("cosaved migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// lockKey serializes rebuilds across servers ("cosaved" in ASCII).
+const lockKey = 0x636f7361766564
+
+// Builder rebuilds event_cosaved and user_cosaved from saved_events.
+type Builder struct {
+	db         *sql.DB
+	interval   time.Duration
+	minSupport int
+	perEvent   int
+	perUser    int
+	maxBecause int
+}
+
+func NewBuilder(db *sql.DB) *Builder {
+	minSupport := DefaultMinSupport
+	if v, err := strconv.Atoi(os.Getenv("COSAVED_MIN_SUPPORT")); err == nil {
+		minSupport = v
+	}
+	if minSupport < 2 {
+		minSupport = 2
+	}
+	return &Builder{
+		db:         db,
+		interval:   time.Hour,
+		minSupport: minSupport,
+		perEvent:   20,
+		perUser:    50,
+		maxBecause: 3,
+	}
+}
+
+// Run rebuilds the tables every interval until ctx is cancelled.
+func (b *Builder) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		if pairs, users, err := b.Rebuild(ctx); err != nil {
+			log.Printf("⚠️  Rebuilding also-saved lists failed: %v", err)
+		} else {
+			log.Printf("🔗 Rebuilt also-saved lists: %d pairs, %d users", pairs, users)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebuild replaces both tables in one transaction and returns how many
+// event pairs and users got entries. Servers sharing a database take turns
+// through an advisory lock.
+func (b *Builder) Rebuild(ctx context.Context) (pairs, users int, err error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, lockKey); err != nil {
+		return 0, 0, err
+	}
+
+	// Only upcoming and undated events are recommended. Matches are scored
+	// by the cosine similarity of the events' saver sets: savers of both /
+	// sqrt(savers of one * savers of the other), so a popular event does
+	// not top every list.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_cosaved`); err != nil {
+		return 0, 0, err
+	}
+	res, err := tx.ExecContext(ctx, `
+		WITH kept AS (
+			SELECT user_id, event_id FROM saved_events WHERE status <> 'skipped'
+		), counts AS (
+			SELECT event_id, COUNT(*) AS n FROM kept GROUP BY event_id
+		), pairs AS (
+			SELECT a.event_id, b.event_id AS other_id, COUNT(*) AS savers,
+			       COUNT(*) / sqrt(MAX(ca.n) * MAX(cb.n)) AS score
+			FROM kept a
+			JOIN kept b ON b.user_id = a.user_id AND b.event_id <> a.event_id
+			JOIN counts ca ON ca.event_id = a.event_id
+			JOIN counts cb ON cb.event_id = b.event_id
+			JOIN events o ON o.id = b.event_id
+			WHERE CASE WHEN o.date ~ '^\d{4}-\d{2}-\d{2}$'
+			           THEN o.date::date >= CURRENT_DATE ELSE true END
+			GROUP BY a.event_id, b.event_id
+			HAVING COUNT(*) >= $1
+		)
+		INSERT INTO event_cosaved (event_id, other_id, savers, score)
+		SELECT event_id, other_id, savers, score FROM (
+			SELECT pairs.*, ROW_NUMBER() OVER (
+				PARTITION BY event_id ORDER BY score DESC, savers DESC, other_id
+			) AS rank
+			FROM pairs
+		) ranked
+		WHERE rank <= $2
+	`, b.minSupport, b.perEvent)
+	if err != nil {
+		return 0, 0, err
+	}
+	n, _ := res.RowsAffected()
+	pairs = int(n)
+
+	// A user's picks add up the scores of every saved event whose list
+	// holds them, doubled for events they attended, leaving out events the
+	// user already saved.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_cosaved`); err != nil {
+		return 0, 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO user_cosaved (user_id, event_id, score, because)
+		SELECT user_id, event_id, score, because FROM (
+			SELECT se.user_id::uuid AS user_id, c.other_id AS event_id, SUM(c.score * se.weight) AS score,
+			       (ARRAY_AGG(c.event_id ORDER BY c.score * se.weight DESC, c.event_id))[1:$2] AS because,
+			       ROW_NUMBER() OVER (
+			           PARTITION BY se.user_id ORDER BY SUM(c.score * se.weight) DESC, c.other_id
+			       ) AS rank
+			FROM (
+				SELECT user_id, event_id, CASE WHEN status = 'attended' THEN 2 ELSE 1 END AS weight
+				FROM saved_events WHERE status <> 'skipped'
+			) se
+			JOIN event_cosaved c ON c.event_id = se.event_id
+			WHERE NOT EXISTS (
+				SELECT 1 FROM saved_events own
+				WHERE own.user_id = se.user_id AND own.event_id = c.other_id
+			)
+			GROUP BY se.user_id, c.other_id
+		) ranked
+		WHERE rank <= $1
+	`, b.perUser, b.maxBecause); err != nil {
+		return 0, 0, err
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(DISTINCT user_id) FROM user_cosaved`).Scan(&users); err != nil {
+		return 0, 0, err
+	}
+	return pairs, users, tx.Commit()
+}
+
+// Match is an event saved together with another one.
+type Match struct {
+	EventID int64
+	Savers  int
+	Score   float64
+}
+
+// AlsoSaved returns the events most often saved together with eventID, best
+// first, as of the last rebuild.
+func AlsoSaved(db *sql.DB, eventID int64) ([]Match, error) {
+	rows, err := db.Query(`
+		SELECT other_id, savers, score FROM event_cosaved
+		WHERE event_id = $1
+		ORDER BY score DESC, savers DESC, other_id
+	`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Match
+	for rows.Next() {
+		var m Match
+		if err := rows.Scan(&m.EventID, &m.Savers, &m.Score); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// Pick is a personal recommendation. Because lists the user's saved events
+// whose also-saved lists hold it, strongest first.
+type Pick struct {
+	EventID int64
+	Score   float64
+	Because []int64
+}
+
+// ForUser returns a user's picks, best first, as of the last rebuild.
+// Events the user saved since are left out.
+func ForUser(db *sql.DB, userID string) ([]Pick, error) {
+	rows, err := db.Query(`
+		SELECT u.event_id, u.score, u.because FROM user_cosaved u
+		WHERE u.user_id = $1
+		  AND NOT EXISTS (SELECT 1 FROM saved_events s WHERE s.user_id = $1 AND s.event_id = u.event_id)
+		ORDER BY u.score DESC, u.event_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Pick
+	for rows.Next() {
+		var p Pick
+		if err := rows.Scan(&p.EventID, &p.Score, pq.Array(&p.Because)); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
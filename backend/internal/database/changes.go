@@ -0,0 +1,98 @@
+package database
+
+import (
+	"database/sql"
+	"event-scraper/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CollectEventChanges returns the events a cycle inserted or changed since the
+// given time. Every upsert bumps updated_at, so the content fingerprint stored in
+// event_fingerprints is what tells a real change apart from a re-scrape.
+//
+// Events that predate fingerprint tracking are recorded silently on first sight
+// instead of being reported as updates.
+func (db *DB) CollectEventChanges(since time.Time) ([]models.EventChange, error) {
+	return collectChanges(db.conn, since, `e.updated_at >= $1 OR e.created_at >= $1`, since)
+}
+
+// CollectEventChangesByID is CollectEventChanges for events changed outside a
+// cycle, such as by re-normalizing their city. They are never reported as
+// created.
+func CollectEventChangesByID(conn *sql.DB, ids []int64) ([]models.EventChange, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return collectChanges(conn, time.Now(), `e.id = ANY($1)`, pq.Array(ids))
+}
+
+// collectChanges reports the events matching where whose fingerprint
+// changed, as created when they are new since since.
+func collectChanges(conn *sql.DB, since time.Time, where string, arg interface{}) ([]models.EventChange, error) {
+	rows, err := conn.Query(`
+		SELECT e.id, e.event_name, COALESCE(e.location, ''), COALESCE(e.city_normalized, ''),
+		       COALESCE(e.date_time, ''), COALESCE(e.date, ''), COALESCE(e.time, ''),
+		       COALESCE(e.website, ''), COALESCE(e.description, ''), COALESCE(e.address, ''),
+		       COALESCE(e.event_type, ''), e.platform, COALESCE(e.hash, ''),
+		       e.created_at, e.updated_at,
+		       COALESCE(f.fingerprint, '')
+		FROM events e
+		LEFT JOIN event_fingerprints f ON f.event_id = e.id
+		WHERE `+where+`
+		ORDER BY e.id
+	`, arg)
+	if err != nil {
+		return nil, fmt.Errorf("change query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.EventChange
+	var seen []models.Event
+	for rows.Next() {
+		var e models.Event
+		var previous string
+		if err := rows.Scan(
+			&e.ID, &e.EventName, &e.Location, &e.CityNormalized,
+			&e.DateTime, &e.Date, &e.Time,
+			&e.Website, &e.Description, &e.Address,
+			&e.EventType, &e.Platform, &e.Hash,
+			&e.CreatedAt, &e.UpdatedAt,
+			&previous,
+		); err != nil {
+			continue
+		}
+
+		current := e.Fingerprint()
+		if current == previous {
+			continue
+		}
+		seen = append(seen, e)
+
+		switch {
+		case previous == "" && !e.CreatedAt.Before(since):
+			changes = append(changes, models.EventChange{Kind: models.EventCreated, Event: e})
+		case previous != "":
+			changes = append(changes, models.EventChange{Kind: models.EventUpdated, Event: e})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, e := range seen {
+		if _, err := conn.Exec(`
+			INSERT INTO event_fingerprints (event_id, fingerprint, updated_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (event_id) DO UPDATE SET
+				fingerprint = EXCLUDED.fingerprint,
+				updated_at  = NOW()
+		`, e.ID, e.Fingerprint()); err != nil {
+			return changes, fmt.Errorf("fingerprint update failed: %w", err)
+		}
+	}
+
+	return changes, nil
+}
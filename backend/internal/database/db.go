@@ -2,6 +2,8 @@ package database
 
 import (
 	"database/sql"
+	"event-scraper/internal/gazetteer"
+	"event-scraper/internal/geo"
 	"event-scraper/internal/models"
 	"fmt"
 	"strings"
@@ -136,7 +138,9 @@ func (db *DB) Migrate() error {
 		`CREATE INDEX IF NOT EXISTS idx_events_platform ON events(platform)`,
 		`CREATE INDEX IF NOT EXISTS idx_events_hash ON events(hash)`,
 		`CREATE INDEX IF NOT EXISTS idx_events_created_at ON events(created_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_events_created_at_id ON events(created_at DESC, id DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_events_city_normalized ON events(city_normalized)`,
+		`CREATE INDEX IF NOT EXISTS idx_events_city_normalized_lower ON events(lower(city_normalized))`,
 		`CREATE INDEX IF NOT EXISTS idx_event_details_event_id ON event_details(event_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_event_details_last_scraped ON event_details(last_scraped)`,
 		`CREATE INDEX IF NOT EXISTS idx_saved_events_user_id ON saved_events(user_id)`,
@@ -144,11 +148,22 @@ func (db *DB) Migrate() error {
 		`CREATE INDEX IF NOT EXISTS idx_scraper_runs_name ON scraper_runs(scraper_name)`,
 		`CREATE INDEX IF NOT EXISTS idx_scraper_runs_run_at ON scraper_runs(run_at DESC)`,
 
+		// Last content fingerprint seen per event — drives change notifications.
+		`CREATE TABLE IF NOT EXISTS event_fingerprints (
+			event_id INTEGER PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+			fingerprint TEXT NOT NULL,
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`,
+
 		// Unique index on website URL — database-level hard stop against URL duplicates.
 		`CREATE UNIQUE INDEX IF NOT EXISTS idx_events_website_unique
 		 ON events(website)
 		 WHERE website IS NOT NULL AND website != ''`,
 	}
+	// Coordinates from the offline gazetteer, filled in by geo.Geocoder.
+	queries = append(queries, geo.Schema...)
+	// Runtime aliases on top of the embedded gazetteer.
+	queries = append(queries, gazetteer.Schema...)
 
 	for _, query := range queries {
 		if _, err := db.conn.Exec(query); err != nil {
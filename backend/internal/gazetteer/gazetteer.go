@@ -0,0 +1,383 @@
+// Package gazetteer turns free-text locations into canonical cities and
+// coordinates. Its data, places.tsv, is the one list of cities, aliases,
+// localities and venues in the project: the Python scrapers read the same
+// file, and admins can add aliases at runtime (see store.go).
+package gazetteer
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// Kind is the type of a gazetteer entry.
+type Kind string
+
+const (
+	KindVenue    Kind = "venue"    // a known venue
+	KindLocality Kind = "locality" // a neighbourhood or area
+	KindCity     Kind = "city"     // a canonical city name
+	KindAlias    Kind = "alias"    // another name for a city
+	KindState    Kind = "state"    // a state, standing for one of its cities
+)
+
+// Confidence is how precisely a Match locates an event.
+type Confidence string
+
+const (
+	ConfidenceVenue    Confidence = "venue"
+	ConfidenceLocality Confidence = "locality"
+	ConfidenceCity     Confidence = "city" // only the city; the point is its centre
+)
+
+// City is a canonical city. HasPoint is false for Online.
+type City struct {
+	Name     string  `json:"name"`
+	State    string  `json:"state,omitempty"`
+	Country  string  `json:"country,omitempty"`
+	Lat      float64 `json:"lat,omitempty"`
+	Lng      float64 `json:"lng,omitempty"`
+	HasPoint bool    `json:"-"`
+}
+
+// Place is one name that identifies a city. Name is normalized. HasPoint is
+// false for cities, aliases and states, which locate to the city centre, and
+// for venues and localities whose position is unknown.
+type Place struct {
+	Kind     Kind
+	Name     string
+	City     string
+	Lat, Lng float64
+	HasPoint bool
+}
+
+// Match is the result of geocoding a location.
+type Match struct {
+	Lat, Lng   float64
+	City       string
+	Confidence Confidence
+	// Matched is the gazetteer name that decided the match.
+	Matched string
+}
+
+// Gazetteer resolves free-text locations. It is immutable; WithAliases
+// returns a copy.
+type Gazetteer struct {
+	places []Place
+	cities map[string]City
+	byName map[string]string // lower-cased canonical name → canonical name
+}
+
+//go:embed places.tsv
+var placesTSV string
+
+// Default is the embedded gazetteer, without runtime aliases.
+var Default = mustParse(placesTSV)
+
+var current atomic.Pointer[Gazetteer]
+
+func init() { current.Store(Default) }
+
+// Current is the gazetteer in use: Default plus the aliases last loaded by
+// Refresh.
+func Current() *Gazetteer { return current.Load() }
+
+func mustParse(data string) *Gazetteer {
+	g, err := Parse(strings.NewReader(data))
+	if err != nil {
+		panic(fmt.Sprintf("gazetteer: embedded places.tsv: %v", err))
+	}
+	return g
+}
+
+// Parse reads a gazetteer in the format described at the top of places.tsv.
+func Parse(r io.Reader) (*Gazetteer, error) {
+	g := &Gazetteer{cities: make(map[string]City), byName: make(map[string]string)}
+	var places []Place
+	lines := make(map[int]int) // index in places → line, for errors
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		f := strings.Split(text, "\t")
+		kind := Kind(f[0])
+
+		var coords []string
+		switch kind {
+		case KindCity:
+			if len(f) != 4 && len(f) != 6 {
+				return nil, fmt.Errorf("line %d: a city wants 4 or 6 fields, got %d", line, len(f))
+			}
+			c := City{Name: strings.TrimSpace(f[1]), State: dash(f[2]), Country: dash(f[3])}
+			if c.Name == "" {
+				return nil, fmt.Errorf("line %d: city name is required", line)
+			}
+			if _, dup := g.byName[strings.ToLower(c.Name)]; dup {
+				return nil, fmt.Errorf("line %d: city %q declared twice", line, c.Name)
+			}
+			if len(f) == 6 {
+				var err error
+				if c.Lat, c.Lng, err = parsePoint(f[4], f[5]); err != nil {
+					return nil, fmt.Errorf("line %d: %v", line, err)
+				}
+				c.HasPoint = true
+			}
+			g.cities[c.Name] = c
+			g.byName[strings.ToLower(c.Name)] = c.Name
+			f = []string{f[0], f[1], c.Name}
+		case KindAlias, KindState:
+			if len(f) != 3 {
+				return nil, fmt.Errorf("line %d: %s wants 3 fields, got %d", line, kind, len(f))
+			}
+		case KindVenue, KindLocality:
+			if len(f) != 3 && len(f) != 5 {
+				return nil, fmt.Errorf("line %d: %s wants 3 or 5 fields, got %d", line, kind, len(f))
+			}
+			coords = f[3:]
+		default:
+			return nil, fmt.Errorf("line %d: unknown kind %q", line, f[0])
+		}
+
+		p := Place{Kind: kind, Name: Normalize(f[1]), City: strings.TrimSpace(f[2])}
+		if p.Name == "" || p.City == "" {
+			return nil, fmt.Errorf("line %d: name and city are required", line)
+		}
+		if len(coords) == 2 {
+			var err error
+			if p.Lat, p.Lng, err = parsePoint(coords[0], coords[1]); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			p.HasPoint = true
+		}
+		lines[len(places)] = line
+		places = append(places, p)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	// Cities may be declared after the rows that use them.
+	for i, p := range places {
+		if _, ok := g.cities[p.City]; !ok {
+			return nil, fmt.Errorf("line %d: unknown city %q", lines[i], p.City)
+		}
+	}
+	g.places = dedupe(places)
+	return g, nil
+}
+
+func dash(s string) string {
+	if s = strings.TrimSpace(s); s == "-" {
+		return ""
+	}
+	return s
+}
+
+func parsePoint(lat, lng string) (float64, float64, error) {
+	la, err1 := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	ln, err2 := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err1 != nil || err2 != nil || math.Abs(la) > 90 || math.Abs(ln) > 180 {
+		return 0, 0, fmt.Errorf("bad coordinates %q, %q", lat, lng)
+	}
+	return la, ln, nil
+}
+
+// dedupe drops repeats of a name within a city (the first wins) and sorts
+// the rest into a fixed order, so nothing depends on file or map order.
+// The same name may exist in two cities (Malviya Nagar); Geocode's city
+// hint picks between them.
+func dedupe(places []Place) []Place {
+	seen := make(map[string]bool, len(places))
+	out := places[:0:0]
+	for _, p := range places {
+		key := p.Name + "\x00" + p.City
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, p)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if tier(a.Kind) != tier(b.Kind) {
+			return tier(a.Kind) > tier(b.Kind)
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) > len(b.Name)
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.City < b.City
+	})
+	return out
+}
+
+// tier orders kinds for deciding a city: a venue or locality says more
+// than a city name, which says more than a state.
+func tier(k Kind) int {
+	switch k {
+	case KindVenue, KindLocality:
+		return 2
+	case KindCity, KindAlias:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// precision orders kinds for picking coordinates within a city.
+func precision(k Kind) int {
+	switch k {
+	case KindVenue:
+		return 2
+	case KindLocality:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ─── Lookup ───────────────────────────────────────────────────────────────────
+
+// City returns the canonical city with the given name, ignoring case.
+func (g *Gazetteer) City(name string) (City, bool) {
+	c, ok := g.cities[g.byName[strings.ToLower(strings.TrimSpace(name))]]
+	return c, ok
+}
+
+// Cities lists the canonical cities by name.
+func (g *Gazetteer) Cities() []City {
+	cities := make([]City, 0, len(g.cities))
+	for _, c := range g.cities {
+		cities = append(cities, c)
+	}
+	sort.Slice(cities, func(i, j int) bool { return cities[i].Name < cities[j].Name })
+	return cities
+}
+
+// Lookup returns the entries named name (normalized first), most specific
+// first.
+func (g *Gazetteer) Lookup(name string) []Place {
+	name = Normalize(name)
+	var out []Place
+	for _, p := range g.places {
+		if p.Name == name {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+type hit struct {
+	Place
+	pos int
+}
+
+// hits finds every entry named in texts, in the gazetteer's order. The
+// texts are searched together; pos is the offset of the hit, so earlier
+// texts come first.
+func (g *Gazetteer) hits(texts []string) []hit {
+	haystack := " "
+	for _, t := range texts {
+		if n := Normalize(t); n != "" {
+			haystack += n + " | "
+		}
+	}
+	var hits []hit
+	for _, p := range g.places {
+		if i := strings.Index(haystack, " "+p.Name+" "); i >= 0 {
+			hits = append(hits, hit{p, i})
+		}
+	}
+	return hits
+}
+
+// best picks the entry that decides the city: the highest tier, then the
+// longest name, then the earliest in the text. hits is in gazetteer order,
+// so only equal tiers and lengths need comparing.
+func best(hits []hit) (hit, bool) {
+	if len(hits) == 0 {
+		return hit{}, false
+	}
+	b := hits[0]
+	for _, h := range hits[1:] {
+		if tier(h.Kind) != tier(b.Kind) || len(h.Name) != len(b.Name) {
+			break
+		}
+		if h.pos < b.pos {
+			b = h
+		}
+	}
+	return b, true
+}
+
+// Resolve returns the entry that names the city of a location, trying
+// texts together: a venue in the address beats a city name in the
+// location. See places.tsv for the rules.
+func (g *Gazetteer) Resolve(texts ...string) (Place, bool) {
+	h, ok := best(g.hits(texts))
+	return h.Place, ok
+}
+
+// Geocode locates texts as precisely as the gazetteer allows. city, when it
+// is a canonical city such as CityNormalized, keeps matches inside it and
+// is the fallback when nothing more precise matches; otherwise the city is
+// resolved from texts.
+func (g *Gazetteer) Geocode(city string, texts ...string) (Match, bool) {
+	hits := g.hits(texts)
+	if _, ok := g.cities[city]; ok {
+		inCity := hits[:0:0]
+		for _, h := range hits {
+			if h.City == city {
+				inCity = append(inCity, h)
+			}
+		}
+		if len(inCity) > 0 || len(hits) == 0 {
+			hits = inCity
+		}
+	} else if b, ok := best(hits); ok {
+		city = b.City
+	}
+
+	var located *hit
+	for i, h := range hits {
+		if h.City != city || !h.HasPoint {
+			continue
+		}
+		if located == nil || precision(h.Kind) > precision(located.Kind) ||
+			precision(h.Kind) == precision(located.Kind) && len(h.Name) > len(located.Name) {
+			located = &hits[i]
+		}
+	}
+	if located != nil {
+		return Match{Lat: located.Lat, Lng: located.Lng, City: city,
+			Confidence: Confidence(located.Kind), Matched: located.Name}, true
+	}
+
+	if c, ok := g.cities[city]; ok && c.HasPoint {
+		matched := Normalize(c.Name)
+		if b, ok := best(hits); ok {
+			matched = b.Name
+		}
+		return Match{Lat: c.Lat, Lng: c.Lng, City: c.Name, Confidence: ConfidenceCity, Matched: matched}, true
+	}
+	return Match{}, false
+}
+
+// Normalize lower-cases s and turns punctuation into single spaces, so
+// "T-Hub, Hyderabad" and "t hub hyderabad" compare equal.
+func Normalize(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,254 @@
+package gazetteer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Alias is a name added at runtime, on top of places.tsv: another name for
+// a city (KindAlias) or a venue or locality in it, optionally with a
+// position.
+type Alias struct {
+	Name      string    `json:"name"`
+	Kind      Kind      `json:"kind"`
+	City      string    `json:"city"`
+	Lat       *float64  `json:"lat,omitempty"`
+	Lng       *float64  `json:"lng,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var (
+	ErrNotFound     = errors.New("alias not found")
+	ErrUnknownCity  = errors.New("unknown city")
+	ErrBuiltIn      = errors.New("name is already in the gazetteer")
+	ErrUnknownPlace = errors.New("no venue or locality by that name")
+)
+
+// Schema holds the DDL for runtime aliases. name is normalized.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS gazetteer_aliases (
+		name       TEXT PRIMARY KEY,
+		city       VARCHAR(100) NOT NULL,
+		created_by UUID,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`ALTER TABLE gazetteer_aliases ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'alias'`,
+	`ALTER TABLE gazetteer_aliases ADD COLUMN IF NOT EXISTS lat DOUBLE PRECISION`,
+	`ALTER TABLE gazetteer_aliases ADD COLUMN IF NOT EXISTS lng DOUBLE PRECISION`,
+}
+
+// EnsureSchema creates the gazetteer_aliases table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("gazetteer migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate normalises an alias before it is stored, checking it against
+// Default: city must be a canonical city (any case) and name must not
+// already be in places.tsv. Only venues and localities have a position.
+func (a *Alias) Validate() error {
+	a.Name = Normalize(a.Name)
+	if a.Name == "" {
+		return errors.New("name is required")
+	}
+	if a.Kind == "" {
+		a.Kind = KindAlias
+	}
+	switch a.Kind {
+	case KindAlias, KindVenue, KindLocality:
+	default:
+		return errors.New("kind must be alias, venue or locality")
+	}
+	c, ok := Default.City(a.City)
+	if !ok {
+		return ErrUnknownCity
+	}
+	a.City = c.Name
+	if (a.Lat == nil) != (a.Lng == nil) {
+		return errors.New("lat and lng go together")
+	}
+	if a.Lat != nil {
+		if a.Kind == KindAlias {
+			return errors.New("only a venue or locality has lat and lng")
+		}
+		if !(math.Abs(*a.Lat) <= 90 && math.Abs(*a.Lng) <= 180) {
+			return errors.New("lat must be between -90 and 90 and lng between -180 and 180")
+		}
+	}
+	if len(Default.Lookup(a.Name)) > 0 {
+		return ErrBuiltIn
+	}
+	return nil
+}
+
+// Place finds the venue or locality called name, for mapping another name
+// onto it. city picks between places of the same name in different cities
+// and may be empty.
+func (g *Gazetteer) Place(name, city string) (Place, error) {
+	var found []Place
+	for _, p := range g.Lookup(name) {
+		if (p.Kind == KindVenue || p.Kind == KindLocality) && (city == "" || strings.EqualFold(p.City, city)) {
+			found = append(found, p)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return Place{}, ErrUnknownPlace
+	case 1:
+		return found[0], nil
+	default:
+		return Place{}, fmt.Errorf("%q is in more than one city; name the city", name)
+	}
+}
+
+// WithAliases returns a copy of g that also knows aliases. Aliases for
+// unknown cities or names g already has are skipped.
+func (g *Gazetteer) WithAliases(aliases []Alias) *Gazetteer {
+	places := append([]Place(nil), g.places...)
+	for _, a := range aliases {
+		name := Normalize(a.Name)
+		if _, ok := g.cities[a.City]; !ok || name == "" || len(g.Lookup(name)) > 0 {
+			continue
+		}
+		p := Place{Kind: a.Kind, Name: name, City: a.City}
+		if p.Kind == "" {
+			p.Kind = KindAlias
+		}
+		if a.Lat != nil && a.Lng != nil {
+			p.Lat, p.Lng, p.HasPoint = *a.Lat, *a.Lng, true
+		}
+		places = append(places, p)
+	}
+	return &Gazetteer{places: dedupe(places), cities: g.cities, byName: g.byName}
+}
+
+// ─── Store ────────────────────────────────────────────────────────────────────
+
+// ListAliases returns the runtime aliases by name.
+func ListAliases(db *sql.DB) ([]Alias, error) {
+	rows, err := db.Query(`
+		SELECT name, kind, city, lat, lng, COALESCE(created_by::text, ''), created_at
+		FROM gazetteer_aliases
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []Alias{}
+	for rows.Next() {
+		var a Alias
+		if err := rows.Scan(&a.Name, &a.Kind, &a.City, &a.Lat, &a.Lng, &a.CreatedBy, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, a)
+	}
+	return aliases, rows.Err()
+}
+
+// SaveAlias stores a, replacing an existing alias of the same name. a
+// should have been validated.
+func SaveAlias(db *sql.DB, a *Alias) error {
+	var createdBy interface{}
+	if a.CreatedBy != "" {
+		createdBy = a.CreatedBy
+	}
+	return db.QueryRow(`
+		INSERT INTO gazetteer_aliases (name, kind, city, lat, lng, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			kind = EXCLUDED.kind, city = EXCLUDED.city,
+			lat = EXCLUDED.lat, lng = EXCLUDED.lng,
+			created_by = EXCLUDED.created_by, created_at = now()
+		RETURNING created_at
+	`, a.Name, a.Kind, a.City, a.Lat, a.Lng, createdBy).Scan(&a.CreatedAt)
+}
+
+// DeleteAlias removes the alias called name.
+func DeleteAlias(db *sql.DB, name string) error {
+	res, err := db.Exec(`DELETE FROM gazetteer_aliases WHERE name = $1`, Normalize(name))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Refresh rebuilds Current from Default and the stored aliases. Processes
+// other than the API server call it before resolving, so an alias added
+// through the API reaches them without a restart.
+func Refresh(db *sql.DB) error {
+	aliases, err := ListAliases(db)
+	if err != nil {
+		return err
+	}
+	current.Store(Default.WithAliases(aliases))
+	return nil
+}
+
+// ─── Re-normalizing ───────────────────────────────────────────────────────────
+
+// Renormalize runs resolve over the location and address of every event
+// that mentions name (normalized, on word boundaries) and stores the cities
+// it finds. An event keeps its city when resolve finds none. It returns
+// the IDs of the matching events, whether or not their city changed, and
+// the IDs of those that changed, which callers announce like any other
+// event change. On error, the changes made so far are still returned.
+func Renormalize(db *sql.DB, name string, resolve func(location, address string) string) (matched, changed []int64, err error) {
+	name = Normalize(name)
+	if name == "" {
+		return nil, nil, nil
+	}
+	// The same normalization as Normalize: lower case, runs of anything
+	// but letters and digits as one space.
+	norm := func(col string) string {
+		return fmt.Sprintf(`' ' || regexp_replace(lower(COALESCE(%s, '')), '[^[:alnum:]]+', ' ', 'g') || ' '`, col)
+	}
+	rows, err := db.Query(fmt.Sprintf(`
+		SELECT id, COALESCE(location, ''), COALESCE(address, ''), COALESCE(city_normalized, '')
+		FROM events
+		WHERE strpos(%s, $1) > 0 OR strpos(%s, $1) > 0
+	`, norm("location"), norm("address")), " "+name+" ")
+	if err != nil {
+		return nil, nil, err
+	}
+	cities := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var location, address, current string
+		if err := rows.Scan(&id, &location, &address, &current); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		matched = append(matched, id)
+		if city := resolve(location, address); city != "" && city != "Unknown" && city != current {
+			cities[id] = city
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	for id, city := range cities {
+		if _, err := db.Exec(
+			`UPDATE events SET city_normalized = $2, updated_at = NOW() WHERE id = $1`, id, city,
+		); err != nil {
+			return matched, changed, err
+		}
+		changed = append(changed, id)
+	}
+	return matched, changed, nil
+}
@@ -0,0 +1,110 @@
+package gazetteer
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// unknownCity matches events whose city could not be resolved.
+const unknownCity = `(city_normalized IS NULL OR city_normalized IN ('', 'Unknown'))`
+
+// Unresolved is a location or address shared by events whose city is
+// Unknown. Mapping Text (as an alias, venue or locality) resolves them.
+type Unresolved struct {
+	Text    string        `json:"text"`
+	Field   string        `json:"field"` // "location" or "address"
+	Count   int           `json:"count"`
+	Samples []SampleEvent `json:"samples"`
+}
+
+// SampleEvent is an event quoted in the triage report.
+type SampleEvent struct {
+	ID        int64  `json:"id"`
+	EventName string `json:"event_name"`
+	Platform  string `json:"platform"`
+	Website   string `json:"website"`
+}
+
+// UnresolvedTexts lists the most frequent locations and addresses of events
+// with an Unknown city, up to limit, each with up to samples of its newest
+// events. Texts for which skip returns true, such as ones no mapping could
+// resolve, are left out. It also returns how many events have an Unknown
+// city.
+func UnresolvedTexts(db *sql.DB, limit, samples int, skip func(text string) bool) ([]Unresolved, int, error) {
+	var unknown int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM events WHERE ` + unknownCity).Scan(&unknown); err != nil {
+		return nil, 0, err
+	}
+
+	// Texts are grouped case-insensitively and shown in their most common
+	// spelling. The cap bounds the scan when skip drops many.
+	rows, err := db.Query(`
+		SELECT field, mode() WITHIN GROUP (ORDER BY text), COUNT(*),
+		       (array_agg(id ORDER BY id DESC))[1:$1]
+		FROM (
+			SELECT id, 'location' AS field, btrim(location) AS text FROM events WHERE `+unknownCity+`
+			UNION ALL
+			SELECT id, 'address', btrim(address) FROM events WHERE `+unknownCity+`
+		) x
+		WHERE text IS NOT NULL AND text NOT IN ('', 'N/A')
+		GROUP BY field, lower(text)
+		ORDER BY 3 DESC, 2 ASC
+		LIMIT 1000
+	`, samples)
+	if err != nil {
+		return nil, 0, err
+	}
+	texts := []Unresolved{}
+	sampleIDs := make(map[int][]int64)
+	var allIDs []int64
+	for rows.Next() && len(texts) < limit {
+		var u Unresolved
+		var ids []int64
+		if err := rows.Scan(&u.Field, &u.Text, &u.Count, pq.Array(&ids)); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		if skip != nil && skip(u.Text) {
+			continue
+		}
+		sampleIDs[len(texts)] = ids
+		allIDs = append(allIDs, ids...)
+		texts = append(texts, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	events := make(map[int64]SampleEvent, len(allIDs))
+	if len(allIDs) > 0 {
+		rows, err := db.Query(`
+			SELECT id, event_name, platform, COALESCE(website, '')
+			FROM events WHERE id = ANY($1)
+		`, pq.Array(allIDs))
+		if err != nil {
+			return nil, 0, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var e SampleEvent
+			if err := rows.Scan(&e.ID, &e.EventName, &e.Platform, &e.Website); err != nil {
+				return nil, 0, err
+			}
+			events[e.ID] = e
+		}
+		if err := rows.Err(); err != nil {
+			return nil, 0, err
+		}
+	}
+	for i := range texts {
+		texts[i].Samples = []SampleEvent{}
+		for _, id := range sampleIDs[i] {
+			if e, ok := events[id]; ok {
+				texts[i].Samples = append(texts[i].Samples, e)
+			}
+		}
+	}
+	return texts, unknown, nil
+}
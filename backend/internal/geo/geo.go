@@ -0,0 +1,33 @@
+// Package geo places events on the map without calling out to a geocoding
+// service. Locations are matched against internal/gazetteer, and every
+// result says how precise it is.
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// ─── Distance ─────────────────────────────────────────────────────────────────
+
+// EarthRadiusKm is the mean radius used for great-circle distances.
+const EarthRadiusKm = 6371.0
+
+// DistanceKm is the great-circle distance between two points.
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// DistanceSQL is DistanceKm as a Postgres expression over the given
+// column and parameter expressions.
+func DistanceSQL(lat, lng, fromLat, fromLng string) string {
+	return fmt.Sprintf(`(2 * %[5]g * asin(least(1, sqrt(
+		power(sin(radians(%[1]s - %[3]s) / 2), 2) +
+		cos(radians(%[3]s)) * cos(radians(%[1]s)) * power(sin(radians(%[2]s - %[4]s) / 2), 2)))))`,
+		lat, lng, fromLat, fromLng, EarthRadiusKm)
+}
@@ -0,0 +1,167 @@
+package geo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"event-scraper/internal/gazetteer"
+
+	"github.com/lib/pq"
+)
+
+// Schema holds the DDL for event coordinates. Events that could not be
+// placed keep a row with NULL coordinates, so they are only retried once
+// the event or its cleaned location changes; event_updated_at and
+// cleaned_at record the versions that were geocoded.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS event_geo (
+		event_id         INTEGER PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+		lat              DOUBLE PRECISION,
+		lng              DOUBLE PRECISION,
+		confidence       VARCHAR(20) NOT NULL DEFAULT '',
+		matched          TEXT NOT NULL DEFAULT '',
+		event_updated_at TIMESTAMP,
+		cleaned_at       TIMESTAMP,
+		geocoded_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_event_geo_point ON event_geo(lat, lng) WHERE lat IS NOT NULL`,
+}
+
+// EnsureSchema creates the event_geo table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("geo migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Invalidate drops the coordinates of events so that GeocodePending places
+// them again, e.g. after the gazetteer learned a new venue.
+func Invalidate(db *sql.DB, eventIDs []int64) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	_, err := db.Exec(`DELETE FROM event_geo WHERE event_id = ANY($1)`, pq.Array(eventIDs))
+	return err
+}
+
+// Geocoder keeps event_geo in step with events: new events, and events
+// updated since they were last placed, are geocoded in batches against
+// gazetteer.Current.
+type Geocoder struct {
+	db           *sql.DB
+	pollInterval time.Duration
+	batchSize    int
+}
+
+func NewGeocoder(db *sql.DB) *Geocoder {
+	return &Geocoder{db: db, pollInterval: 10 * time.Minute, batchSize: 500}
+}
+
+// Run geocodes pending events until ctx is cancelled.
+func (g *Geocoder) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := g.GeocodePending(ctx); err != nil {
+			log.Printf("⚠️  Geocoding failed: %v", err)
+		} else if n > 0 {
+			log.Printf("📍 Geocoded %d events", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GeocodePending places every event that has no event_geo row or changed
+// since it got one, and returns how many it processed.
+func (g *Geocoder) GeocodePending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := g.geocodeBatch(ctx)
+		total += n
+		if err != nil || n < g.batchSize {
+			return total, err
+		}
+	}
+}
+
+type pendingEvent struct {
+	id                   int64
+	city, eventType      string
+	texts                []string
+	updatedAt, cleanedAt sql.NullTime
+}
+
+func (g *Geocoder) geocodeBatch(ctx context.Context) (int, error) {
+	rows, err := g.db.QueryContext(ctx, `
+		SELECT e.id, COALESCE(e.city_normalized, ''), COALESCE(e.event_type, ''),
+		       COALESCE(ec.location_clean, ''), COALESCE(ec.address_clean, ''),
+		       COALESCE(e.location, ''), COALESCE(e.address, ''),
+		       e.updated_at, ec.cleaned_at
+		FROM events e
+		LEFT JOIN event_cleaned ec ON ec.event_id = e.id
+		LEFT JOIN event_geo g ON g.event_id = e.id
+		WHERE g.event_id IS NULL
+		   OR g.event_updated_at IS DISTINCT FROM e.updated_at
+		   OR g.cleaned_at IS DISTINCT FROM ec.cleaned_at
+		ORDER BY e.id
+		LIMIT $1
+	`, g.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	var pending []pendingEvent
+	for rows.Next() {
+		var p pendingEvent
+		texts := make([]string, 4)
+		if err := rows.Scan(&p.id, &p.city, &p.eventType, &texts[0], &texts[1], &texts[2], &texts[3],
+			&p.updatedAt, &p.cleanedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		p.texts = texts
+		pending = append(pending, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	gz := gazetteer.Current()
+	for _, p := range pending {
+		var lat, lng sql.NullFloat64
+		var confidence, matched string
+		if !strings.EqualFold(p.eventType, "online") && p.city != "Online" {
+			if m, ok := gz.Geocode(p.city, p.texts...); ok {
+				lat = sql.NullFloat64{Float64: m.Lat, Valid: true}
+				lng = sql.NullFloat64{Float64: m.Lng, Valid: true}
+				confidence, matched = string(m.Confidence), m.Matched
+			}
+		}
+		if _, err := g.db.ExecContext(ctx, `
+			INSERT INTO event_geo (event_id, lat, lng, confidence, matched, event_updated_at, cleaned_at, geocoded_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, now())
+			ON CONFLICT (event_id) DO UPDATE SET
+				lat = EXCLUDED.lat, lng = EXCLUDED.lng,
+				confidence = EXCLUDED.confidence, matched = EXCLUDED.matched,
+				event_updated_at = EXCLUDED.event_updated_at,
+				cleaned_at = EXCLUDED.cleaned_at,
+				geocoded_at = now()
+		`, p.id, lat, lng, confidence, matched, p.updatedAt, p.cleanedAt); err != nil {
+			return 0, err
+		}
+	}
+	return len(pending), nil
+}
@@ -0,0 +1,157 @@
+// Package interests stores what users say they care about: technologies,
+// cities, event formats and whether they want free or paid events. The
+// "For you" feed ranks events by them.
+package interests
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Formats are the event types a user can follow.
+var Formats = []string{"online", "offline", "hybrid"}
+
+// Price preferences; "" means either.
+const (
+	PriceFree = "free"
+	PricePaid = "paid"
+)
+
+// MaxValues bounds each list, and MaxLen each value in it.
+const (
+	MaxValues = 30
+	MaxLen    = 60
+)
+
+// Interests are a user's declared interests. Values are matched
+// case-insensitively.
+type Interests struct {
+	Tech      []string   `json:"tech"`
+	Cities    []string   `json:"cities"`
+	Formats   []string   `json:"formats"`
+	Price     string     `json:"price"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// Empty reports whether nothing is declared.
+func (in *Interests) Empty() bool {
+	return len(in.Tech) == 0 && len(in.Cities) == 0 && len(in.Formats) == 0 && in.Price == ""
+}
+
+// Validate trims the values and drops duplicates. Formats and the price
+// are lower-cased.
+func (in *Interests) Validate() error {
+	var err error
+	if in.Tech, err = clean("tech", in.Tech); err != nil {
+		return err
+	}
+	if in.Cities, err = clean("cities", in.Cities); err != nil {
+		return err
+	}
+	if in.Formats, err = clean("formats", in.Formats); err != nil {
+		return err
+	}
+	for i, f := range in.Formats {
+		in.Formats[i] = strings.ToLower(f)
+		if !contains(Formats, in.Formats[i]) {
+			return fmt.Errorf("unknown format %q: use %s", f, strings.Join(Formats, ", "))
+		}
+	}
+	in.Price = strings.ToLower(strings.TrimSpace(in.Price))
+	if in.Price != "" && in.Price != PriceFree && in.Price != PricePaid {
+		return errors.New(`price must be "free", "paid" or empty for either`)
+	}
+	return nil
+}
+
+func clean(name string, values []string) ([]string, error) {
+	if len(values) > MaxValues {
+		return nil, fmt.Errorf("%s can hold at most %d values", name, MaxValues)
+	}
+	seen := map[string]bool{}
+	out := []string{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		if len(v) > MaxLen {
+			return nil, fmt.Errorf("%s values are at most %d characters", name, MaxLen)
+		}
+		seen[strings.ToLower(v)] = true
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Lower returns values lower-cased, for matching.
+func Lower(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
+
+// Schema holds the DDL for user interests.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS user_interests (
+		user_id    UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		tech       TEXT[] NOT NULL DEFAULT '{}',
+		cities     TEXT[] NOT NULL DEFAULT '{}',
+		formats    TEXT[] NOT NULL DEFAULT '{}',
+		price      VARCHAR(10) NOT NULL DEFAULT '',
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+}
+
+// EnsureSchema creates the user_interests table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("interests migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Get returns a user's interests, empty when none were saved.
+func Get(db *sql.DB, userID string) (Interests, error) {
+	in := Interests{Tech: []string{}, Cities: []string{}, Formats: []string{}}
+	err := db.QueryRow(`
+		SELECT tech, cities, formats, price, updated_at FROM user_interests WHERE user_id = $1
+	`, userID).Scan(pq.Array(&in.Tech), pq.Array(&in.Cities), pq.Array(&in.Formats), &in.Price, &in.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return in, nil
+	}
+	return in, err
+}
+
+// Save stores a user's interests, which must be valid.
+func Save(db *sql.DB, userID string, in Interests) error {
+	_, err := db.Exec(`
+		INSERT INTO user_interests (user_id, tech, cities, formats, price, updated_at)
+		VALUES ($1, $2, $3, $4, $5, now())
+		ON CONFLICT (user_id) DO UPDATE SET
+			tech       = EXCLUDED.tech,
+			cities     = EXCLUDED.cities,
+			formats    = EXCLUDED.formats,
+			price      = EXCLUDED.price,
+			updated_at = now()
+	`, userID, pq.Array(in.Tech), pq.Array(in.Cities), pq.Array(in.Formats), in.Price)
+	return err
+}
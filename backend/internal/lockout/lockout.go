@@ -0,0 +1,124 @@
+// Package lockout slows down password guessing against one account.
+//
+// Failed sign-ins are counted per email address, whether or not an
+// account exists, so lockouts do not reveal which addresses are
+// registered. After FreeAttempts failures each further failure locks the
+// address for twice as long as the one before, up to MaxDelay. Failures
+// are forgotten after Window without one, and on a successful sign-in or
+// password reset.
+package lockout
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	FreeAttempts = 5
+	BaseDelay    = 30 * time.Second
+	MaxDelay     = time.Hour
+	Window       = 24 * time.Hour
+)
+
+// Schema holds the DDL for failed sign-in counters.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS signin_failures (
+		email          VARCHAR(255) PRIMARY KEY,
+		failures       INT NOT NULL,
+		last_failed_at TIMESTAMPTZ NOT NULL,
+		locked_until   TIMESTAMPTZ
+	)`,
+}
+
+// EnsureSchema creates the signin_failures table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("lockout migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Delay is how long an address is locked after failures failed sign-ins.
+func Delay(failures int) time.Duration {
+	if failures < FreeAttempts {
+		return 0
+	}
+	d := BaseDelay
+	for i := FreeAttempts; i < failures && d < MaxDelay; i++ {
+		d *= 2
+	}
+	if d > MaxDelay {
+		d = MaxDelay
+	}
+	return d
+}
+
+// Locked returns how much longer email is locked, or 0.
+func Locked(db *sql.DB, email string) (time.Duration, error) {
+	var until time.Time
+	err := db.QueryRow(`
+		SELECT locked_until FROM signin_failures
+		WHERE email = $1 AND locked_until > now()
+	`, email).Scan(&until)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return time.Until(until), nil
+}
+
+// Fail records a failed sign-in for email and returns how long it is now
+// locked, or 0.
+func Fail(db *sql.DB, email string) (time.Duration, error) {
+	var failures int
+	err := db.QueryRow(`
+		INSERT INTO signin_failures (email, failures, last_failed_at)
+		VALUES ($1, 1, now())
+		ON CONFLICT (email) DO UPDATE SET
+			failures = CASE
+				WHEN signin_failures.last_failed_at < now() - make_interval(secs => $2) THEN 1
+				ELSE signin_failures.failures + 1
+			END,
+			last_failed_at = now()
+		RETURNING failures
+	`, email, Window.Seconds()).Scan(&failures)
+	if err != nil {
+		return 0, err
+	}
+	d := Delay(failures)
+	if d > 0 {
+		if _, err := db.Exec(`
+			UPDATE signin_failures SET locked_until = now() + make_interval(secs => $2)
+			WHERE email = $1
+		`, email, d.Seconds()); err != nil {
+			return 0, err
+		}
+	}
+	return d, nil
+}
+
+// Clear forgets the failed sign-ins of email.
+func Clear(db *sql.DB, email string) error {
+	_, err := db.Exec(`DELETE FROM signin_failures WHERE email = $1`, email)
+	return err
+}
+
+// Purge deletes counters that have expired, returning how many.
+func Purge(db *sql.DB) (int, error) {
+	res, err := db.Exec(`
+		DELETE FROM signin_failures
+		WHERE last_failed_at < now() - make_interval(secs => $1)
+		  AND (locked_until IS NULL OR locked_until < now())
+	`, Window.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
@@ -93,4 +93,18 @@ func (e *Event) Normalize() {
 // IsValid checks if the event has required fields
 func (e *Event) IsValid() bool {
 	return e.EventName != "" && e.Platform != ""
-}
\ No newline at end of file
+}
+
+// Fingerprint returns a stable hash over the user-visible fields of the event.
+// Unlike Hash it changes whenever the content of an existing event changes,
+// which is what change notifications (webhooks, live updates) care about.
+func (e *Event) Fingerprint() string {
+	key := strings.Join([]string{
+		e.EventName, e.Location, e.CityNormalized,
+		e.DateTime, e.Date, e.Time,
+		e.Website, e.Description, e.Address,
+		e.EventType, e.Platform,
+	}, "\x1f")
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,13 @@
+package models
+
+// Change kinds reported for events touched by a scraping cycle.
+const (
+	EventCreated = "event.created"
+	EventUpdated = "event.updated"
+)
+
+// EventChange describes an event that a cycle inserted or whose content changed.
+type EventChange struct {
+	Kind  string `json:"type"`
+	Event Event  `json:"event"`
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Mail is a plain-text email.
+type Mail struct {
+	To      string
+	Subject string
+	Text    string
+}
+
+// Mailer sends mail. Implementations are chosen with MAIL_DRIVER.
+type Mailer interface {
+	Send(ctx context.Context, m Mail) error
+}
+
+// NewMailerFromEnv builds the mailer selected by MAIL_DRIVER:
+//
+//	log  (default) — print the message to the server log
+//	file           — write each message to MAIL_DIR (default "tmp/mail") as a .eml file
+//	smtp           — send through SMTP_HOST:SMTP_PORT with optional SMTP_USER/SMTP_PASSWORD
+//
+// MAIL_FROM sets the sender address for file and smtp.
+func NewMailerFromEnv() Mailer {
+	from := getEnv("MAIL_FROM", "Event Scraper <no-reply@localhost>")
+	switch strings.ToLower(getEnv("MAIL_DRIVER", "log")) {
+	case "file":
+		return &FileMailer{Dir: getEnv("MAIL_DIR", filepath.Join("tmp", "mail")), From: from}
+	case "smtp":
+		return &SMTPMailer{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USER"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}
+	default:
+		return LogMailer{}
+	}
+}
+
+// LogMailer prints mail to the log instead of sending it. Meant for development.
+type LogMailer struct{}
+
+func (LogMailer) Send(_ context.Context, m Mail) error {
+	log.Printf("📧 [mail] to=%s subject=%q\n%s", m.To, m.Subject, m.Text)
+	return nil
+}
+
+// FileMailer writes every message to its own .eml file in Dir.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (f *FileMailer) Send(_ context.Context, m Mail) error {
+	if err := os.MkdirAll(f.Dir, 0755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s.eml",
+		time.Now().UTC().Format("20060102T150405.000000000"),
+		unsafeFileChars.ReplaceAllString(m.To, "_"),
+	)
+	return os.WriteFile(filepath.Join(f.Dir, name), compose(f.From, m), 0644)
+}
+
+// SMTPMailer sends mail through an SMTP relay using PLAIN auth when a username
+// is configured.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPMailer) Send(_ context.Context, m Mail) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	return smtp.SendMail(s.Host+":"+s.Port, auth, addressOf(s.From), []string{m.To}, compose(s.From, m))
+}
+
+func compose(from string, m Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(m.Text, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// addressOf extracts "a@b" from "Name <a@b>".
+func addressOf(from string) string {
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,245 @@
+// Package notify sends user-facing notifications (reminders, account mail)
+// through a set of pluggable channels: email, in-app and webhook.
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-scraper/internal/webhooks"
+)
+
+// Channel names as stored in user preferences.
+const (
+	ChannelEmail   = "email"
+	ChannelInApp   = "in_app"
+	ChannelWebhook = "webhook"
+)
+
+// Message is one notification addressed to a user.
+type Message struct {
+	UserID  string
+	Email   string
+	Name    string
+	Kind    string // e.g. "reminder.due"
+	Subject string
+	Body    string
+	EventID int64
+	// Data is attached verbatim to in-app notifications and webhook payloads.
+	Data map[string]interface{}
+}
+
+// Channel delivers a Message over one medium.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// Notifier fans a message out to the channels a user has enabled.
+type Notifier struct {
+	channels map[string]Channel
+}
+
+func NewNotifier(channels ...Channel) *Notifier {
+	n := &Notifier{channels: make(map[string]Channel)}
+	for _, c := range channels {
+		n.channels[c.Name()] = c
+	}
+	return n
+}
+
+// Send delivers msg on every named channel. It succeeds if at least one
+// channel accepted the message; otherwise the joined errors are returned.
+func (n *Notifier) Send(ctx context.Context, msg Message, channels []string) error {
+	var errs []error
+	delivered := 0
+	for _, name := range channels {
+		c, ok := n.channels[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: channel not configured", name))
+			continue
+		}
+		if err := c.Send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		delivered++
+	}
+	if delivered == 0 && len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// ValidChannel reports whether name is a known channel.
+func ValidChannel(name string) bool {
+	return name == ChannelEmail || name == ChannelInApp || name == ChannelWebhook
+}
+
+// ─── Email ────────────────────────────────────────────────────────────────────
+
+type EmailChannel struct {
+	mailer Mailer
+}
+
+func NewEmailChannel(m Mailer) *EmailChannel {
+	return &EmailChannel{mailer: m}
+}
+
+func (c *EmailChannel) Name() string { return ChannelEmail }
+
+func (c *EmailChannel) Send(ctx context.Context, msg Message) error {
+	if strings.TrimSpace(msg.Email) == "" {
+		return fmt.Errorf("user has no email address")
+	}
+	return c.mailer.Send(ctx, Mail{To: msg.Email, Subject: msg.Subject, Text: msg.Body})
+}
+
+// ─── In-app ───────────────────────────────────────────────────────────────────
+
+// Schema holds the DDL for in-app notifications.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS notifications (
+		id         BIGSERIAL PRIMARY KEY,
+		user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		kind       VARCHAR(50) NOT NULL,
+		title      TEXT NOT NULL,
+		body       TEXT NOT NULL DEFAULT '',
+		event_id   INTEGER REFERENCES events(id) ON DELETE SET NULL,
+		data       JSONB NOT NULL DEFAULT '{}',
+		read_at    TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at DESC)`,
+}
+
+// EnsureSchema creates the notifications table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("notifications migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+type InAppChannel struct {
+	db *sql.DB
+}
+
+func NewInAppChannel(db *sql.DB) *InAppChannel {
+	return &InAppChannel{db: db}
+}
+
+func (c *InAppChannel) Name() string { return ChannelInApp }
+
+func (c *InAppChannel) Send(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+	_, err = c.db.ExecContext(ctx, `
+		INSERT INTO notifications (user_id, kind, title, body, event_id, data)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0), $6)
+	`, msg.UserID, msg.Kind, msg.Subject, msg.Body, msg.EventID, data)
+	return err
+}
+
+// Notification is one row of a user's in-app inbox.
+type Notification struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	EventID   int64           `json:"event_id,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ListNotifications returns a user's most recent in-app notifications.
+func ListNotifications(db *sql.DB, userID string, unreadOnly bool, limit int) ([]Notification, error) {
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	rows, err := db.Query(`
+		SELECT id, kind, title, body, COALESCE(event_id, 0), data, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Notification{}
+	for rows.Next() {
+		var n Notification
+		var data []byte
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.Kind, &n.Title, &n.Body, &n.EventID, &data, &readAt, &n.CreatedAt); err != nil {
+			continue
+		}
+		n.Data = data
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+// MarkRead marks one notification (or all of them when id is 0) as read and
+// returns how many rows changed.
+func MarkRead(db *sql.DB, userID string, id int64) (int64, error) {
+	res, err := db.Exec(`
+		UPDATE notifications SET read_at = now()
+		WHERE user_id = $1 AND read_at IS NULL AND ($2 = 0 OR id = $2)
+	`, userID, id)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// ─── Webhook ──────────────────────────────────────────────────────────────────
+
+// WebhookChannel hands the message to the user's own webhooks, reusing their
+// signing secret, retry schedule and delivery log.
+type WebhookChannel struct {
+	db *sql.DB
+}
+
+func NewWebhookChannel(db *sql.DB) *WebhookChannel {
+	return &WebhookChannel{db: db}
+}
+
+func (c *WebhookChannel) Name() string { return ChannelWebhook }
+
+func (c *WebhookChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"type":        msg.Kind,
+		"occurred_at": time.Now().UTC(),
+		"title":       msg.Subject,
+		"body":        msg.Body,
+		"data":        msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+	n, err := webhooks.EnqueueForUser(c.db, msg.UserID, msg.Kind, msg.EventID, body)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("no active webhook subscribed to %s", msg.Kind)
+	}
+	return nil
+}
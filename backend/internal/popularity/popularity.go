@@ -0,0 +1,232 @@
+// Package popularity scores events by how much interest they drew lately:
+// detail views, outbound clicks (to the event's website or registration
+// page) and saves.
+//
+// Views and clicks are recorded in event_interactions, at most once per
+// visitor, event and kind a day. A visitor is a keyed hash of the user, or of
+// the client address and user agent, that changes every day, so no address
+// is stored and visitors cannot be followed from one day to the next. Saves
+// come straight from saved_events. A Builder periodically turns the last
+// Window of signals into one score per event: each signal counts its weight,
+// halved every HalfLife since it happened.
+package popularity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Interaction kinds recorded in event_interactions.
+const (
+	KindView  = "view"
+	KindClick = "click"
+)
+
+// Weights per signal: an outbound click or a save says more than a view.
+const (
+	WeightView  = 1
+	WeightClick = 3
+	WeightSave  = 5
+)
+
+const (
+	// HalfLife is how long it takes a signal to lose half its weight.
+	HalfLife = 72 * time.Hour
+	// Window is how far back signals count; older interactions are deleted.
+	Window = 30 * 24 * time.Hour
+)
+
+// Schema holds the DDL for interactions and the scores built from them.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS event_interactions (
+		event_id   INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		kind       VARCHAR(10) NOT NULL CHECK (kind IN ('view', 'click')),
+		visitor    CHAR(32) NOT NULL,
+		day        DATE NOT NULL DEFAULT CURRENT_DATE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (event_id, kind, visitor, day)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_event_interactions_created ON event_interactions(created_at)`,
+	`CREATE TABLE IF NOT EXISTS event_popularity (
+		event_id   INTEGER PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+		score      DOUBLE PRECISION NOT NULL,
+		views      INTEGER NOT NULL,
+		clicks     INTEGER NOT NULL,
+		saves      INTEGER NOT NULL,
+		built_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_event_popularity_score ON event_popularity(score DESC)`,
+}
+
+// EnsureSchema creates the popularity tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("popularity migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// botPattern matches the user agents of crawlers, link previewers, uptime
+// checkers and HTTP libraries.
+var botPattern = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|preview|fetch|monitor|headless|` +
+	`lighthouse|facebookexternalhit|embedly|curl|wget|python|java/|go-http-client|okhttp|axios|node-fetch|libwww|scrapy`)
+
+// IsBot reports whether a request with this user agent should not count.
+// Browsers always send one, so an empty user agent is a bot too.
+func IsBot(userAgent string) bool {
+	return strings.TrimSpace(userAgent) == "" || botPattern.MatchString(userAgent)
+}
+
+// Visitor identifies whoever made a request, for one day. Signed-in users
+// are identified by userID; others by their address and user agent. The
+// result is an HMAC under key, so it cannot be traced back without the key,
+// and it changes every day.
+func Visitor(key []byte, userID, ip, userAgent string, day time.Time) string {
+	who := "ip:" + ip + "|" + userAgent
+	if userID != "" {
+		who = "user:" + userID
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(day.Format("2006-01-02") + "|" + who))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// Record counts one interaction with eventID on day, the day visitor was
+// hashed with. Repeats by the same visitor on the same day are ignored.
+func Record(db *sql.DB, eventID int64, kind, visitor string, day time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO event_interactions (event_id, kind, visitor, day)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`, eventID, kind, visitor, day.Format("2006-01-02"))
+	return err
+}
+
+// Score is the popularity of one event, with the signals of the last Window
+// behind it.
+type Score struct {
+	EventID int64   `json:"event_id"`
+	Score   float64 `json:"score"`
+	Views   int     `json:"views"`
+	Clicks  int     `json:"clicks"`
+	Saves   int     `json:"saves"`
+}
+
+// Trending returns the most popular upcoming and undated events, in city
+// (any case) unless it is empty, most popular first.
+func Trending(db *sql.DB, city string, limit int) ([]Score, error) {
+	rows, err := db.Query(`
+		SELECT p.event_id, p.score, p.views, p.clicks, p.saves
+		FROM event_popularity p
+		JOIN events e ON e.id = p.event_id
+		WHERE CASE WHEN e.date ~ '^\d{4}-\d{2}-\d{2}$'
+		           THEN e.date::date >= CURRENT_DATE ELSE true END
+		  AND ($1 = '' OR lower(e.city_normalized) = lower($1))
+		ORDER BY p.score DESC, p.event_id
+		LIMIT $2
+	`, strings.TrimSpace(city), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Score{}
+	for rows.Next() {
+		var sc Score
+		if err := rows.Scan(&sc.EventID, &sc.Score, &sc.Views, &sc.Clicks, &sc.Saves); err != nil {
+			return nil, err
+		}
+		out = append(out, sc)
+	}
+	return out, rows.Err()
+}
+
+// lockKey serializes rebuilds across servers ("popular" in ASCII).
+const lockKey = 0x706f70756c6172
+
+// Builder rebuilds event_popularity from the recent signals.
+type Builder struct {
+	db       *sql.DB
+	interval time.Duration
+}
+
+func NewBuilder(db *sql.DB) *Builder {
+	return &Builder{db: db, interval: 15 * time.Minute}
+}
+
+// Run rebuilds the scores every interval until ctx is cancelled.
+func (b *Builder) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		if n, err := b.Rebuild(ctx); err != nil {
+			log.Printf("⚠️  Rebuilding popularity scores failed: %v", err)
+		} else {
+			log.Printf("📈 Rebuilt popularity scores for %d events", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebuild drops interactions older than Window and replaces every score in
+// one transaction, returning how many events have one. Servers sharing a
+// database take turns through an advisory lock.
+func (b *Builder) Rebuild(ctx context.Context) (int, error) {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, lockKey); err != nil {
+		return 0, err
+	}
+	window := Window.Seconds()
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM event_interactions WHERE created_at < now() - make_interval(secs => $1)
+	`, window); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM event_popularity`); err != nil {
+		return 0, err
+	}
+
+	// Saves of events the user then skipped do not count.
+	res, err := tx.ExecContext(ctx, `
+		WITH signals AS (
+			SELECT event_id, kind, created_at AS at FROM event_interactions
+			UNION ALL
+			SELECT event_id, 'save', saved_at::timestamptz FROM saved_events
+			WHERE status <> 'skipped' AND saved_at::timestamptz >= now() - make_interval(secs => $1)
+		)
+		INSERT INTO event_popularity (event_id, score, views, clicks, saves)
+		SELECT event_id,
+		       SUM(CASE kind WHEN 'view' THEN $3::float8 WHEN 'click' THEN $4::float8 ELSE $5::float8 END
+		           * power(0.5, GREATEST(extract(epoch FROM now() - at)::float8, 0) / $2::float8)),
+		       COUNT(*) FILTER (WHERE kind = 'view'),
+		       COUNT(*) FILTER (WHERE kind = 'click'),
+		       COUNT(*) FILTER (WHERE kind = 'save')
+		FROM signals
+		GROUP BY event_id
+	`, window, HalfLife.Seconds(), WeightView, WeightClick, WeightSave)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+
+	return int(n), tx.Commit()
+}
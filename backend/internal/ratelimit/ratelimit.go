@@ -0,0 +1,124 @@
+// Package ratelimit keeps a token bucket per caller.
+//
+// A rule such as "300/m" lets a caller make 300 requests at once and then
+// refills their bucket at 300 a minute. Buckets are created on first use
+// and dropped once they have refilled, so idle callers cost nothing.
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Rule is the size of every bucket and how fast it refills.
+type Rule struct {
+	// Every is the time one token takes to refill.
+	Every time.Duration
+	Burst int
+}
+
+// ParseRule reads "N/s", "N/m" or "N/h", optionally followed by ",B" for a
+// burst other than N. "off" and "" mean no limit (a zero Rule).
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "off" {
+		return Rule{}, nil
+	}
+	spec, burst, hasBurst := strings.Cut(s, ",")
+	count, unit, ok := strings.Cut(spec, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if !ok || err != nil || n < 1 {
+		return Rule{}, fmt.Errorf("rate limit %q: want N/s, N/m or N/h", s)
+	}
+	per := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}[strings.TrimSpace(unit)]
+	if per == 0 {
+		return Rule{}, fmt.Errorf("rate limit %q: unit must be s, m or h", s)
+	}
+	r := Rule{Every: per / time.Duration(n), Burst: n}
+	if hasBurst {
+		if r.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil || r.Burst < 1 {
+			return Rule{}, fmt.Errorf("rate limit %q: burst must be a positive number", s)
+		}
+	}
+	return r, nil
+}
+
+func (r Rule) String() string {
+	if r.Burst == 0 {
+		return "off"
+	}
+	return fmt.Sprintf("bursts of %d, refilling one every %s", r.Burst, r.Every)
+}
+
+// Limiter holds a bucket per key. A nil Limiter allows everything.
+type Limiter struct {
+	limit rate.Limit
+	// full is how long an emptied bucket takes to refill.
+	full  time.Duration
+	burst int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	lim  *rate.Limiter
+	seen time.Time
+}
+
+// New returns a limiter for rule, or nil when the rule is off.
+func New(rule Rule) *Limiter {
+	if rule.Burst == 0 {
+		return nil
+	}
+	return &Limiter{
+		limit:   rate.Every(rule.Every),
+		full:    rule.Every * time.Duration(rule.Burst),
+		burst:   rule.Burst,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow takes a token from the bucket of key. When the bucket is empty it
+// returns false and how long until a token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.full {
+		l.sweep(now)
+	}
+	b := l.buckets[key]
+	if b == nil {
+		b = &bucket{lim: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.seen = now
+
+	res := b.lim.ReserveN(now, 1)
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// sweep drops buckets that have refilled: they behave like new ones.
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.seen) >= l.full {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,249 @@
+package reminders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"event-scraper/internal/notify"
+	"event-scraper/internal/webhooks"
+)
+
+// Dispatcher sends due reminders through the notification channels each user
+// has enabled. Rows are claimed with the same lease-on-next_attempt_at scheme
+// the webhook dispatcher uses.
+type Dispatcher struct {
+	db           *sql.DB
+	notifier     *notify.Notifier
+	pollInterval time.Duration
+	lease        time.Duration
+	batchSize    int
+	maxAttempts  int
+	retryDelay   time.Duration
+}
+
+func NewDispatcher(db *sql.DB, notifier *notify.Notifier) *Dispatcher {
+	return &Dispatcher{
+		db:           db,
+		notifier:     notifier,
+		pollInterval: time.Minute,
+		lease:        5 * time.Minute,
+		batchSize:    50,
+		maxAttempts:  3,
+		retryDelay:   5 * time.Minute,
+	}
+}
+
+// Run polls for due reminders until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := d.DispatchDue(ctx); err != nil {
+			log.Printf("⚠️  Reminder dispatch failed: %v", err)
+		} else if n > 0 {
+			log.Printf("⏰ Dispatched %d reminders", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type dueReminder struct {
+	id            int64
+	savedEventID  int64
+	userID        string
+	eventID       int64
+	offsetMinutes int64
+	eventStart    time.Time
+	attempts      int
+}
+
+// DispatchDue sends one batch of due reminders and returns how many it handled.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	rows, err := d.db.QueryContext(ctx, `
+		UPDATE reminders
+		SET next_attempt_at = now() + $1 * interval '1 second'
+		WHERE id IN (
+			SELECT id FROM reminders
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, saved_event_id, user_id::text, event_id, offset_minutes, event_start, attempts
+	`, int(d.lease.Seconds()), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var batch []dueReminder
+	for rows.Next() {
+		var r dueReminder
+		if err := rows.Scan(&r.id, &r.savedEventID, &r.userID, &r.eventID,
+			&r.offsetMinutes, &r.eventStart, &r.attempts); err != nil {
+			continue
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, r := range batch {
+		d.dispatch(ctx, r)
+	}
+	return len(batch), nil
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, r dueReminder) {
+	// The event may have moved since this row was scheduled and the cycle
+	// that noticed has not rescheduled it yet — recompute instead of sending.
+	start, ok, err := EventStart(d.db, r.eventID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		d.finish(r.id, StatusSkipped, r.attempts, "event no longer exists")
+		return
+	case err != nil:
+		// Nothing is sent without knowing when the event starts; the lease
+		// expires and the next poll tries again.
+		log.Printf("⚠️  Failed to load start of event %d for reminder %d: %v", r.eventID, r.id, err)
+		return
+	case !ok:
+		d.finish(r.id, StatusSkipped, r.attempts, "event no longer has a start time")
+		return
+	case !start.Equal(r.eventStart):
+		// Rescheduling re-arms or removes this row; a failure leaves the
+		// lease to expire so the next poll tries again.
+		if err := ScheduleSavedEvent(d.db, r.savedEventID); err != nil {
+			log.Printf("⚠️  Failed to reschedule reminder %d: %v", r.id, err)
+		}
+		return
+	}
+	if time.Now().After(r.eventStart) {
+		d.finish(r.id, StatusSkipped, r.attempts, "event already started")
+		return
+	}
+
+	msg, channels, err := d.buildMessage(r)
+	if err != nil {
+		d.retry(r, err)
+		return
+	}
+	if len(channels) == 0 {
+		d.finish(r.id, StatusSkipped, r.attempts, "no notification channels enabled")
+		return
+	}
+
+	if err := d.notifier.Send(ctx, msg, channels); err != nil {
+		d.retry(r, err)
+		return
+	}
+	d.finish(r.id, StatusSent, r.attempts+1, "")
+}
+
+func (d *Dispatcher) buildMessage(r dueReminder) (notify.Message, []string, error) {
+	var email, name, title, location, city string
+	err := d.db.QueryRow(`
+		SELECT u.email, u.full_name,
+		       COALESCE(NULLIF(ec.title_clean, ''), e.event_name),
+		       COALESCE(e.location, ''), COALESCE(e.city_normalized, '')
+		FROM users u, events e
+		LEFT JOIN event_cleaned ec ON ec.event_id = e.id
+		WHERE u.id::text = $1 AND e.id = $2
+	`, r.userID, r.eventID).Scan(&email, &name, &title, &location, &city)
+	if err != nil {
+		return notify.Message{}, nil, fmt.Errorf("load reminder context: %w", err)
+	}
+
+	prefs, err := GetPreferences(d.db, r.userID)
+	if err != nil {
+		return notify.Message{}, nil, fmt.Errorf("load preferences: %w", err)
+	}
+
+	when := r.eventStart.In(ist).Format("Mon, 02 Jan 2006 at 3:04 PM IST")
+	subject := fmt.Sprintf("Reminder: %s starts in %s", title, humanOffset(r.offsetMinutes))
+	body := fmt.Sprintf("Hi %s,\n\n%s starts %s.\nWhere: %s\n\nYou are receiving this because you saved this event.",
+		name, title, when, strings.TrimSpace(location))
+
+	return notify.Message{
+		UserID:  r.userID,
+		Email:   email,
+		Name:    name,
+		Kind:    webhooks.KindReminderDue,
+		Subject: subject,
+		Body:    body,
+		EventID: r.eventID,
+		Data: map[string]interface{}{
+			"event_id":       r.eventID,
+			"event_name":     title,
+			"city":           city,
+			"starts_at":      r.eventStart,
+			"offset_minutes": r.offsetMinutes,
+		},
+	}, prefs.ReminderChannels, nil
+}
+
+func (d *Dispatcher) retry(r dueReminder, cause error) {
+	attempts := r.attempts + 1
+	if attempts >= d.maxAttempts {
+		d.finish(r.id, StatusFailed, attempts, cause.Error())
+		return
+	}
+	if _, err := d.db.Exec(`
+		UPDATE reminders
+		SET attempts = $1, last_error = $2, next_attempt_at = now() + $3 * interval '1 second'
+		WHERE id = $4
+	`, attempts, cause.Error(), int(d.retryDelay.Seconds()), r.id); err != nil {
+		log.Printf("⚠️  Failed to record reminder %d: %v", r.id, err)
+	}
+}
+
+func (d *Dispatcher) finish(id int64, status string, attempts int, reason string) {
+	sentAt := "NULL"
+	if status == StatusSent {
+		sentAt = "now()"
+	}
+	if _, err := d.db.Exec(`
+		UPDATE reminders
+		SET status = $1, attempts = $2, last_error = $3, sent_at = `+sentAt+`
+		WHERE id = $4 AND status = 'pending'
+	`, status, attempts, reason, id); err != nil {
+		log.Printf("⚠️  Failed to record reminder %d: %v", id, err)
+	}
+}
+
+var ist = func() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Kolkata"); err == nil {
+		return loc
+	}
+	return time.FixedZone("IST", 5*60*60+30*60)
+}()
+
+// humanOffset renders 1440 as "1 day", 120 as "2 hours", 90 as "90 minutes".
+func humanOffset(minutes int64) string {
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case minutes%(24*60) == 0:
+		return plural(minutes/(24*60), "day")
+	case minutes%60 == 0:
+		return plural(minutes/60, "hour")
+	default:
+		return plural(minutes, "minute")
+	}
+}
@@ -0,0 +1,316 @@
+// Package reminders schedules and sends "your saved event starts soon"
+// notifications.
+//
+// Each saved event gets one reminders row per offset (minutes before start).
+// Offsets come from the saved event itself or, when it has none, from the
+// user's default preferences. Rows are recomputed whenever the offsets, the
+// defaults or the event's date/time change, so a rescheduled event gets fresh
+// reminders instead of firing at the old time.
+package reminders
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"event-scraper/internal/notify"
+	"event-scraper/pkg/utils"
+)
+
+// Reminder statuses.
+const (
+	StatusPending = "pending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// MaxOffsetMinutes bounds how far ahead a reminder may be set (30 days).
+const MaxOffsetMinutes = 30 * 24 * 60
+
+// DefaultOffsets are used for users who never saved preferences:
+// one day and two hours before the event.
+var DefaultOffsets = []int64{24 * 60, 2 * 60}
+
+// DefaultChannels are used for users who never saved preferences.
+var DefaultChannels = []string{notify.ChannelInApp}
+
+// Schema holds the DDL for reminders and the preferences that drive them.
+var Schema = []string{
+	`ALTER TABLE saved_events ADD COLUMN IF NOT EXISTS reminder_offsets INTEGER[]`,
+	`CREATE TABLE IF NOT EXISTS user_preferences (
+		user_id           UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		reminder_offsets  INTEGER[] NOT NULL DEFAULT '{1440,120}',
+		reminder_channels TEXT[] NOT NULL DEFAULT '{in_app}',
+		updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE TABLE IF NOT EXISTS reminders (
+		id              BIGSERIAL PRIMARY KEY,
+		saved_event_id  INTEGER NOT NULL REFERENCES saved_events(id) ON DELETE CASCADE,
+		user_id         UUID NOT NULL,
+		event_id        INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		offset_minutes  INTEGER NOT NULL,
+		event_start     TIMESTAMPTZ NOT NULL,
+		remind_at       TIMESTAMPTZ NOT NULL,
+		next_attempt_at TIMESTAMPTZ NOT NULL,
+		status          VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		last_error      TEXT NOT NULL DEFAULT '',
+		sent_at         TIMESTAMPTZ,
+		created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+		UNIQUE(saved_event_id, offset_minutes)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_reminders_due ON reminders(next_attempt_at) WHERE status = 'pending'`,
+	`CREATE INDEX IF NOT EXISTS idx_reminders_event ON reminders(event_id)`,
+}
+
+// EnsureSchema creates the reminder tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("reminders migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// ─── Preferences ──────────────────────────────────────────────────────────────
+
+type Preferences struct {
+	ReminderOffsets  []int64  `json:"reminder_offsets"`
+	ReminderChannels []string `json:"reminder_channels"`
+}
+
+// GetPreferences returns a user's reminder preferences, or the defaults when
+// none were saved.
+func GetPreferences(db *sql.DB, userID string) (Preferences, error) {
+	var p Preferences
+	err := db.QueryRow(`
+		SELECT reminder_offsets, reminder_channels FROM user_preferences WHERE user_id = $1
+	`, userID).Scan(pq.Array(&p.ReminderOffsets), pq.Array(&p.ReminderChannels))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Preferences{ReminderOffsets: DefaultOffsets, ReminderChannels: DefaultChannels}, nil
+	}
+	return p, err
+}
+
+// SavePreferences stores a user's defaults and reschedules every saved event
+// that relies on them.
+func SavePreferences(db *sql.DB, userID string, p Preferences) error {
+	if _, err := db.Exec(`
+		INSERT INTO user_preferences (user_id, reminder_offsets, reminder_channels, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (user_id) DO UPDATE SET
+			reminder_offsets  = EXCLUDED.reminder_offsets,
+			reminder_channels = EXCLUDED.reminder_channels,
+			updated_at        = now()
+	`, userID, pq.Array(p.ReminderOffsets), pq.Array(p.ReminderChannels)); err != nil {
+		return err
+	}
+	return RescheduleUser(db, userID)
+}
+
+// ValidateOffsets checks that every offset is within (0, MaxOffsetMinutes] and
+// drops duplicates.
+func ValidateOffsets(offsets []int64) ([]int64, error) {
+	seen := make(map[int64]bool)
+	out := []int64{}
+	for _, o := range offsets {
+		if o <= 0 || o > MaxOffsetMinutes {
+			return nil, fmt.Errorf("reminder offsets must be between 1 and %d minutes", MaxOffsetMinutes)
+		}
+		if !seen[o] {
+			seen[o] = true
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+// ValidateChannels checks that every channel name is known.
+func ValidateChannels(channels []string) error {
+	for _, c := range channels {
+		if !notify.ValidChannel(c) {
+			return fmt.Errorf("unknown notification channel %q", c)
+		}
+	}
+	return nil
+}
+
+// ─── Scheduling ───────────────────────────────────────────────────────────────
+
+type Reminder struct {
+	ID            int64      `json:"id"`
+	OffsetMinutes int64      `json:"offset_minutes"`
+	EventStart    time.Time  `json:"event_start"`
+	RemindAt      time.Time  `json:"remind_at"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// eventStartSQL resolves the date/time an event starts, preferring the
+// LLM-cleaned values.
+const eventStartSQL = `
+	SELECT COALESCE(NULLIF(ec.date_clean, ''), e.date, ''),
+	       COALESCE(NULLIF(ec.time_clean, ''), e.time, '')
+	FROM events e
+	LEFT JOIN event_cleaned ec ON ec.event_id = e.id
+	WHERE e.id = $1`
+
+// EventStart returns when an event starts, if its date can be parsed.
+func EventStart(db *sql.DB, eventID int64) (time.Time, bool, error) {
+	var date, clock string
+	if err := db.QueryRow(eventStartSQL, eventID).Scan(&date, &clock); err != nil {
+		return time.Time{}, false, err
+	}
+	start, ok := utils.EventStart(date, clock)
+	return start, ok, nil
+}
+
+// ScheduleSavedEvent (re)computes the pending reminders of one saved event.
+func ScheduleSavedEvent(db *sql.DB, savedEventID int64) error {
+	var userID string
+	var eventID int64
+	var own, defaults []int64
+	var hasOwn, skipped bool
+	err := db.QueryRow(`
+		SELECT se.user_id::text, se.event_id,
+		       se.reminder_offsets IS NOT NULL, COALESCE(se.reminder_offsets, '{}'),
+		       COALESCE(up.reminder_offsets, $2), se.status = 'skipped'
+		FROM saved_events se
+		LEFT JOIN user_preferences up ON up.user_id::text = se.user_id::text
+		WHERE se.id = $1
+	`, savedEventID, pq.Array(DefaultOffsets)).Scan(
+		&userID, &eventID, &hasOwn, pq.Array(&own), pq.Array(&defaults), &skipped,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	offsets := defaults
+	if hasOwn {
+		offsets = own
+	}
+	// Nobody needs reminding of an event they decided to skip.
+	if skipped {
+		offsets = nil
+	}
+
+	start, ok, err := EventStart(db, eventID)
+	if err != nil {
+		return err
+	}
+
+	// Keep only offsets that still land in the future.
+	now := time.Now()
+	live := []int64{}
+	if ok {
+		for _, o := range offsets {
+			if start.Add(-time.Duration(o) * time.Minute).After(now) {
+				live = append(live, o)
+			}
+		}
+	}
+
+	if _, err := db.Exec(`
+		DELETE FROM reminders
+		WHERE saved_event_id = $1 AND status = 'pending' AND NOT (offset_minutes = ANY($2))
+	`, savedEventID, pq.Array(live)); err != nil {
+		return err
+	}
+
+	for _, o := range live {
+		remindAt := start.Add(-time.Duration(o) * time.Minute)
+		// A reminder that already went out is only re-armed when the event moved.
+		if _, err := db.Exec(`
+			INSERT INTO reminders (
+				saved_event_id, user_id, event_id, offset_minutes,
+				event_start, remind_at, next_attempt_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $6)
+			ON CONFLICT (saved_event_id, offset_minutes) DO UPDATE SET
+				event_start     = EXCLUDED.event_start,
+				remind_at       = EXCLUDED.remind_at,
+				next_attempt_at = EXCLUDED.remind_at,
+				status          = 'pending',
+				attempts        = 0,
+				last_error      = '',
+				sent_at         = NULL
+			WHERE reminders.status = 'pending'
+			   OR reminders.event_start <> EXCLUDED.event_start
+		`, savedEventID, userID, eventID, o, start, remindAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RescheduleEvent recomputes reminders for every user who saved an event.
+// Called when a cycle reports the event changed.
+func RescheduleEvent(db *sql.DB, eventID int64) error {
+	return rescheduleWhere(db, "event_id = $1", eventID)
+}
+
+// RescheduleUser recomputes reminders for saved events that follow the user's
+// default offsets.
+func RescheduleUser(db *sql.DB, userID string) error {
+	return rescheduleWhere(db, "user_id::text = $1 AND reminder_offsets IS NULL", userID)
+}
+
+func rescheduleWhere(db *sql.DB, where string, arg interface{}) error {
+	rows, err := db.Query(`SELECT id FROM saved_events WHERE `+where, arg)
+	if err != nil {
+		return err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+
+	for _, id := range ids {
+		if err := ScheduleSavedEvent(db, id); err != nil {
+			return fmt.Errorf("saved event %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// ListForSavedEvent returns the reminders of one saved event.
+func ListForSavedEvent(db *sql.DB, savedEventID int64) ([]Reminder, error) {
+	rows, err := db.Query(`
+		SELECT id, offset_minutes, event_start, remind_at, status, attempts, last_error, sent_at
+		FROM reminders
+		WHERE saved_event_id = $1
+		ORDER BY remind_at
+	`, savedEventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Reminder{}
+	for rows.Next() {
+		var r Reminder
+		var sentAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.OffsetMinutes, &r.EventStart, &r.RemindAt,
+			&r.Status, &r.Attempts, &r.LastError, &sentAt); err != nil {
+			continue
+		}
+		if sentAt.Valid {
+			r.SentAt = &sentAt.Time
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,460 @@
+// Package reviews holds post-event ratings (1 to 5) with short reviews, and
+// aggregates them per event and per organizer.
+//
+// Only people who were there may review an event: those who marked it
+// attended, or saved it before it started and did not skip it. Reviews are
+// screened by Rules when written; one that a rule objects to is held as
+// pending until a moderator publishes or hides it. Readers can flag
+// published reviews, and FlagThreshold flags send a review back to pending.
+// Lists and aggregates only ever show published reviews.
+package reviews
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"event-scraper/internal/rsvp"
+)
+
+// Review statuses.
+const (
+	StatusPublished = "published"
+	StatusPending   = "pending"
+	StatusHidden    = "hidden"
+)
+
+const (
+	MaxBodyLen = 2000
+	// FlagThreshold is how many users must flag a review to hold it.
+	FlagThreshold = 3
+	// MinRatings is how many published reviews an organizer needs before
+	// their average counts as a quality signal.
+	MinRatings = 3
+)
+
+var (
+	ErrNotFound    = errors.New("review not found")
+	ErrNotEligible = errors.New("only people who attended the event or saved it before it started can review it")
+	ErrNotHappened = errors.New("the event has not started yet")
+	ErrBadRating   = errors.New("rating must be between 1 and 5")
+	ErrTooLong     = fmt.Errorf("review must be at most %d characters", MaxBodyLen)
+	ErrBadStatus   = errors.New("status must be published or hidden")
+	ErrOwnReview   = errors.New("you cannot flag your own review")
+)
+
+type Review struct {
+	ID         int64  `json:"id"`
+	EventID    int64  `json:"event_id"`
+	UserID     string `json:"-"`
+	AuthorName string `json:"author_name"`
+	Rating     int    `json:"rating"`
+	Body       string `json:"body"`
+	Status     string `json:"status"`
+	// Set for held and hidden reviews, and only shown to their author and
+	// to moderators, as is Flags.
+	ModerationReason string    `json:"moderation_reason,omitempty"`
+	Flags            int       `json:"flags,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Schema holds the DDL for reviews and reader flags.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS reviews (
+		id                BIGSERIAL PRIMARY KEY,
+		event_id          INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		user_id           UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		rating            SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+		body              TEXT NOT NULL DEFAULT '',
+		status            VARCHAR(20) NOT NULL DEFAULT 'published',
+		moderation_reason TEXT NOT NULL DEFAULT '',
+		moderated_by      UUID REFERENCES users(id) ON DELETE SET NULL,
+		moderated_at      TIMESTAMPTZ,
+		created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+		UNIQUE (event_id, user_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_reviews_event ON reviews(event_id, status)`,
+	`CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews(status, updated_at)`,
+	`CREATE TABLE IF NOT EXISTS review_flags (
+		review_id  BIGINT NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+		user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		reason     TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (review_id, user_id)
+	)`,
+}
+
+// EnsureSchema creates the review tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("reviews migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate trims the body and checks the rating and length.
+func (r *Review) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Rating < 1 || r.Rating > 5 {
+		return ErrBadRating
+	}
+	if len([]rune(r.Body)) > MaxBodyLen {
+		return ErrTooLong
+	}
+	return nil
+}
+
+// ─── Screening ────────────────────────────────────────────────────────────────
+
+// Rule screens a review before it is stored. It returns why the review
+// should wait for a moderator, or "" to let it through.
+type Rule func(r *Review) string
+
+// Rules run, in order, on every review written. The first objection holds
+// the review.
+var Rules = []Rule{tooManyLinks, contactDetails}
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)https?://|www\.`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d -]{8,}\d`)
+)
+
+func tooManyLinks(r *Review) string {
+	if len(linkPattern.FindAllString(r.Body, -1)) > 1 {
+		return "contains several links"
+	}
+	return ""
+}
+
+func contactDetails(r *Review) string {
+	if emailPattern.MatchString(r.Body) || phonePattern.MatchString(r.Body) {
+		return "contains contact details"
+	}
+	return ""
+}
+
+// screen returns the status and moderation reason a new review starts with.
+func screen(r *Review) (string, string) {
+	for _, rule := range Rules {
+		if reason := rule(r); reason != "" {
+			return StatusPending, reason
+		}
+	}
+	return StatusPublished, ""
+}
+
+// ─── Writing ──────────────────────────────────────────────────────────────────
+
+// CheckEligible returns why userID may not review eventID, or nil.
+func CheckEligible(db *sql.DB, userID string, eventID int64) error {
+	var status string
+	var savedAt time.Time
+	err := db.QueryRow(`
+		SELECT status, saved_at::timestamptz FROM saved_events WHERE user_id = $1 AND event_id = $2
+	`, userID, eventID).Scan(&status, &savedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotEligible
+	}
+	if err != nil {
+		return err
+	}
+
+	spans, err := rsvp.Spans(db, []int64{eventID})
+	if err != nil {
+		return err
+	}
+	span, ok := spans[eventID]
+	return eligible(status, savedAt, span, ok, time.Now())
+}
+
+// eligible returns why someone whose saved event has status and savedAt may
+// not review an event with span (known reports whether it has one) at now,
+// or nil. Even attendees need a known start in the past: an undated event
+// could otherwise be marked attended and reviewed before it took place.
+func eligible(status string, savedAt time.Time, span rsvp.Span, known bool, now time.Time) error {
+	switch {
+	case !known || status == rsvp.StatusSkipped:
+		return ErrNotEligible
+	case status != rsvp.StatusAttended && !savedAt.Before(span.Start):
+		return ErrNotEligible
+	case span.Start.After(now):
+		return ErrNotHappened
+	}
+	return nil
+}
+
+// heldSQL is true for a stored review that a moderator hid or readers
+// flagged enough ($7 is FlagThreshold): editing it does not publish it.
+const heldSQL = `(reviews.status = 'hidden'
+	OR (SELECT COUNT(*) FROM review_flags f WHERE f.review_id = reviews.id) >= $7)`
+
+// Upsert stores r as its author's review of its event, replacing an earlier
+// one, and reloads it. The review is screened again; an edited review that a
+// moderator hid or readers flagged goes back to pending.
+func Upsert(db *sql.DB, r *Review) error {
+	status, reason := screen(r)
+	var id int64
+	err := db.QueryRow(`
+		INSERT INTO reviews (event_id, user_id, rating, body, status, moderation_reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (event_id, user_id) DO UPDATE SET
+			rating            = EXCLUDED.rating,
+			body              = EXCLUDED.body,
+			status            = CASE WHEN EXCLUDED.status = 'published' AND `+heldSQL+`
+			                         THEN 'pending' ELSE EXCLUDED.status END,
+			moderation_reason = CASE WHEN EXCLUDED.status = 'published' AND `+heldSQL+`
+			                         THEN 'edited after being held' ELSE EXCLUDED.moderation_reason END,
+			updated_at        = now()
+		RETURNING id
+	`, r.EventID, r.UserID, r.Rating, r.Body, status, reason, FlagThreshold).Scan(&id)
+	if err != nil {
+		return err
+	}
+	got, err := Get(db, id)
+	if err != nil {
+		return err
+	}
+	*r = *got
+	return nil
+}
+
+// Delete removes userID's review of eventID and reports whether there was one.
+func Delete(db *sql.DB, userID string, eventID int64) (bool, error) {
+	res, err := db.Exec(`DELETE FROM reviews WHERE user_id = $1 AND event_id = $2`, userID, eventID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Flag records that userID reports a published review, holding it once
+// FlagThreshold users have. Flagging twice counts once.
+func Flag(db *sql.DB, reviewID int64, userID, reason string) error {
+	r, err := Get(db, reviewID)
+	if err != nil {
+		return err
+	}
+	if r.Status != StatusPublished {
+		return ErrNotFound
+	}
+	if r.UserID == userID {
+		return ErrOwnReview
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO review_flags (review_id, user_id, reason) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, reviewID, userID, strings.TrimSpace(reason)); err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		UPDATE reviews SET status = 'pending', moderation_reason = 'flagged by readers'
+		WHERE id = $1 AND status = 'published'
+		  AND (SELECT COUNT(*) FROM review_flags WHERE review_id = $1) >= $2
+	`, reviewID, FlagThreshold)
+	return err
+}
+
+// Moderate publishes or hides a review. Publishing clears its flags, so the
+// same readers cannot hold it again.
+func Moderate(db *sql.DB, reviewID int64, moderatorID, status, reason string) (*Review, error) {
+	if status != StatusPublished && status != StatusHidden {
+		return nil, ErrBadStatus
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE reviews
+		SET status = $2, moderation_reason = $3, moderated_by = $4, moderated_at = now()
+		WHERE id = $1
+	`, reviewID, status, strings.TrimSpace(reason), moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, ErrNotFound
+	}
+	if status == StatusPublished {
+		if _, err := tx.Exec(`DELETE FROM review_flags WHERE review_id = $1`, reviewID); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return Get(db, reviewID)
+}
+
+// ─── Reading ──────────────────────────────────────────────────────────────────
+
+const reviewCols = `
+	r.id, r.event_id, r.user_id::text, COALESCE(u.full_name, ''), r.rating, r.body,
+	r.status, r.moderation_reason,
+	(SELECT COUNT(*) FROM review_flags f WHERE f.review_id = r.id),
+	r.created_at, r.updated_at`
+
+const reviewFrom = `
+	FROM reviews r
+	JOIN users u ON u.id = r.user_id
+	LEFT JOIN event_cleaned ec ON ec.event_id = r.event_id`
+
+func scanReview(row interface{ Scan(...interface{}) error }) (*Review, error) {
+	var r Review
+	err := row.Scan(&r.ID, &r.EventID, &r.UserID, &r.AuthorName, &r.Rating, &r.Body,
+		&r.Status, &r.ModerationReason, &r.Flags, &r.CreatedAt, &r.UpdatedAt)
+	return &r, err
+}
+
+// Get returns a review whatever its status.
+func Get(db *sql.DB, id int64) (*Review, error) {
+	r, err := scanReview(db.QueryRow(`SELECT `+reviewCols+reviewFrom+` WHERE r.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return r, err
+}
+
+// Mine returns userID's review of eventID whatever its status.
+func Mine(db *sql.DB, userID string, eventID int64) (*Review, error) {
+	r, err := scanReview(db.QueryRow(`SELECT `+reviewCols+reviewFrom+`
+		WHERE r.user_id = $1 AND r.event_id = $2`, userID, eventID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return r, err
+}
+
+// Filter selects reviews. Zero fields do not filter; Status defaults to
+// published.
+type Filter struct {
+	EventID   int64
+	Organizer string
+	Rating    int
+	UserID    string
+	Status    string
+}
+
+func (f Filter) where() (string, []interface{}) {
+	status := f.Status
+	if status == "" {
+		status = StatusPublished
+	}
+	conds := []string{"r.status = $1"}
+	args := []interface{}{status}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.EventID > 0 {
+		add("r.event_id = $%d", f.EventID)
+	}
+	if o := strings.TrimSpace(f.Organizer); o != "" {
+		add("lower(btrim(ec.organizer)) = lower($%d)", o)
+	}
+	if f.Rating > 0 {
+		add("r.rating = $%d", f.Rating)
+	}
+	if f.UserID != "" {
+		add("r.user_id = $%d", f.UserID)
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// List returns one page of the reviews matching f, newest first, and how
+// many match in all.
+func List(db *sql.DB, f Filter, limit, offset int) ([]Review, int, error) {
+	where, args := f.where()
+	var total int
+	if err := db.QueryRow(`SELECT COUNT(*)`+reviewFrom+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, limit, offset)
+	rows, err := db.Query(fmt.Sprintf(`SELECT %s%s%s ORDER BY r.updated_at DESC, r.id DESC LIMIT $%d OFFSET $%d`,
+		reviewCols, reviewFrom, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := []Review{}
+	for rows.Next() {
+		r, err := scanReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		out = append(out, *r)
+	}
+	return out, total, rows.Err()
+}
+
+// ByUser returns every review userID wrote, newest first.
+func ByUser(db *sql.DB, userID string) ([]Review, error) {
+	out := []Review{}
+	for _, status := range []string{StatusPublished, StatusPending, StatusHidden} {
+		list, _, err := List(db, Filter{UserID: userID, Status: status}, 1000, 0)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, list...)
+	}
+	return out, nil
+}
+
+// Summary aggregates published ratings. Distribution[i] counts ratings of
+// i+1.
+type Summary struct {
+	Average      float64 `json:"average"`
+	Count        int     `json:"count"`
+	Distribution []int   `json:"distribution"`
+}
+
+// Summarize aggregates the published reviews matching f, ignoring
+// f.Rating and f.Status.
+func Summarize(db *sql.DB, f Filter) (Summary, error) {
+	f.Rating, f.Status = 0, StatusPublished
+	where, args := f.where()
+	s := Summary{Distribution: make([]int, 5)}
+	var counts []int64
+	err := db.QueryRow(`
+		SELECT COALESCE(ROUND(AVG(r.rating), 2), 0)::float8, COUNT(*),
+		       ARRAY[COUNT(*) FILTER (WHERE r.rating = 1), COUNT(*) FILTER (WHERE r.rating = 2),
+		             COUNT(*) FILTER (WHERE r.rating = 3), COUNT(*) FILTER (WHERE r.rating = 4),
+		             COUNT(*) FILTER (WHERE r.rating = 5)]
+	`+reviewFrom+where, args...).Scan(&s.Average, &s.Count, pq.Array(&counts))
+	if err != nil {
+		return s, err
+	}
+	for i, n := range counts {
+		if i < len(s.Distribution) {
+			s.Distribution[i] = int(n)
+		}
+	}
+	return s, nil
+}
+
+// RatedOrganizersSQL selects, per lower-cased organizer with at least
+// MinRatings published reviews, their average rating and review count.
+// Meant for a WITH clause in ranking queries.
+var RatedOrganizersSQL = fmt.Sprintf(`
+	SELECT lower(btrim(ec.organizer)) AS organizer, AVG(r.rating)::float8 AS average, COUNT(*) AS reviews
+	FROM reviews r
+	JOIN event_cleaned ec ON ec.event_id = r.event_id
+	WHERE r.status = 'published' AND btrim(COALESCE(ec.organizer, '')) <> ''
+	GROUP BY 1
+	HAVING COUNT(*) >= %d`, MinRatings)
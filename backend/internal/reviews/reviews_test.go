@@ -0,0 +1,42 @@
+package reviews
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-scraper/internal/rsvp"
+)
+
+func TestEligible(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	past := rsvp.Span{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}
+	future := rsvp.Span{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+	before := now.Add(-24 * time.Hour)
+	after := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		status  string
+		savedAt time.Time
+		span    rsvp.Span
+		known   bool
+		want    error
+	}{
+		{"attended", rsvp.StatusAttended, after, past, true, nil},
+		{"attended undated", rsvp.StatusAttended, before, rsvp.Span{}, false, ErrNotEligible},
+		{"attended not started", rsvp.StatusAttended, before, future, true, ErrNotHappened},
+		{"saved before start", rsvp.StatusGoing, before, past, true, nil},
+		{"saved after start", rsvp.StatusInterested, after, past, true, ErrNotEligible},
+		{"saved undated", rsvp.StatusGoing, before, rsvp.Span{}, false, ErrNotEligible},
+		{"skipped", rsvp.StatusSkipped, before, past, true, ErrNotEligible},
+		{"not started", rsvp.StatusGoing, before, future, true, ErrNotHappened},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := eligible(tt.status, tt.savedAt, tt.span, tt.known, now); !errors.Is(err, tt.want) {
+				t.Errorf("eligible = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Package roles defines what a user may do beyond their own data.
+//
+// Roles are ordered: a moderator can do everything a user can, and an
+// admin everything a moderator can. Every user has exactly one role.
+package roles
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Role is stored in users.role and carried in the "role" claim of access
+// tokens.
+type Role string
+
+const (
+	User      Role = "user"
+	Moderator Role = "moderator"
+	Admin     Role = "admin"
+)
+
+var rank = map[Role]int{User: 0, Moderator: 1, Admin: 2}
+
+var (
+	ErrNotFound  = errors.New("user not found")
+	ErrLastAdmin = errors.New("cannot remove the last admin")
+)
+
+// Parse returns the role called s, or false.
+func Parse(s string) (Role, bool) {
+	r := Role(s)
+	_, ok := rank[r]
+	return r, ok
+}
+
+// Includes reports whether r may do what other may. Unknown roles include
+// nothing.
+func (r Role) Includes(other Role) bool {
+	have, ok := rank[r]
+	return ok && have >= rank[other]
+}
+
+// Schema adds the role column to users.
+var Schema = []string{
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'`,
+}
+
+// EnsureSchema adds the role column if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("roles migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Set gives a user a role. The last admin cannot be demoted, so there is
+// always someone who can hand out roles.
+func Set(db *sql.DB, userID string, role Role) error {
+	if _, ok := rank[role]; !ok {
+		return fmt.Errorf("unknown role %q", role)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, admins, err := lockAdmins(tx, userID)
+	if err != nil {
+		return err
+	}
+	if current == Admin && role != Admin && admins <= 1 {
+		return ErrLastAdmin
+	}
+	if _, err := tx.Exec(
+		`UPDATE users SET role = $2, updated_at = now() WHERE id::text = $1`, userID, role,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteUser deletes a user; everything they own goes with them through
+// ON DELETE CASCADE. Like demoting, deleting the last admin is refused.
+func DeleteUser(db *sql.DB, userID string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, admins, err := lockAdmins(tx, userID)
+	if err != nil {
+		return err
+	}
+	if current == Admin && admins <= 1 {
+		return ErrLastAdmin
+	}
+	if _, err := tx.Exec(`DELETE FROM users WHERE id::text = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockAdmins returns the role of a user and how many admins there are.
+// Locking the admins serializes concurrent demotions and deletions.
+func lockAdmins(tx *sql.Tx, userID string) (current Role, admins int, err error) {
+	var role string
+	if err := tx.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM (SELECT 1 FROM users WHERE role = $2 FOR UPDATE) a),
+		       COALESCE((SELECT role FROM users WHERE id::text = $1), '')
+	`, userID, Admin).Scan(&admins, &role); err != nil {
+		return "", 0, err
+	}
+	if role == "" {
+		return "", 0, ErrNotFound
+	}
+	return Role(role), admins, nil
+}
@@ -0,0 +1,251 @@
+// Package rsvp tracks whether a user is going to the events they saved, and
+// finds the events they are going to that overlap.
+//
+// Every saved event has a status. It starts as interested; going and skipped
+// can be set at any time, attended only once the event has started. Event
+// times come from the (cleaned) date and time columns through
+// utils.EventSpan; events whose date cannot be read have no span, so they
+// never conflict, stay out of the agenda and cannot be marked attended.
+package rsvp
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+
+	"event-scraper/pkg/utils"
+)
+
+// Saved event statuses.
+const (
+	StatusInterested = "interested"
+	StatusGoing      = "going"
+	StatusAttended   = "attended"
+	StatusSkipped    = "skipped"
+)
+
+// Statuses lists every status.
+var Statuses = []string{StatusInterested, StatusGoing, StatusAttended, StatusSkipped}
+
+var (
+	ErrBadStatus  = errors.New("status must be interested, going, attended or skipped")
+	ErrNotStarted = errors.New("an event can only be marked attended once it has started")
+	ErrNoDate     = errors.New("an event without a known date cannot be marked attended")
+)
+
+// Schema holds the DDL for saved event statuses.
+var Schema = []string{
+	`ALTER TABLE saved_events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'interested'
+		CHECK (status IN ('interested', 'going', 'attended', 'skipped'))`,
+	`ALTER TABLE saved_events ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ`,
+	`CREATE INDEX IF NOT EXISTS idx_saved_events_status ON saved_events(user_id, status)`,
+}
+
+// EnsureSchema adds the status columns to saved_events if they are missing.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("rsvp migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Valid reports whether status is one of Statuses.
+func Valid(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns why eventID cannot be given status, or nil.
+func Check(db *sql.DB, eventID int64, status string) error {
+	if !Valid(status) {
+		return ErrBadStatus
+	}
+	if status != StatusAttended {
+		return nil
+	}
+	spans, err := Spans(db, []int64{eventID})
+	if err != nil {
+		return err
+	}
+	span, ok := spans[eventID]
+	return checkAttended(span, ok, time.Now())
+}
+
+// checkAttended returns why an event with span (known reports whether it
+// has one) cannot be marked attended at now, or nil.
+func checkAttended(span Span, known bool, now time.Time) error {
+	if !known {
+		return ErrNoDate
+	}
+	if span.Start.After(now) {
+		return ErrNotStarted
+	}
+	return nil
+}
+
+// Span is when an event takes place.
+type Span struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Overlaps reports whether the two spans share any time. Back-to-back
+// events do not overlap.
+func (s Span) Overlaps(o Span) bool {
+	return s.Start.Before(o.End) && o.Start.Before(s.End)
+}
+
+// Spans returns when each of ids takes place, preferring the LLM-cleaned
+// date and time. Events that do not exist or have no readable date are
+// missing from the result.
+func Spans(db *sql.DB, ids []int64) (map[int64]Span, error) {
+	rows, err := db.Query(`
+		SELECT e.id,
+		       COALESCE(NULLIF(ec.date_clean, ''), e.date, ''),
+		       COALESCE(NULLIF(ec.time_clean, ''), e.time, '')
+		FROM events e
+		LEFT JOIN event_cleaned ec ON ec.event_id = e.id
+		WHERE e.id = ANY($1)
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[int64]Span, len(ids))
+	for rows.Next() {
+		var id int64
+		var date, clock string
+		if err := rows.Scan(&id, &date, &clock); err != nil {
+			return nil, err
+		}
+		if start, end, ok := utils.EventSpan(date, clock); ok {
+			out[id] = Span{start, end}
+		}
+	}
+	return out, rows.Err()
+}
+
+// Conflicts returns the other events userID is going to that overlap
+// eventID, earliest first.
+func Conflicts(db *sql.DB, userID string, eventID int64) ([]int64, error) {
+	going, err := savedWithStatus(db, userID, StatusGoing)
+	if err != nil {
+		return nil, err
+	}
+	spans, err := Spans(db, append(going, eventID))
+	if err != nil {
+		return nil, err
+	}
+	span, ok := spans[eventID]
+	if !ok {
+		return nil, nil
+	}
+
+	var out []int64
+	for _, id := range going {
+		if other, ok := spans[id]; ok && id != eventID && span.Overlaps(other) {
+			out = append(out, id)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return spans[out[i]].Start.Before(spans[out[j]].Start) })
+	return out, nil
+}
+
+// Entry is one saved event on a user's agenda. Conflicts lists the other
+// going events it overlaps; it is only filled in for going events.
+type Entry struct {
+	EventID int64  `json:"event_id"`
+	Status  string `json:"status"`
+	Span
+	Conflicts []int64 `json:"conflicts"`
+}
+
+// Agenda returns the saved events of userID that take place between from
+// and to, earliest first. Skipped events are left out.
+func Agenda(db *sql.DB, userID string, from, to time.Time) ([]Entry, error) {
+	rows, err := db.Query(`
+		SELECT event_id, status FROM saved_events
+		WHERE user_id = $1 AND status <> $2
+	`, userID, StatusSkipped)
+	if err != nil {
+		return nil, err
+	}
+	status := map[int64]string{}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		var s string
+		if err := rows.Scan(&id, &s); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		status[id] = s
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	spans, err := Spans(db, ids)
+	if err != nil {
+		return nil, err
+	}
+	window := Span{from, to}
+	entries := []Entry{}
+	for _, id := range ids {
+		if span, ok := spans[id]; ok && span.Overlaps(window) {
+			entries = append(entries, Entry{EventID: id, Status: status[id], Span: span, Conflicts: []int64{}})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Start.Equal(entries[j].Start) {
+			return entries[i].Start.Before(entries[j].Start)
+		}
+		return entries[i].EventID < entries[j].EventID
+	})
+
+	for i := range entries {
+		if entries[i].Status != StatusGoing {
+			continue
+		}
+		for j := range entries {
+			if i != j && entries[j].Status == StatusGoing && entries[i].Overlaps(entries[j].Span) {
+				entries[i].Conflicts = append(entries[i].Conflicts, entries[j].EventID)
+			}
+		}
+	}
+	return entries, nil
+}
+
+// savedWithStatus returns the events userID saved with the given status.
+func savedWithStatus(db *sql.DB, userID, status string) ([]int64, error) {
+	rows, err := db.Query(`
+		SELECT event_id FROM saved_events WHERE user_id = $1 AND status = $2
+	`, userID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,31 @@
+package rsvp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckAttended(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	past := Span{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}
+	future := Span{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+
+	tests := []struct {
+		name  string
+		span  Span
+		known bool
+		want  error
+	}{
+		{"started", past, true, nil},
+		{"not started", future, true, ErrNotStarted},
+		{"undated", Span{}, false, ErrNoDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAttended(tt.span, tt.known, now); !errors.Is(err, tt.want) {
+				t.Errorf("checkAttended = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
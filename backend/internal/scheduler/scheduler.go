@@ -3,9 +3,13 @@ package scheduler
 import (
 	"context"
 	"event-scraper/internal/ai"
+	"event-scraper/internal/changes"
 	"event-scraper/internal/database"
+	"event-scraper/internal/gazetteer"
+	"event-scraper/internal/geo"
 	"event-scraper/internal/models"
 	"event-scraper/internal/scrapers"
+	"event-scraper/internal/stream"
 	"event-scraper/pkg/utils"
 	"fmt"
 	"os"
@@ -39,6 +43,8 @@ type Scheduler struct {
 
 	cleaner     BatchCleaner
 	llmProvider string
+
+	publisher stream.Publisher
 }
 
 type ScraperStatus struct {
@@ -81,6 +87,23 @@ func New(
 		intervalMinutes: intervalMinutes,
 		cleaner:         cleaner,
 		llmProvider:     provider,
+		publisher:       stream.NewPGNotifier(db.GetConn()),
+	}
+}
+
+// SetPublisher replaces the default Postgres NOTIFY publisher, e.g. with an
+// in-process stream.Broker when the scheduler runs inside the API server.
+func (s *Scheduler) SetPublisher(p stream.Publisher) {
+	s.publisher = p
+}
+
+// publish sends a live update; failures only cost subscribers a message.
+func (s *Scheduler) publish(msg stream.Message) {
+	if s.publisher == nil {
+		return
+	}
+	if err := s.publisher.Publish(msg); err != nil {
+		s.logger.Warn("Failed to publish stream message", zap.String("type", msg.Type), zap.Error(err))
 	}
 }
 
@@ -136,6 +159,7 @@ func (s *Scheduler) runScrapingCycle() {
 	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
 	fmt.Printf("SCRAPING CYCLE #%d STARTED at %s\n", loop, start.Format("2006-01-02 15:04:05"))
 	fmt.Printf("%s\n", strings.Repeat("=", 80))
+	s.publish(stream.CycleStarted(loop))
 
 	// ── Step 1: Run Python scrapers (main_scraper.py handles all scrapers) ──
 	for _, scraper := range s.scrapers {
@@ -151,6 +175,8 @@ func (s *Scheduler) runScrapingCycle() {
 		if status.Success {
 			totalInserted += status.EventsFound
 			totalFiltered += status.Filtered
+		} else {
+			s.publish(stream.ScraperFailed(status.Name, status.Error, status.Duration))
 		}
 	}
 
@@ -159,6 +185,8 @@ func (s *Scheduler) runScrapingCycle() {
 	statuses = append(statuses, hitexStatus)
 	if hitexStatus.Success {
 		totalInserted += hitexStatus.EventsFound
+	} else {
+		s.publish(stream.ScraperFailed(hitexStatus.Name, hitexStatus.Error, hitexStatus.Duration))
 	}
 
 	// ── Step 3: Delete non-tech events ──────────────────────────────────────
@@ -179,7 +207,13 @@ func (s *Scheduler) runScrapingCycle() {
 		fmt.Printf("\n⚠️  LLM cleaning skipped (LLM_PROVIDER=%s)\n", s.llmProvider)
 	}
 
-	// ── Step 6: Log results + total DB count ─────────────────────────────────
+	// ── Step 6: Geocode new / changed events ────────────────────────────────
+	s.geocodeEvents()
+
+	// ── Step 7: Notify about new / changed events ───────────────────────────
+	changed := s.notifyChanges(start)
+
+	// ── Step 8: Log results + total DB count ─────────────────────────────────
 	totalInDB := s.getTotalEventCount()
 
 	fmt.Printf("\n%s\n", strings.Repeat("=", 80))
@@ -214,6 +248,23 @@ func (s *Scheduler) runScrapingCycle() {
 		}
 	}
 	fmt.Printf("%s\n\n", strings.Repeat("=", 80))
+
+	failed := 0
+	for _, st := range statuses {
+		if !st.Success {
+			failed++
+		}
+	}
+	s.publish(stream.CycleFinished(stream.CycleStats{
+		Loop:     loop,
+		Inserted: totalInserted,
+		Filtered: totalFiltered,
+		Deleted:  deleted,
+		Changed:  changed,
+		Failed:   failed,
+		TotalDB:  totalInDB,
+		Duration: time.Since(start).Seconds(),
+	}))
 }
 
 // ─── deleteNonTechEvents ────────────────────────────────────────────────────
@@ -307,6 +358,40 @@ func (s *Scheduler) runDetailScraperPython() {
 	fmt.Println("  ✅ Detail scraper completed")
 }
 
+// ─── notifyChanges ───────────────────────────────────────────────────────────
+// Finds events inserted or changed since the cycle started and announces
+// them (see internal/changes): webhook deliveries, live stream messages and
+// rescheduled reminders. Returns the number of changes found.
+func (s *Scheduler) notifyChanges(since time.Time) int {
+	found, err := s.db.CollectEventChanges(since)
+	if err != nil {
+		s.logger.Error("Failed to collect event changes", zap.Error(err))
+		return 0
+	}
+	if len(found) == 0 {
+		return 0
+	}
+
+	queued := changes.Announce(s.db.GetConn(), s.publisher, found)
+	fmt.Printf("\n📨 %d event changes → %d webhook deliveries queued\n", len(found), queued)
+	return len(found)
+}
+
+// ─── geocodeEvents ───────────────────────────────────────────────────────────
+// Places events the cycle added or changed, using the cleaned location when
+// the LLM step produced one. The API server also runs this in the background.
+func (s *Scheduler) geocodeEvents() {
+	// Pick up aliases added through the API since the last cycle.
+	if err := gazetteer.Refresh(s.db.GetConn()); err != nil {
+		s.logger.Warn("Could not load gazetteer aliases", zap.Error(err))
+	}
+	n, err := geo.NewGeocoder(s.db.GetConn()).GeocodePending(context.Background())
+	if err != nil {
+		s.logger.Warn("Geocoding failed", zap.Error(err))
+	}
+	fmt.Printf("\n📍 Geocoded %d events\n", n)
+}
+
 // ─── getTotalEventCount ──────────────────────────────────────────────────────
 // Returns the current total number of events in the database.
 func (s *Scheduler) getTotalEventCount() int {
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"event-scraper/internal/database"
+	"event-scraper/internal/models"
+	"event-scraper/internal/stream"
+)
+
+// TestIngestPublishesToStream writes an event the way a scrape cycle does
+// and checks that notifyChanges announces it, then its change, on the
+// stream. It needs TEST_DATABASE_URL to name a database the API server has
+// already migrated, and is skipped otherwise.
+func TestIngestPublishesToStream(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	sqlDB, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlDB.Close()
+	db, err := database.New(sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	broker := stream.NewBroker(16)
+	sub := broker.Subscribe(stream.Filter{}, 0)
+	defer broker.Unsubscribe(sub)
+	s := &Scheduler{db: db, logger: zap.NewNop(), publisher: broker}
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	event := &models.Event{
+		EventName: "Stream check meetup " + suffix,
+		Location:  "Bangalore",
+		Date:      time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		Time:      "6:00 PM",
+		Website:   "https://example.com/stream-check/" + suffix,
+		Platform:  "stream-test",
+	}
+
+	since := time.Now().Add(-time.Second)
+	if err := db.InsertEvent(event); err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	defer sqlDB.Exec(`DELETE FROM events WHERE id = $1`, event.ID)
+	s.notifyChanges(since)
+	expectEventMessage(t, sub, stream.TypeEventInserted, event.ID)
+
+	since = time.Now().Add(-time.Second)
+	event.Location = "Koramangala, Bangalore"
+	if err := db.UpdateEvent(event.ID, event); err != nil {
+		t.Fatalf("update event: %v", err)
+	}
+	s.notifyChanges(since)
+	expectEventMessage(t, sub, stream.TypeEventUpdated, event.ID)
+}
+
+// expectEventMessage reads sub until a message of typ about eventID arrives.
+// Other events changed by concurrent writers are passed over.
+func expectEventMessage(t *testing.T, sub *stream.Subscription, typ string, eventID int64) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-sub.C:
+			if !ok {
+				t.Fatal("subscription closed")
+			}
+			var summary stream.EventSummary
+			if err := json.Unmarshal(msg.Data, &summary); err != nil {
+				continue
+			}
+			if msg.Type == typ && summary.ID == eventID {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no %s message for event %d", typ, eventID)
+		}
+	}
+}
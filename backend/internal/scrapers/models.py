@@ -5,6 +5,7 @@ Mirrors the Go structs in internal/models/event.go.
 """
 
 import hashlib
+import os
 import re
 from dataclasses import dataclass, field
 from datetime import datetime
@@ -14,83 +15,113 @@ from datetime import datetime
 #  CITY EXTRACTION
 # ═══════════════════════════════════════════════════════════════════════════════
 
-VENUE_TO_CITY = {
-    "hitex": "Hyderabad", "hicc": "Hyderabad", "shilpakala": "Hyderabad",
-    "t-hub": "Hyderabad", "iiit hyderabad": "Hyderabad", "hitech city": "Hyderabad",
-    "gachibowli": "Hyderabad", "madhapur": "Hyderabad", "banjara hills": "Hyderabad",
-    "biec": "Bengaluru", "palace grounds": "Bengaluru", "koramangala": "Bengaluru",
-    "indiranagar": "Bengaluru", "whitefield": "Bengaluru", "hsr layout": "Bengaluru",
-    "electronic city": "Bengaluru", "ub city": "Bengaluru", "mg road": "Bengaluru",
-    "bombay exhibition": "Mumbai", "bkc": "Mumbai", "nesco": "Mumbai",
-    "powai": "Mumbai", "andheri": "Mumbai", "lower parel": "Mumbai",
-    "pragati maidan": "Delhi", "india expo": "Delhi", "dwarka": "Delhi",
-    "connaught place": "Delhi", "gurugram": "Gurugram", "gurgaon": "Gurugram",
-    "noida": "Noida", "greater noida": "Noida",
-    "anna salai": "Chennai", "tidel park": "Chennai", "omr": "Chennai",
-    "chennai trade centre": "Chennai", "nandambakkam": "Chennai",
-    "hinjawadi": "Pune", "kharadi": "Pune", "magarpatta": "Pune",
-    "viman nagar": "Pune", "baner": "Pune", "auto cluster": "Pune",
-    "salt lake": "Kolkata", "sector v": "Kolkata", "new town": "Kolkata",
-}
-
-CITY_ALIASES = {
-    "bengaluru": "Bengaluru", "bangalore": "Bengaluru", "blr": "Bengaluru",
-    "hyderabad": "Hyderabad", "hyd": "Hyderabad", "secunderabad": "Hyderabad",
-    "mumbai": "Mumbai", "bombay": "Mumbai",
-    "delhi": "Delhi", "new delhi": "Delhi",
-    "chennai": "Chennai", "madras": "Chennai",
-    "pune": "Pune", "kolkata": "Kolkata", "calcutta": "Kolkata",
-    "gurugram": "Gurugram", "gurgaon": "Gurugram",
-    "noida": "Noida", "greater noida": "Noida",
-    "ahmedabad": "Ahmedabad", "kochi": "Kochi", "cochin": "Kochi",
-    "jaipur": "Jaipur", "lucknow": "Lucknow", "chandigarh": "Chandigarh",
-    "indore": "Indore", "bhopal": "Bhopal", "coimbatore": "Coimbatore",
-    "thiruvananthapuram": "Thiruvananthapuram", "trivandrum": "Thiruvananthapuram",
-    "nagpur": "Nagpur", "visakhapatnam": "Visakhapatnam", "vizag": "Visakhapatnam",
-    "mysuru": "Mysuru", "mysore": "Mysuru", "mangalore": "Mangalore",
-    "karnataka": "Bengaluru", "telangana": "Hyderabad",
-    "maharashtra": "Mumbai", "tamil Nadu": "Chennai",
-}
-
-_GARBAGE_RE = re.compile(
-    r"(n/a|tba|tbd|to be announced|online|virtual|zoom|webinar|"
-    r"google meet|microsoft teams|anywhere|remote)", re.I
+# The gazetteer is shared with the Go backend: internal/gazetteer/places.tsv
+# is the one list of cities, aliases, localities and venues (its header
+# describes the format), and extract_city applies the same rules as
+# ExtractCity in internal/utils/city.go. Aliases that admins add through the
+# API (aliases, venues and localities) are read from the gazetteer_aliases
+# table once per run.
+
+GAZETTEER_PATH = os.path.join(
+    os.path.dirname(os.path.abspath(__file__)), "..", "gazetteer", "places.tsv"
 )
 
+# Venues and localities beat city names and aliases, which beat states.
+_TIERS = {"venue": 2, "locality": 2, "city": 1, "alias": 1, "state": 0}
+
+# Mirrors IsGarbageLocation in internal/utils/city.go.
+_GARBAGE_PATTERNS = (
+    "http://", "https://", "register at:", "venue will be shared",
+    "tba", "to be announced", "to be confirmed", "details coming",
+    "hall -", "hall-",
+)
+
+_places = None
+
+
+def normalize_place(s: str) -> str:
+    """Lower-case s and turn punctuation into single spaces ("T-Hub" → "t hub")."""
+    return " ".join(re.findall(r"[^\W_]+", s.lower()))
+
+
+def load_gazetteer(path: str = GAZETTEER_PATH) -> tuple:
+    """Read places.tsv into ({canonical city}, [(tier, name, city)])."""
+    cities, rows = set(), []
+    with open(path, encoding="utf-8") as f:
+        for line_no, line in enumerate(f, 1):
+            line = line.strip()
+            if not line or line.startswith("#"):
+                continue
+            fields = line.split("\t")
+            kind = fields[0]
+            if kind not in _TIERS or len(fields) < 3:
+                raise ValueError(f"{path}:{line_no}: bad gazetteer row")
+            if kind == "city":
+                cities.add(fields[1].strip())
+                rows.append((_TIERS[kind], normalize_place(fields[1]), fields[1].strip()))
+            else:
+                rows.append((_TIERS[kind], normalize_place(fields[1]), fields[2].strip()))
+    return cities, rows
+
+
+def _runtime_aliases() -> list:
+    """Aliases from gazetteer_aliases, or none when the DB is unreachable."""
+    try:
+        from base_scraper import get_db_connection
+
+        conn = get_db_connection()
+        try:
+            with conn.cursor() as cur:
+                cur.execute("SELECT name, kind, city FROM gazetteer_aliases")
+                return cur.fetchall()
+        finally:
+            conn.close()
+    except Exception as e:
+        print(f"  [gazetteer] runtime aliases not loaded: {e}")
+        return []
+
+
+def _gazetteer() -> list:
+    """Places sorted most specific first, deduplicated on name and city."""
+    global _places
+    if _places is None:
+        cities, rows = load_gazetteer()
+        known = {name for _, name, _ in rows}
+        for name, kind, city in _runtime_aliases():
+            name = normalize_place(name)
+            if city in cities and kind in _TIERS and name and name not in known:
+                rows.append((_TIERS[kind], name, city))
+        seen, places = set(), []
+        for tier, name, city in rows:
+            if (name, city) not in seen:
+                seen.add((name, city))
+                places.append((tier, name, city))
+        places.sort(key=lambda p: (-p[0], -len(p[1]), p[1], p[2]))
+        _places = places
+    return _places
+
+
+def resolve_city(text: str) -> str:
+    """City of the best gazetteer match in text, or "" if nothing matches."""
+    haystack = " " + normalize_place(text) + " | "
+    best = None
+    for tier, name, city in _gazetteer():
+        if best and (tier, len(name)) != (best[0], len(best[1])):
+            break
+        pos = haystack.find(" " + name + " ")
+        if pos >= 0 and (best is None or pos < best[3]):
+            best = (tier, name, city, pos)
+    return best[2] if best else ""
+
 
 def extract_city(raw_location: str) -> str:
     """Derive a canonical city name from a raw location string."""
     if not raw_location or not raw_location.strip():
         return "Unknown"
-    if _GARBAGE_RE.search(raw_location):
+    lower = raw_location.strip().lower()
+    if any(p in lower for p in _GARBAGE_PATTERNS) or len(raw_location.strip()) < 4:
         return "Unknown"
-
-    norm = re.sub(r"\s+", " ", raw_location.lower()).strip()
-
-    # Step 1: venue substring match (prefer longest)
-    best_venue, best_city = "", ""
-    for venue, city in VENUE_TO_CITY.items():
-        if venue in norm and len(venue) > len(best_venue):
-            best_venue, best_city = venue, city
-    if best_city:
-        return best_city
-
-    # Step 2: city alias token match
-    parts = [p.strip() for p in norm.split(",")]
-    parts.append(norm)
-    for part in parts:
-        if part in CITY_ALIASES:
-            return CITY_ALIASES[part]
-        for word in part.split():
-            if word in CITY_ALIASES:
-                return CITY_ALIASES[word]
-        words = part.split()
-        for i in range(len(words) - 1):
-            two_word = words[i] + " " + words[i + 1]
-            if two_word in CITY_ALIASES:
-                return CITY_ALIASES[two_word]
-
-    return "Unknown"
+    return resolve_city(raw_location) or "Unknown"
 
 
 # ═══════════════════════════════════════════════════════════════════════════════
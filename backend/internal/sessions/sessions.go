@@ -0,0 +1,304 @@
+// Package sessions backs short-lived access tokens with revocable,
+// server-side sessions.
+//
+// Signing in opens a session and returns a refresh token for it. Each
+// refresh exchanges the token for a new one (rotation); only a hash is
+// stored. Presenting a token that was already exchanged means it leaked,
+// so the whole session — the token family — is revoked. Access tokens
+// carry the session ID and stop working as soon as it is revoked.
+package sessions
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RefreshTTL is how long a refresh token stays valid unused. Every refresh
+// extends the session by as much.
+const RefreshTTL = 30 * 24 * time.Hour
+
+// Revocation reasons.
+const (
+	ReasonLogout         = "logout"
+	ReasonLogoutAll      = "logout_all"
+	ReasonReuse          = "reuse"
+	ReasonPasswordReset  = "password_reset"
+	ReasonPasswordChange = "password_change"
+)
+
+var (
+	// ErrInvalid is returned for unknown, expired and revoked tokens.
+	ErrInvalid = errors.New("invalid or expired refresh token")
+	// ErrReused is returned when an already rotated token comes back; the
+	// session has been revoked.
+	ErrReused   = errors.New("refresh token reused; session revoked")
+	ErrNotFound = errors.New("session not found")
+)
+
+// Session is one signed-in device.
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"-"`
+	UserAgent  string     `json:"user_agent"`
+	IP         string     `json:"ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	// Current is set by callers for the session making the request.
+	Current bool `json:"current"`
+}
+
+// Schema holds the DDL for sessions and their refresh tokens. A token row
+// outlives its rotation so reuse can be detected.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS sessions (
+		id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		user_agent     TEXT NOT NULL DEFAULT '',
+		ip             VARCHAR(64) NOT NULL DEFAULT '',
+		created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_used_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+		expires_at     TIMESTAMPTZ NOT NULL,
+		revoked_at     TIMESTAMPTZ,
+		revoked_reason VARCHAR(20)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id)`,
+	`CREATE TABLE IF NOT EXISTS refresh_tokens (
+		token_hash  TEXT PRIMARY KEY,
+		session_id  UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+		rotated_at  TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id)`,
+}
+
+// EnsureSchema creates the session tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("sessions migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+const sessionCols = `id::text, user_id::text, user_agent, ip, created_at, last_used_at, expires_at, revoked_at`
+
+func scanSession(row interface{ Scan(...interface{}) error }, s *Session) error {
+	return row.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt, &s.RevokedAt)
+}
+
+// Create opens a session for a user and returns its first refresh token.
+func Create(db *sql.DB, userID, userAgent, ip string) (*Session, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.Rollback()
+
+	var s Session
+	if err := scanSession(tx.QueryRow(`
+		INSERT INTO sessions (user_id, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+sessionCols,
+		userID, truncate(userAgent, 500), truncate(ip, 64), time.Now().Add(RefreshTTL),
+	), &s); err != nil {
+		return nil, "", err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO refresh_tokens (token_hash, session_id) VALUES ($1, $2)`, hashToken(token), s.ID,
+	); err != nil {
+		return nil, "", err
+	}
+	return &s, token, tx.Commit()
+}
+
+// Rotate exchanges a refresh token for a new one in the same session.
+func Rotate(db *sql.DB, token string) (*Session, string, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.Rollback()
+
+	// Lock the token row, so two concurrent refreshes with the same token
+	// cannot both succeed: the second sees rotated_at and counts as reuse.
+	var sessionID string
+	var rotatedAt sql.NullTime
+	err = tx.QueryRow(`
+		SELECT session_id::text, rotated_at FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE
+	`, hashToken(token)).Scan(&sessionID, &rotatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrInvalid
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	var s Session
+	if err := scanSession(tx.QueryRow(
+		`SELECT `+sessionCols+` FROM sessions WHERE id = $1 FOR UPDATE`, sessionID,
+	), &s); err != nil {
+		return nil, "", err
+	}
+	if s.RevokedAt != nil || time.Now().After(s.ExpiresAt) {
+		return nil, "", ErrInvalid
+	}
+	if rotatedAt.Valid {
+		if _, err := tx.Exec(`
+			UPDATE sessions SET revoked_at = now(), revoked_reason = $2 WHERE id = $1
+		`, s.ID, ReasonReuse); err != nil {
+			return nil, "", err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrReused
+	}
+
+	next, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := tx.Exec(
+		`UPDATE refresh_tokens SET rotated_at = now() WHERE token_hash = $1`, hashToken(token),
+	); err != nil {
+		return nil, "", err
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO refresh_tokens (token_hash, session_id) VALUES ($1, $2)`, hashToken(next), s.ID,
+	); err != nil {
+		return nil, "", err
+	}
+	if err := tx.QueryRow(`
+		UPDATE sessions SET last_used_at = now(), expires_at = $2 WHERE id = $1
+		RETURNING last_used_at, expires_at
+	`, s.ID, time.Now().Add(RefreshTTL)).Scan(&s.LastUsedAt, &s.ExpiresAt); err != nil {
+		return nil, "", err
+	}
+	return &s, next, tx.Commit()
+}
+
+// Active reports whether a session may still be used.
+func Active(db *sql.DB, sessionID string) (bool, error) {
+	var active bool
+	err := db.QueryRow(`
+		SELECT revoked_at IS NULL AND expires_at > now() FROM sessions WHERE id = $1
+	`, sessionID).Scan(&active)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return active, err
+}
+
+// SessionOf returns the session a refresh token belongs to, rotated or not.
+func SessionOf(db *sql.DB, token string) (*Session, error) {
+	var s Session
+	err := scanSession(db.QueryRow(`
+		SELECT `+sessionCols+` FROM sessions
+		WHERE id = (SELECT session_id FROM refresh_tokens WHERE token_hash = $1)
+	`, hashToken(token)), &s)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List returns a user's sessions that are still active, newest first.
+func List(db *sql.DB, userID string) ([]Session, error) {
+	rows, err := db.Query(`
+		SELECT `+sessionCols+` FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY last_used_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Session{}
+	for rows.Next() {
+		var s Session
+		if err := scanSession(rows, &s); err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, rows.Err()
+}
+
+// Revoke ends one of a user's sessions.
+func Revoke(db *sql.DB, userID, sessionID, reason string) error {
+	res, err := db.Exec(`
+		UPDATE sessions SET revoked_at = now(), revoked_reason = $3
+		WHERE id::text = $1 AND user_id = $2 AND revoked_at IS NULL
+	`, sessionID, userID, reason)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RevokeAll ends every session of a user except keep, which may be empty,
+// and returns how many it ended.
+func RevokeAll(db *sql.DB, userID, keep, reason string) (int, error) {
+	res, err := db.Exec(`
+		UPDATE sessions SET revoked_at = now(), revoked_reason = $3
+		WHERE user_id = $1 AND id::text <> $2 AND revoked_at IS NULL
+	`, userID, keep, reason)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// Purge deletes sessions that ended more than a week ago.
+func Purge(db *sql.DB) (int, error) {
+	res, err := db.Exec(`
+		DELETE FROM sessions
+		WHERE COALESCE(revoked_at, expires_at) < now() - INTERVAL '7 days'
+	`)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,344 @@
+// Package similar finds events like a given one, for the "More like this"
+// recommendations. Events are compared on their tech stack, the terms of
+// their title and description, how close their dates are and their city.
+// Tech and terms carry most of the weight and one of them must match, so a
+// Rust workshop is never recommended a yoga meetup just because both are in
+// the same city. Results are cached per event (see store.go).
+package similar
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"event-scraper/pkg/utils"
+)
+
+// Points available per signal; a score is their sum, at most 100.
+const (
+	pointsTech  = 40
+	pointsTerms = 35
+	pointsDate  = 15
+	pointsCity  = 10
+)
+
+// minContent is the least a match must score on tech and terms together.
+const minContent = 6
+
+// dateWindow is how far apart two dates may be and still score.
+const dateWindow = 30
+
+// dupTitleSimilarity is the utils.SimilarityScore from which two titles
+// name the same event.
+const dupTitleSimilarity = 90
+
+// Reason is one signal behind a match.
+type Reason struct {
+	Kind   string `json:"kind"` // tech, terms, date or city
+	Value  string `json:"value"`
+	Points int    `json:"points"`
+	Text   string `json:"text"`
+}
+
+// Match is an event similar to another one.
+type Match struct {
+	EventID int64    `json:"event_id"`
+	Score   int      `json:"score"`
+	Reasons []Reason `json:"reasons"`
+}
+
+// doc is what an event is compared on.
+type doc struct {
+	ID          int64
+	Title       string
+	Description string
+	Website     string
+	City        string
+	Date        string
+	Tech        []string
+
+	date  time.Time
+	dated bool
+	site  string
+	tech  map[string]bool
+	terms map[string]float64 // term frequency, title terms counted twice
+}
+
+func (d *doc) prepare() {
+	d.date, d.dated = utils.ParseDate(d.Date)
+	d.site = normalizeURL(d.Website)
+	d.tech = map[string]bool{}
+	for _, t := range d.Tech {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			d.tech[t] = true
+		}
+	}
+	d.terms = map[string]float64{}
+	for _, t := range tokenize(d.Title) {
+		d.terms[t] += 2
+	}
+	for _, t := range tokenize(d.Description) {
+		d.terms[t]++
+	}
+}
+
+// rank scores every prepared candidate against src and returns the best
+// limit matches, strongest first. Candidates that are src itself, a
+// duplicate of it or a duplicate of a better match are left out, as are
+// past events.
+func rank(src *doc, candidates []doc, idf map[string]float64, limit int) []Match {
+	srcVec := weigh(src.terms, idf)
+
+	type scored struct {
+		doc   *doc
+		match Match
+	}
+	var all []scored
+	for i := range candidates {
+		c := &candidates[i]
+		if c.ID == src.ID || !utils.IsUpcoming(c.Date) || duplicate(src, c) {
+			continue
+		}
+		m, ok := score(src, srcVec, c, weigh(c.terms, idf))
+		if ok {
+			all = append(all, scored{c, m})
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.match.Score != b.match.Score {
+			return a.match.Score > b.match.Score
+		}
+		if a.doc.dated != b.doc.dated {
+			return a.doc.dated
+		}
+		if !a.doc.date.Equal(b.doc.date) {
+			return a.doc.date.Before(b.doc.date)
+		}
+		return a.doc.ID < b.doc.ID
+	})
+
+	out := []Match{}
+	var picked []*doc
+next:
+	for _, s := range all {
+		if len(out) == limit {
+			break
+		}
+		for _, p := range picked {
+			if duplicate(p, s.doc) {
+				continue next
+			}
+		}
+		picked = append(picked, s.doc)
+		out = append(out, s.match)
+	}
+	return out
+}
+
+func score(src *doc, srcVec map[string]float64, c *doc, cVec map[string]float64) (Match, bool) {
+	m := Match{EventID: c.ID, Reasons: []Reason{}}
+
+	var shared []string
+	for t := range c.tech {
+		if src.tech[t] {
+			shared = append(shared, t)
+		}
+	}
+	if len(shared) > 0 {
+		sort.Strings(shared)
+		union := len(src.tech) + len(c.tech) - len(shared)
+		pts := round(pointsTech * float64(len(shared)) / float64(union))
+		m.Reasons = append(m.Reasons, Reason{"tech", strings.Join(shared, ", "), pts,
+			"Also covers " + list(shared)})
+	}
+
+	if cos, terms := cosine(srcVec, cVec); len(terms) > 0 {
+		if pts := round(pointsTerms * cos); pts > 0 {
+			m.Reasons = append(m.Reasons, Reason{"terms", strings.Join(terms, ", "), pts,
+				"Similar topic: " + strings.Join(terms, ", ")})
+		}
+	}
+
+	content := 0
+	for _, r := range m.Reasons {
+		content += r.Points
+	}
+	if content < minContent {
+		return m, false
+	}
+
+	if src.dated && c.dated {
+		days := int(math.Abs(dayOf(c.date).Sub(dayOf(src.date)).Hours()) / 24)
+		if days <= dateWindow {
+			pts := round(pointsDate * float64(dateWindow+1-days) / float64(dateWindow+1))
+			text := fmt.Sprintf("%d days apart", days)
+			switch days {
+			case 0:
+				text = "On the same day"
+			case 1:
+				text = "A day apart"
+			}
+			m.Reasons = append(m.Reasons, Reason{"date", c.date.Format("2006-01-02"), pts, text})
+		}
+	}
+
+	if city := strings.TrimSpace(c.City); city != "" && !strings.EqualFold(city, "unknown") &&
+		strings.EqualFold(city, strings.TrimSpace(src.City)) {
+		m.Reasons = append(m.Reasons, Reason{"city", city, pointsCity, "Also in " + city})
+	}
+
+	for _, r := range m.Reasons {
+		m.Score += r.Points
+	}
+	if m.Score > 100 {
+		m.Score = 100
+	}
+	return m, true
+}
+
+// duplicate reports whether a and b are the same event listed twice: same
+// link, or the same title on the same day (or without a date to tell).
+func duplicate(a, b *doc) bool {
+	if a.site != "" && a.site == b.site {
+		return true
+	}
+	if utils.SimilarityScore(a.Title, b.Title) < dupTitleSimilarity {
+		return false
+	}
+	return !a.dated || !b.dated || dayOf(a.date).Equal(dayOf(b.date))
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func normalizeURL(u string) string {
+	u = strings.ToLower(strings.TrimSpace(u))
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	u = strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+	u = strings.TrimPrefix(u, "www.")
+	return strings.TrimRight(u, "/")
+}
+
+// ─── Terms ───────────────────────────────────────────────────────────────────
+
+var wordRe = regexp.MustCompile(`[a-z0-9][a-z0-9+#.-]*[a-z0-9+#]|[a-z]`)
+
+// stopwords are common words and event boilerplate that say nothing about
+// the topic.
+var stopwords = toSet(`a about above after again all also am an and any are as at be
+because been before being below between both but by can could did do does doing
+during each few for from further get had has have having here how if in into is it
+its just learn like more most new no nor not now of off on once one only or other
+our out over own same should so some such than that the their them then there these
+they this those through to too under until up us very via was we were what when
+where which while who whom why will with you your yours
+event events join register registration online offline free session sessions day
+days date time venue hosted host hosts organised organized edition attend
+attendees participants welcome please details ticket tickets`)
+
+func toSet(words string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+func tokenize(s string) []string {
+	var out []string
+	for _, w := range wordRe.FindAllString(strings.ToLower(s), -1) {
+		w = strings.Trim(w, ".-")
+		if len(w) < 2 || stopwords[w] || isNumber(w) {
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if (r < '0' || r > '9') && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// inverseFrequencies weighs terms down the more documents use them.
+func inverseFrequencies(docs []doc) map[string]float64 {
+	df := map[string]int{}
+	for _, d := range docs {
+		for t := range d.terms {
+			df[t]++
+		}
+	}
+	idf := make(map[string]float64, len(df))
+	for t, n := range df {
+		idf[t] = math.Log(1 + float64(len(docs))/float64(n))
+	}
+	return idf
+}
+
+func weigh(tf, idf map[string]float64) map[string]float64 {
+	vec := make(map[string]float64, len(tf))
+	for t, f := range tf {
+		vec[t] = f * idf[t]
+	}
+	return vec
+}
+
+// cosine returns the cosine similarity of two term vectors and the shared
+// terms that contribute most, at most three.
+func cosine(a, b map[string]float64) (float64, []string) {
+	var dot, na, nb float64
+	type term struct {
+		t string
+		w float64
+	}
+	var shared []term
+	for t, w := range a {
+		na += w * w
+		if v, ok := b[t]; ok {
+			dot += w * v
+			shared = append(shared, term{t, w * v})
+		}
+	}
+	for _, w := range b {
+		nb += w * w
+	}
+	if dot == 0 {
+		return 0, nil
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		if shared[i].w != shared[j].w {
+			return shared[i].w > shared[j].w
+		}
+		return shared[i].t < shared[j].t
+	})
+	var top []string
+	for i := 0; i < len(shared) && i < 3; i++ {
+		top = append(top, shared[i].t)
+	}
+	return dot / math.Sqrt(na*nb), top
+}
+
+func round(f float64) int {
+	return int(math.Round(f))
+}
+
+// list joins values as "a, b and c".
+func list(values []string) string {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " and " + values[len(values)-1]
+}
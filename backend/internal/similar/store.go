@@ -0,0 +1,149 @@
+package similar
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Limit is how many matches are kept per event.
+const Limit = 20
+
+// TTL is how long cached matches are served. The scheduler also clears the
+// cache whenever a scrape changes events.
+const TTL = 6 * time.Hour
+
+// Schema holds the DDL for the similarity cache.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS event_similar (
+		event_id    INTEGER PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+		matches     JSONB NOT NULL DEFAULT '[]',
+		computed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+}
+
+// EnsureSchema creates the event_similar table if it does not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("similar migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Get returns the matches of every event in ids, from the cache when it is
+// fresh and computed otherwise. Events that do not exist are missing from
+// the result. Cached matches may since have passed or been deleted, so
+// callers load the events and drop those.
+func Get(db *sql.DB, ids []int64) (map[int64][]Match, error) {
+	out := make(map[int64][]Match, len(ids))
+	rows, err := db.Query(`
+		SELECT event_id, matches FROM event_similar
+		WHERE event_id = ANY($1) AND computed_at > now() - $2 * interval '1 second'
+	`, pq.Array(ids), TTL.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var raw []byte
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, err
+		}
+		var matches []Match
+		if err := json.Unmarshal(raw, &matches); err != nil {
+			continue // recomputed below
+		}
+		out[id] = matches
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var stale []int64
+	for _, id := range ids {
+		if _, ok := out[id]; !ok {
+			stale = append(stale, id)
+		}
+	}
+	if len(stale) == 0 {
+		return out, nil
+	}
+
+	computed, err := compute(db, stale)
+	if err != nil {
+		return nil, err
+	}
+	for id, matches := range computed {
+		raw, err := json.Marshal(matches)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := db.Exec(`
+			INSERT INTO event_similar (event_id, matches, computed_at) VALUES ($1, $2, now())
+			ON CONFLICT (event_id) DO UPDATE SET matches = EXCLUDED.matches, computed_at = now()
+		`, id, raw); err != nil {
+			return nil, err
+		}
+		out[id] = matches
+	}
+	return out, nil
+}
+
+// Clear drops every cached match, for when events changed.
+func Clear(db *sql.DB) error {
+	_, err := db.Exec(`DELETE FROM event_similar`)
+	return err
+}
+
+// compute ranks the upcoming and undated events against each event in ids.
+// The corpus is loaded once for all of them.
+func compute(db *sql.DB, ids []int64) (map[int64][]Match, error) {
+	rows, err := db.Query(`
+		SELECT e.id,
+		       COALESCE(NULLIF(ec.title_clean, ''), e.event_name, ''),
+		       COALESCE(NULLIF(ec.description_clean, ''), e.description, ''),
+		       COALESCE(e.website, ''), COALESCE(e.city_normalized, ''), COALESCE(e.date, ''),
+		       COALESCE(ec.tech_stack, '{}'),
+		       e.id = ANY($1)
+		FROM events e
+		LEFT JOIN event_cleaned ec ON ec.event_id = e.id
+		WHERE e.id = ANY($1)
+		   OR CASE WHEN e.date ~ '^\d{4}-\d{2}-\d{2}$' THEN e.date::date >= CURRENT_DATE ELSE true END
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var corpus []doc
+	var sources []int
+	for rows.Next() {
+		var d doc
+		var isSource bool
+		if err := rows.Scan(&d.ID, &d.Title, &d.Description, &d.Website, &d.City, &d.Date,
+			pq.Array(&d.Tech), &isSource); err != nil {
+			return nil, err
+		}
+		d.prepare()
+		if isSource {
+			sources = append(sources, len(corpus))
+		}
+		corpus = append(corpus, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	idf := inverseFrequencies(corpus)
+	out := make(map[int64][]Match, len(sources))
+	for _, i := range sources {
+		out[corpus[i].ID] = rank(&corpus[i], corpus, idf, Limit)
+	}
+	return out, nil
+}
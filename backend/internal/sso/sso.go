@@ -0,0 +1,179 @@
+// Package sso signs users in through an OpenID Connect identity provider
+// with the authorization code flow and PKCE.
+//
+// The provider is discovered from its issuer URL on first use, so the API
+// server starts even when the provider is down. An identity (issuer and
+// subject) is tied to one row in users: on first sign-in it is linked to
+// the user with the same, provider-verified email, or a new user is made.
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Config describes the identity provider and this app's client with it.
+type Config struct {
+	// Name is shown on the sign-in button.
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback route of this API as the browser sees
+	// it, registered with the provider.
+	RedirectURL string
+	Scopes      []string
+	// TrustEmail treats every email from the provider as verified, for
+	// providers that vouch for addresses but send no email_verified claim.
+	TrustEmail bool
+}
+
+// ConfigFromEnv reads OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET,
+// OIDC_REDIRECT_URL (default appURL + "/api/auth/oidc/callback"),
+// OIDC_SCOPES (default "openid email profile"), OIDC_NAME and
+// OIDC_TRUST_EMAIL. ok is false when no issuer or client is configured.
+func ConfigFromEnv(appURL string) (cfg Config, ok bool) {
+	cfg = Config{
+		Name:         getEnv("OIDC_NAME", "single sign-on"),
+		Issuer:       strings.TrimRight(os.Getenv("OIDC_ISSUER"), "/"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  getEnv("OIDC_REDIRECT_URL", appURL+"/api/auth/oidc/callback"),
+		Scopes:       strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+		TrustEmail:   os.Getenv("OIDC_TRUST_EMAIL") == "true",
+	}
+	return cfg, cfg.Issuer != "" && cfg.ClientID != ""
+}
+
+// Identity is the signed-in user as the provider describes them.
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider talks to one identity provider.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// New returns a Provider for cfg. Nothing is fetched until it is used.
+func New(cfg Config) *Provider {
+	return &Provider{cfg: cfg, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// Name is the display name of the provider.
+func (p *Provider) Name() string { return p.cfg.Name }
+
+// discover fetches the provider's metadata once it succeeds.
+func (p *Provider) discover() (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+	// Keys are fetched later with this context, so it must outlive the
+	// request that happens to trigger discovery.
+	ctx := oidc.ClientContext(context.Background(), p.client)
+	provider, err := oidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("discover %s: %w", p.cfg.Issuer, err)
+	}
+	p.oauth = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.cfg.Scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.cfg.ClientID})
+	return p.oauth, p.verifier, nil
+}
+
+// AuthCodeURL is where to send the browser to sign in. verifier is the
+// PKCE code verifier; only its S256 challenge is sent.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	oauth, _, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	return oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange redeems the authorization code from the callback and verifies
+// the ID token that comes back, including its nonce.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	oauth, idVerifier, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+	ctx = oidc.ClientContext(ctx, p.client)
+	token, err := oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+	raw, ok := token.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+	idToken, err := idVerifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	var claims struct {
+		Email         string      `json:"email"`
+		EmailVerified interface{} `json:"email_verified"`
+		Name          string      `json:"name"`
+		PreferredName string      `json:"preferred_username"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("read id_token claims: %w", err)
+	}
+	id := &Identity{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+		Email:   strings.TrimSpace(strings.ToLower(claims.Email)),
+		Name:    strings.TrimSpace(claims.Name),
+	}
+	// Some providers send email_verified as the string "true".
+	switch v := claims.EmailVerified.(type) {
+	case bool:
+		id.EmailVerified = v
+	case string:
+		id.EmailVerified = v == "true"
+	}
+	if p.cfg.TrustEmail && id.Email != "" {
+		id.EmailVerified = true
+	}
+	if id.Name == "" {
+		id.Name = strings.TrimSpace(claims.PreferredName)
+	}
+	return id, nil
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,216 @@
+package sso
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LoginTTL bounds the time between leaving for the provider and coming
+// back to the callback.
+const LoginTTL = 10 * time.Minute
+
+var (
+	// ErrUnknownLogin is returned for a callback state that was never
+	// issued, has expired or was already used.
+	ErrUnknownLogin = errors.New("sign-in attempt not found or expired")
+	// ErrEmailUnverified is returned when a new identity comes without an
+	// email the provider has verified, so it can be neither linked nor
+	// used for a new account.
+	ErrEmailUnverified = errors.New("the identity provider has not verified this email address")
+)
+
+// Schema holds the DDL for pending sign-ins and linked identities.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS sso_logins (
+		state_hash  TEXT PRIMARY KEY,
+		verifier    TEXT NOT NULL,
+		nonce       TEXT NOT NULL,
+		redirect_to TEXT NOT NULL DEFAULT '/',
+		expires_at  TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS user_identities (
+		issuer        TEXT NOT NULL,
+		subject       TEXT NOT NULL,
+		user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		email         VARCHAR(180) NOT NULL DEFAULT '',
+		created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_login_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		PRIMARY KEY (issuer, subject)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id)`,
+}
+
+// EnsureSchema creates the SSO tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("sso migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Login is a sign-in in progress.
+type Login struct {
+	State      string
+	Nonce      string
+	Verifier   string
+	RedirectTo string
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashState(state string) string {
+	h := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(h[:])
+}
+
+// StartLogin records a new sign-in that returns to redirectTo, a path in
+// the frontend, and returns its state, nonce and PKCE verifier.
+func StartLogin(db *sql.DB, redirectTo string) (*Login, error) {
+	l := &Login{RedirectTo: redirectTo}
+	for _, v := range []*string{&l.State, &l.Nonce, &l.Verifier} {
+		s, err := randomString()
+		if err != nil {
+			return nil, err
+		}
+		*v = s
+	}
+	// Abandoned sign-ins are cleared as new ones start.
+	if _, err := db.Exec(`DELETE FROM sso_logins WHERE expires_at < now()`); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(`
+		INSERT INTO sso_logins (state_hash, verifier, nonce, redirect_to, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, hashState(l.State), l.Verifier, l.Nonce, redirectTo, time.Now().Add(LoginTTL)); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// TakeLogin removes and returns the sign-in with the given state, so a
+// callback can be used once.
+func TakeLogin(db *sql.DB, state string) (*Login, error) {
+	l := &Login{State: state}
+	err := db.QueryRow(`
+		DELETE FROM sso_logins WHERE state_hash = $1 AND expires_at > now()
+		RETURNING verifier, nonce, redirect_to
+	`, hashState(state)).Scan(&l.Verifier, &l.Nonce, &l.RedirectTo)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUnknownLogin
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// LinkUser returns the user of an identity. An identity seen for the first
+// time is linked to the user with its email, which the provider must have
+// verified, or to a new user. created reports a new user.
+func LinkUser(db *sql.DB, id *Identity) (userID string, created bool, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return "", false, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		UPDATE user_identities SET last_login_at = now(), email = $3
+		WHERE issuer = $1 AND subject = $2
+		RETURNING user_id::text
+	`, id.Issuer, id.Subject, id.Email).Scan(&userID)
+	if err == nil {
+		return userID, false, tx.Commit()
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", false, err
+	}
+
+	if id.Email == "" || !id.EmailVerified {
+		return "", false, ErrEmailUnverified
+	}
+	err = tx.QueryRow(`
+		UPDATE users SET email_verified_at = COALESCE(email_verified_at, now()), updated_at = now()
+		WHERE email = $1
+		RETURNING id::text
+	`, id.Email).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// No password: the account signs in through the provider until a
+		// password is set with a reset link.
+		name := id.Name
+		if name == "" {
+			name = strings.SplitN(id.Email, "@", 2)[0]
+		}
+		err = tx.QueryRow(`
+			INSERT INTO users (full_name, email, password_hash, email_verified_at)
+			VALUES ($1, $2, '', now())
+			RETURNING id::text
+		`, truncate(name, 120), id.Email).Scan(&userID)
+		created = true
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO user_identities (issuer, subject, user_id, email)
+		VALUES ($1, $2, $3, $4)
+	`, id.Issuer, id.Subject, userID, id.Email); err != nil {
+		return "", false, err
+	}
+	return userID, created, tx.Commit()
+}
+
+// LinkedIdentity is an identity linked to a user.
+type LinkedIdentity struct {
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// ListIdentities returns the identities linked to a user, oldest first.
+func ListIdentities(db *sql.DB, userID string) ([]LinkedIdentity, error) {
+	rows, err := db.Query(`
+		SELECT issuer, subject, email, created_at, last_login_at FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []LinkedIdentity{}
+	for rows.Next() {
+		var id LinkedIdentity
+		if err := rows.Scan(&id.Issuer, &id.Subject, &id.Email, &id.CreatedAt, &id.LastLoginAt); err != nil {
+			return nil, err
+		}
+		list = append(list, id)
+	}
+	return list, rows.Err()
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres NOTIFY channel messages travel on between processes.
+const Channel = "event_stream"
+
+// maxPayload is just under Postgres' 8000-byte NOTIFY limit.
+const maxPayload = 7900
+
+// PGNotifier publishes messages with pg_notify so every server process that
+// is listening forwards them to its own subscribers.
+type PGNotifier struct {
+	db *sql.DB
+}
+
+func NewPGNotifier(db *sql.DB) *PGNotifier {
+	return &PGNotifier{db: db}
+}
+
+func (p *PGNotifier) Publish(msg Message) error {
+	msg.ID = 0
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayload {
+		return fmt.Errorf("stream message %s is %d bytes, over the NOTIFY limit", msg.Type, len(payload))
+	}
+	_, err = p.db.Exec(`SELECT pg_notify($1, $2)`, Channel, string(payload))
+	return err
+}
+
+// Listen subscribes to Channel and republishes every notification on b until
+// ctx is cancelled. It returns once the first LISTEN succeeded; reconnects
+// after that are handled in the background by pq.
+func Listen(ctx context.Context, connStr string, b *Broker) error {
+	listener := pq.NewListener(connStr, 2*time.Second, time.Minute,
+		func(ev pq.ListenerEventType, err error) {
+			if err != nil {
+				log.Printf("⚠️  Stream listener: %v", err)
+			}
+		})
+	if err := listener.Listen(Channel); err != nil {
+		listener.Close()
+		return err
+	}
+
+	go func() {
+		defer listener.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-listener.Notify:
+				// nil means the connection was re-established; anything sent
+				// while it was down is lost.
+				if n == nil {
+					continue
+				}
+				var msg Message
+				if err := json.Unmarshal([]byte(n.Extra), &msg); err != nil {
+					log.Printf("⚠️  Stream listener: bad payload: %v", err)
+					continue
+				}
+				b.Publish(msg)
+			case <-time.After(90 * time.Second):
+				go listener.Ping()
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package stream fans live scraper activity out to Server-Sent Events clients.
+//
+// Producers (the scheduler, the manual detail scrape) hand Messages to a
+// Publisher. Inside the API process that is the Broker itself; when the
+// scheduler runs in its own process (cmd/scraper) it publishes through Postgres NOTIFY and
+// the API process forwards the notifications into its Broker with Listen.
+package stream
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"event-scraper/internal/models"
+)
+
+// Message types sent to clients as the SSE "event:" field.
+const (
+	TypeEventInserted = "event-inserted"
+	TypeEventUpdated  = "event-updated"
+	TypeCycleStarted  = "cycle-started"
+	TypeCycleFinished = "cycle-finished"
+	TypeScraperFailed = "scraper-failed"
+)
+
+// Types lists every message type in the order they are documented.
+var Types = []string{
+	TypeEventInserted, TypeEventUpdated,
+	TypeCycleStarted, TypeCycleFinished, TypeScraperFailed,
+}
+
+// Message is one published update. ID is assigned by the Broker that delivers
+// it, so it is only meaningful for resuming against the same server process.
+type Message struct {
+	ID       int64           `json:"id,omitempty"`
+	Type     string          `json:"type"`
+	Time     time.Time       `json:"time"`
+	City     string          `json:"city,omitempty"`
+	Platform string          `json:"platform,omitempty"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// Publisher accepts messages for delivery to stream subscribers.
+type Publisher interface {
+	Publish(msg Message) error
+}
+
+// EventSummary is the trimmed event sent with event-inserted/updated. It leaves
+// out descriptions so a message always fits in a NOTIFY payload.
+type EventSummary struct {
+	ID        int64  `json:"id"`
+	EventName string `json:"event_name"`
+	City      string `json:"city"`
+	Platform  string `json:"platform"`
+	Date      string `json:"date"`
+	Time      string `json:"time"`
+	Location  string `json:"location"`
+	EventType string `json:"event_type"`
+	Website   string `json:"website"`
+}
+
+// EventMessage builds an event-inserted or event-updated message.
+func EventMessage(typ string, e models.Event) Message {
+	data, _ := json.Marshal(EventSummary{
+		ID:        e.ID,
+		EventName: truncate(e.EventName, 300),
+		City:      e.CityNormalized,
+		Platform:  e.Platform,
+		Date:      e.Date,
+		Time:      e.Time,
+		Location:  truncate(e.Location, 300),
+		EventType: e.EventType,
+		Website:   truncate(e.Website, 1000),
+	})
+	return Message{Type: typ, Time: time.Now().UTC(), City: e.CityNormalized, Platform: e.Platform, Data: data}
+}
+
+// CycleStats is the payload of cycle-finished.
+type CycleStats struct {
+	Loop     int     `json:"loop"`
+	Inserted int     `json:"inserted"`
+	Filtered int     `json:"filtered"`
+	Deleted  int     `json:"deleted"`
+	Changed  int     `json:"changed"`
+	Failed   int     `json:"failed"`
+	TotalDB  int     `json:"total_db"`
+	Duration float64 `json:"duration_seconds"`
+}
+
+// CycleStarted builds a cycle-started message.
+func CycleStarted(loop int) Message {
+	data, _ := json.Marshal(map[string]int{"loop": loop})
+	return Message{Type: TypeCycleStarted, Time: time.Now().UTC(), Data: data}
+}
+
+// CycleFinished builds a cycle-finished message.
+func CycleFinished(stats CycleStats) Message {
+	data, _ := json.Marshal(stats)
+	return Message{Type: TypeCycleFinished, Time: time.Now().UTC(), Data: data}
+}
+
+// ScraperFailed builds a scraper-failed message.
+func ScraperFailed(scraper, errMsg string, duration time.Duration) Message {
+	data, _ := json.Marshal(map[string]interface{}{
+		"scraper":          scraper,
+		"error":            truncate(errMsg, 2000),
+		"duration_seconds": duration.Seconds(),
+	})
+	return Message{Type: TypeScraperFailed, Time: time.Now().UTC(), Platform: scraper, Data: data}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+// ─── Filtering ────────────────────────────────────────────────────────────────
+
+// Filter selects the messages a subscriber receives. Empty fields match all.
+// City and Platform only narrow event messages; cycle and scraper messages
+// are always delivered so dashboards stay in sync.
+type Filter struct {
+	Types    map[string]bool
+	City     string
+	Platform string
+}
+
+func (f Filter) Matches(msg Message) bool {
+	if len(f.Types) > 0 && !f.Types[msg.Type] {
+		return false
+	}
+	if msg.Type != TypeEventInserted && msg.Type != TypeEventUpdated {
+		return true
+	}
+	if f.City != "" && !strings.EqualFold(f.City, msg.City) {
+		return false
+	}
+	if f.Platform != "" && !strings.EqualFold(f.Platform, msg.Platform) {
+		return false
+	}
+	return true
+}
+
+// ─── Broker ───────────────────────────────────────────────────────────────────
+
+// Subscription receives matching messages on C. C is closed when the
+// subscriber falls too far behind or is unsubscribed; clients then reconnect
+// with Last-Event-ID and replay from the Broker's history.
+type Subscription struct {
+	C      <-chan Message
+	ch     chan Message
+	filter Filter
+}
+
+// Broker delivers published messages to in-process subscribers and keeps a
+// short history for resuming clients.
+type Broker struct {
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	history []Message
+	size    int
+	nextID  int64
+}
+
+// NewBroker returns a Broker that remembers the last historySize messages.
+func NewBroker(historySize int) *Broker {
+	return &Broker{subs: make(map[*Subscription]struct{}), size: historySize}
+}
+
+// Publish assigns the message an ID and hands it to every matching
+// subscriber without blocking.
+func (b *Broker) Publish(msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	msg.ID = b.nextID
+	if msg.Time.IsZero() {
+		msg.Time = time.Now().UTC()
+	}
+
+	b.history = append(b.history, msg)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+
+	for sub := range b.subs {
+		if !sub.filter.Matches(msg) {
+			continue
+		}
+		select {
+		case sub.ch <- msg:
+		default:
+			// Slow consumer — drop it rather than stall every other client.
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber. Messages newer than lastID that are still
+// in the history are queued first, so a reconnecting client misses nothing
+// that happened while it was away.
+func (b *Broker) Subscribe(f Filter, lastID int64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var backlog []Message
+	if lastID > 0 {
+		for _, msg := range b.history {
+			if msg.ID > lastID && f.Matches(msg) {
+				backlog = append(backlog, msg)
+			}
+		}
+	}
+
+	ch := make(chan Message, len(backlog)+64)
+	for _, msg := range backlog {
+		ch <- msg
+	}
+	sub := &Subscription{C: ch, ch: ch, filter: f}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe removes a subscriber and closes its channel.
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Subscribers returns the number of connected subscribers.
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
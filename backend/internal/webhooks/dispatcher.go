@@ -0,0 +1,228 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Dispatcher drains the webhook_deliveries queue.
+//
+// Due rows are claimed by pushing their next_attempt_at forward by a lease, so
+// several dispatchers can share one database and a crashed one only delays its
+// claimed rows until the lease expires.
+type Dispatcher struct {
+	db           *sql.DB
+	client       *http.Client
+	pollInterval time.Duration
+	lease        time.Duration
+	batchSize    int
+	maxAttempts  int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+}
+
+func NewDispatcher(db *sql.DB) *Dispatcher {
+	return &Dispatcher{
+		db:           db,
+		client:       newClient(),
+		pollInterval: 15 * time.Second,
+		lease:        2 * time.Minute,
+		batchSize:    20,
+		maxAttempts:  8,
+		baseBackoff:  30 * time.Second,
+		maxBackoff:   6 * time.Hour,
+	}
+}
+
+// newClient returns the HTTP client deliveries go out through. It connects
+// directly, never through a proxy, so safeControl sees the endpoint's own
+// address.
+func newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: safeControl}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// safeControl refuses connections to blocked addresses. It runs after DNS
+// resolution, on every connection including redirects, so an endpoint
+// cannot be rebound to an internal address after it was registered.
+func safeControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || blockedIP(ip) {
+		return ErrForbiddenHost
+	}
+	return nil
+}
+
+// Run polls for due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := d.DispatchDue(ctx); err != nil {
+			log.Printf("⚠️  Webhook dispatch failed: %v", err)
+		} else if n > 0 {
+			log.Printf("📨 Dispatched %d webhook deliveries", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+type claimed struct {
+	id       int64
+	kind     string
+	payload  []byte
+	attempts int
+	url      string
+	secret   string
+}
+
+// DispatchDue sends one batch of due deliveries and returns how many it tried.
+// Pending deliveries of inactive webhooks are cancelled rather than sent.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	if _, err := d.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries wd
+		SET status = 'cancelled', last_error = 'webhook is inactive', next_attempt_at = NULL
+		FROM webhooks w
+		WHERE wd.webhook_id = w.id AND NOT w.active AND wd.status = 'pending'
+	`); err != nil {
+		return 0, err
+	}
+
+	rows, err := d.db.QueryContext(ctx, `
+		UPDATE webhook_deliveries wd
+		SET next_attempt_at = now() + $1 * interval '1 second'
+		FROM webhooks w
+		WHERE wd.webhook_id = w.id
+		  AND w.active
+		  AND wd.id IN (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks h ON h.id = d.webhook_id AND h.active
+			WHERE d.status = 'pending' AND d.next_attempt_at <= now()
+			ORDER BY d.next_attempt_at
+			LIMIT $2
+			FOR UPDATE OF d SKIP LOCKED
+		  )
+		RETURNING wd.id, wd.kind, wd.payload, wd.attempts, w.url, w.secret
+	`, int(d.lease.Seconds()), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var batch []claimed
+	for rows.Next() {
+		var c claimed
+		if err := rows.Scan(&c.id, &c.kind, &c.payload, &c.attempts, &c.url, &c.secret); err != nil {
+			continue
+		}
+		batch = append(batch, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, c := range batch {
+		code, sendErr := d.send(ctx, c)
+		d.record(c, code, sendErr)
+	}
+	return len(batch), nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, c claimed) (int, error) {
+	ts := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(c.payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "event-scraper-webhooks/1")
+	req.Header.Set(HeaderEvent, c.kind)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(c.id, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(c.secret, ts, c.payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// The body is drained so the connection can be reused, but never kept:
+	// delivery logs are shown to the webhook's owner.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) record(c claimed, code int, sendErr error) {
+	attempts := c.attempts + 1
+
+	var err error
+	switch {
+	case sendErr == nil:
+		_, err = d.db.Exec(`
+			UPDATE webhook_deliveries
+			SET status = 'succeeded', attempts = $1, last_status_code = $2,
+			    last_error = '', delivered_at = now(), next_attempt_at = NULL
+			WHERE id = $3
+		`, attempts, code, c.id)
+	case attempts >= d.maxAttempts:
+		_, err = d.db.Exec(`
+			UPDATE webhook_deliveries
+			SET status = 'failed', attempts = $1, last_status_code = $2,
+			    last_error = $3, next_attempt_at = NULL
+			WHERE id = $4
+		`, attempts, code, sendErr.Error(), c.id)
+	default:
+		_, err = d.db.Exec(`
+			UPDATE webhook_deliveries
+			SET attempts = $1, last_status_code = $2, last_error = $3,
+			    next_attempt_at = now() + $4 * interval '1 second'
+			WHERE id = $5
+		`, attempts, code, sendErr.Error(), int(d.backoff(attempts).Seconds()), c.id)
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to record webhook delivery %d: %v", c.id, err)
+	}
+}
+
+// backoff returns the wait before the next attempt: base * 2^(attempts-1),
+// capped at maxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.baseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.maxBackoff {
+			return d.maxBackoff
+		}
+	}
+	return wait
+}
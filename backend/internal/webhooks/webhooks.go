@@ -0,0 +1,522 @@
+// Package webhooks delivers signed notifications about new and changed events
+// to endpoints registered by users.
+//
+// The scheduler enqueues one delivery row per matching (webhook, change) pair
+// after every cycle; the Dispatcher drains that queue with exponential backoff.
+// Because the queue lives in Postgres, the scheduler and API server can run as
+// separate processes.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"event-scraper/internal/models"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Request headers sent with every delivery.
+const (
+	HeaderSignature = "X-Event-Scraper-Signature"
+	HeaderTimestamp = "X-Event-Scraper-Timestamp"
+	HeaderDelivery  = "X-Event-Scraper-Delivery"
+	HeaderEvent     = "X-Event-Scraper-Event"
+)
+
+// KindReminderDue is delivered to a user's own webhooks when a reminder for
+// one of their saved events fires. It is opt-in: new webhooks only receive
+// event changes unless they list it explicitly.
+const KindReminderDue = "reminder.due"
+
+var ErrNotFound = errors.New("webhook not found")
+
+// ErrForbiddenHost is returned for webhook URLs that reach this machine or a
+// private network, and by the dispatcher when an endpoint resolves to one.
+var ErrForbiddenHost = errors.New("url must not point at a loopback, private or link-local address")
+
+// Filters narrow down which events a webhook receives. Empty lists match
+// everything; values within one list are OR-ed, lists are AND-ed.
+type Filters struct {
+	Cities     []string `json:"cities,omitempty"`
+	Platforms  []string `json:"platforms,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+	Keywords   []string `json:"keywords,omitempty"`
+}
+
+type Webhook struct {
+	ID        int64     `json:"id"`
+	UserID    string    `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Filters   Filters   `json:"filters"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Delivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        int64           `json:"event_id"`
+	Kind           string          `json:"type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	ReplayOf       *int64          `json:"replay_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// Payload is the JSON body POSTed to a webhook endpoint.
+type Payload struct {
+	Type       string       `json:"type"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Event      models.Event `json:"event"`
+}
+
+// Schema holds the DDL for the webhook tables.
+var Schema = []string{
+	`CREATE TABLE IF NOT EXISTS webhooks (
+		id         SERIAL PRIMARY KEY,
+		user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		url        TEXT NOT NULL,
+		secret     TEXT NOT NULL,
+		events     TEXT[] NOT NULL DEFAULT '{event.created,event.updated}',
+		filters    JSONB NOT NULL DEFAULT '{}',
+		active     BOOLEAN NOT NULL DEFAULT true,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id               BIGSERIAL PRIMARY KEY,
+		webhook_id       INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+		event_id         INTEGER,
+		kind             VARCHAR(50) NOT NULL,
+		payload          JSONB NOT NULL,
+		status           VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts         INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at  TIMESTAMPTZ DEFAULT now(),
+		last_status_code INTEGER NOT NULL DEFAULT 0,
+		last_error       TEXT NOT NULL DEFAULT '',
+		replay_of        BIGINT REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+		created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+		delivered_at     TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks(user_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending'`,
+}
+
+// EnsureSchema creates the webhook tables if they do not exist.
+func EnsureSchema(db *sql.DB) error {
+	for _, q := range Schema {
+		if _, err := db.Exec(q); err != nil {
+			return fmt.Errorf("webhook migration failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// ─── Validation / signing ─────────────────────────────────────────────────────
+
+// Validate normalises a webhook before it is stored.
+func (w *Webhook) Validate() error {
+	w.URL = strings.TrimSpace(w.URL)
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http(s) URL")
+	}
+	if err := checkHost(u.Hostname()); err != nil {
+		return err
+	}
+
+	if len(w.Events) == 0 {
+		w.Events = []string{models.EventCreated, models.EventUpdated}
+	}
+	for _, kind := range w.Events {
+		if kind != models.EventCreated && kind != models.EventUpdated && kind != KindReminderDue {
+			return fmt.Errorf("unsupported event type %q", kind)
+		}
+	}
+
+	w.Filters.Cities = trimAll(w.Filters.Cities)
+	w.Filters.Platforms = trimAll(w.Filters.Platforms)
+	w.Filters.EventTypes = trimAll(w.Filters.EventTypes)
+	w.Filters.Keywords = trimAll(w.Filters.Keywords)
+	return nil
+}
+
+// cgnat is the carrier-grade NAT range, private in practice though not in
+// net.IP.IsPrivate.
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// blockedIP reports whether webhooks may not reach ip: loopback, private,
+// link-local, unspecified and multicast addresses.
+func blockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		cgnat.Contains(ip)
+}
+
+// checkHost rejects a host that is, or resolves to, a blocked address. The
+// dispatcher checks again when it connects (see safeControl), since a name
+// can resolve differently later.
+func checkHost(host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if blockedIP(ip) {
+			return ErrForbiddenHost
+		}
+		return nil
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil || len(ips) == 0 {
+		return fmt.Errorf("url host %q cannot be resolved", host)
+	}
+	for _, ip := range ips {
+		if blockedIP(ip) {
+			return ErrForbiddenHost
+		}
+	}
+	return nil
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the value of the signature header for a delivery body:
+// hex(HMAC-SHA256(secret, timestamp + "." + body)) prefixed with "sha256=".
+// Receivers should recompute it and reject stale timestamps.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Matches reports whether a change should be delivered to this webhook.
+func (w *Webhook) Matches(change models.EventChange) bool {
+	if !w.Active || !containsFold(w.Events, change.Kind) {
+		return false
+	}
+	e := change.Event
+	f := w.Filters
+	if len(f.Cities) > 0 && !containsFold(f.Cities, e.CityNormalized) {
+		return false
+	}
+	if len(f.Platforms) > 0 && !containsFold(f.Platforms, e.Platform) {
+		return false
+	}
+	if len(f.EventTypes) > 0 && !containsFold(f.EventTypes, e.EventType) {
+		return false
+	}
+	if len(f.Keywords) > 0 {
+		text := strings.ToLower(e.EventName + " " + e.Description)
+		found := false
+		for _, kw := range f.Keywords {
+			if strings.Contains(text, strings.ToLower(kw)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ─── Enqueue ──────────────────────────────────────────────────────────────────
+
+// Enqueue records a pending delivery for every active webhook matching each
+// change. Returns the number of deliveries queued.
+func Enqueue(db *sql.DB, changes []models.EventChange) (int, error) {
+	if len(changes) == 0 {
+		return 0, nil
+	}
+
+	hooks, err := listWhere(db, "active = true")
+	if err != nil {
+		return 0, err
+	}
+	if len(hooks) == 0 {
+		return 0, nil
+	}
+
+	queued := 0
+	now := time.Now().UTC()
+	for _, change := range changes {
+		var body []byte
+		for i := range hooks {
+			if !hooks[i].Matches(change) {
+				continue
+			}
+			if body == nil {
+				body, err = json.Marshal(Payload{Type: change.Kind, OccurredAt: now, Event: change.Event})
+				if err != nil {
+					return queued, err
+				}
+			}
+			if _, err := db.Exec(`
+				INSERT INTO webhook_deliveries (webhook_id, event_id, kind, payload)
+				VALUES ($1, $2, $3, $4)
+			`, hooks[i].ID, change.Event.ID, change.Kind, body); err != nil {
+				return queued, fmt.Errorf("enqueue delivery: %w", err)
+			}
+			queued++
+		}
+	}
+	return queued, nil
+}
+
+// EnqueueForUser queues a delivery of an already-encoded payload to every
+// active webhook of one user that subscribes to kind. Event filters are not
+// applied: the payload is about the user, not about a catalogue change.
+func EnqueueForUser(db *sql.DB, userID, kind string, eventID int64, body []byte) (int, error) {
+	res, err := db.Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, kind, payload)
+		SELECT id, NULLIF($3, 0), $2, $4
+		FROM webhooks
+		WHERE user_id = $1 AND active = true AND $2 = ANY(events)
+	`, userID, kind, eventID, body)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue delivery: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// ─── Store ────────────────────────────────────────────────────────────────────
+
+const webhookCols = `id, user_id::text, url, secret, events, filters, active, created_at, updated_at`
+
+func scanWebhook(row interface {
+	Scan(...interface{}) error
+}, w *Webhook) error {
+	var filters []byte
+	if err := row.Scan(
+		&w.ID, &w.UserID, &w.URL, &w.Secret, pq.Array(&w.Events),
+		&filters, &w.Active, &w.CreatedAt, &w.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	return json.Unmarshal(filters, &w.Filters)
+}
+
+func listWhere(db *sql.DB, where string, args ...interface{}) ([]Webhook, error) {
+	rows, err := db.Query(`SELECT `+webhookCols+` FROM webhooks WHERE `+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []Webhook{}
+	for rows.Next() {
+		var w Webhook
+		if err := scanWebhook(rows, &w); err != nil {
+			continue
+		}
+		hooks = append(hooks, w)
+	}
+	return hooks, rows.Err()
+}
+
+// List returns the webhooks owned by a user.
+func List(db *sql.DB, userID string) ([]Webhook, error) {
+	return listWhere(db, "user_id = $1", userID)
+}
+
+// Get returns one webhook owned by a user.
+func Get(db *sql.DB, userID string, id int64) (*Webhook, error) {
+	var w Webhook
+	err := scanWebhook(db.QueryRow(
+		`SELECT `+webhookCols+` FROM webhooks WHERE id = $1 AND user_id = $2`, id, userID,
+	), &w)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// Create stores a new webhook, generating a secret when none was supplied.
+func Create(db *sql.DB, w *Webhook) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	if w.Secret == "" {
+		secret, err := NewSecret()
+		if err != nil {
+			return err
+		}
+		w.Secret = secret
+	}
+	filters, _ := json.Marshal(w.Filters)
+	return db.QueryRow(`
+		INSERT INTO webhooks (user_id, url, secret, events, filters, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`, w.UserID, w.URL, w.Secret, pq.Array(w.Events), filters, w.Active,
+	).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+// Update overwrites the mutable fields of a webhook.
+func Update(db *sql.DB, w *Webhook) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	filters, _ := json.Marshal(w.Filters)
+	res, err := db.Exec(`
+		UPDATE webhooks
+		SET url = $1, secret = $2, events = $3, filters = $4, active = $5, updated_at = now()
+		WHERE id = $6 AND user_id = $7
+	`, w.URL, w.Secret, pq.Array(w.Events), filters, w.Active, w.ID, w.UserID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a webhook and its delivery log.
+func Delete(db *sql.DB, userID string, id int64) error {
+	res, err := db.Exec(`DELETE FROM webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const deliveryCols = `id, webhook_id, COALESCE(event_id, 0), kind, payload, status, attempts,
+	next_attempt_at, last_status_code, last_error, replay_of, created_at, delivered_at`
+
+func scanDelivery(row interface {
+	Scan(...interface{}) error
+}, d *Delivery) error {
+	var next, delivered sql.NullTime
+	var replayOf sql.NullInt64
+	var payload []byte
+	if err := row.Scan(
+		&d.ID, &d.WebhookID, &d.EventID, &d.Kind, &payload, &d.Status, &d.Attempts,
+		&next, &d.LastStatusCode, &d.LastError, &replayOf, &d.CreatedAt, &delivered,
+	); err != nil {
+		return err
+	}
+	d.Payload = payload
+	if next.Valid && d.Status == StatusPending {
+		d.NextAttemptAt = &next.Time
+	}
+	if delivered.Valid {
+		d.DeliveredAt = &delivered.Time
+	}
+	if replayOf.Valid {
+		d.ReplayOf = &replayOf.Int64
+	}
+	return nil
+}
+
+// ListDeliveries returns the most recent deliveries for a webhook, optionally
+// filtered by status.
+func ListDeliveries(db *sql.DB, webhookID int64, status string, limit int) ([]Delivery, error) {
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	rows, err := db.Query(`
+		SELECT `+deliveryCols+`
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`, webhookID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		var d Delivery
+		if err := scanDelivery(rows, &d); err != nil {
+			continue
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Replay queues a fresh copy of an earlier delivery. The original row is kept
+// untouched so the log still shows what happened the first time.
+func Replay(db *sql.DB, webhookID, deliveryID int64) (*Delivery, error) {
+	var d Delivery
+	err := scanDelivery(db.QueryRow(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, kind, payload, replay_of)
+		SELECT webhook_id, event_id, kind, payload, id
+		FROM webhook_deliveries
+		WHERE id = $1 AND webhook_id = $2
+		RETURNING `+deliveryCols,
+		deliveryID, webhookID,
+	), &d)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func trimAll(values []string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func containsFold(values []string, v string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
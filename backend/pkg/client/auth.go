@@ -0,0 +1,18 @@
+// backend/pkg/client/auth.go
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// BearerToken authenticates every request with a token from
+// POST /api/auth/signin:
+//
+//	c, err := client.NewClientWithResponses(baseURL, client.WithRequestEditorFn(client.BearerToken(token)))
+func BearerToken(token string) RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
@@ -0,0 +1,9 @@
+// backend/pkg/client/generate.go
+
+// Package client is a typed Go client for the event-scraper HTTP API,
+// generated from api/openapi.yaml. Regenerate after changing the spec:
+//
+//	go generate ./pkg/client
+package client
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.5.1 -config oapi-codegen.yaml ../../api/openapi.yaml
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -183,3 +185,113 @@ func cleanDateString(s string) string {
 
 	return strings.TrimSpace(s)
 }
+
+// defaultEventHour is assumed when an event lists a date but no start time.
+const defaultEventHour = 9
+
+var clockPattern = regexp.MustCompile(`(?i)\b(\d{1,2})(?:[:.](\d{2}))?\s*([ap])\.?\s?m\b|\b(\d{1,2})[:.](\d{2})\b`)
+
+// ParseClock extracts the first time of day from strings such as "6:30 PM",
+// "18:00", "10 AM - 5 PM" or "7.30pm IST". Returns hour and minute in 24h form.
+func ParseClock(s string) (int, int, bool) {
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+
+	var hour, minute int
+	if m[1] != "" {
+		hour, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			minute, _ = strconv.Atoi(m[2])
+		}
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		pm := strings.EqualFold(m[3], "p")
+		if pm && hour != 12 {
+			hour += 12
+		} else if !pm && hour == 12 {
+			hour = 0
+		}
+	} else {
+		hour, _ = strconv.Atoi(m[4])
+		minute, _ = strconv.Atoi(m[5])
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+// EventLocation is the time zone event wall-clock times are read in.
+func EventLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		return time.FixedZone("IST", 5*60*60+30*60)
+	}
+	return loc
+}
+
+// EventStart combines an event's date and time columns into the instant it
+// starts. Events are in India, so wall-clock times are read as IST. When the
+// time is missing or unparseable the event is assumed to start at 09:00.
+func EventStart(dateStr, timeStr string) (time.Time, bool) {
+	d, ok := ParseDate(dateStr)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	loc := EventLocation()
+	hour, minute, ok := ParseClock(timeStr)
+	if !ok {
+		hour, minute = defaultEventHour, 0
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), hour, minute, 0, 0, loc), true
+}
+
+// defaultEventDuration is assumed when an event lists no end time.
+const defaultEventDuration = 2 * time.Hour
+
+// defaultEventEndHour is assumed for the last day of a multi-day event that
+// lists no end time.
+const defaultEventEndHour = 18
+
+// EventSpan returns when an event starts and ends. The end comes from a
+// date range ("Feb 17 - Feb 20, 2026") and a time range ("10 AM - 5 PM")
+// when they can be read; otherwise a single-day event lasts
+// defaultEventDuration and a multi-day one ends at 18:00 on its last day.
+func EventSpan(dateStr, timeStr string) (start, end time.Time, ok bool) {
+	start, ok = EventStart(dateStr, timeStr)
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+
+	lastDay := start
+	if parts := strings.SplitN(dateStr, " - ", 2); len(parts) == 2 {
+		if d, ok := ParseDate(parts[1]); ok {
+			d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, start.Location())
+			if d.After(start) {
+				lastDay = d
+			}
+		}
+	}
+
+	hour, minute, hasEnd := 0, 0, false
+	if clocks := clockPattern.FindAllString(timeStr, 2); len(clocks) == 2 {
+		hour, minute, hasEnd = ParseClock(clocks[1])
+	}
+	switch {
+	case hasEnd:
+		end = time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), hour, minute, 0, 0, start.Location())
+		if !end.After(start) {
+			end = start.Add(defaultEventDuration)
+		}
+	case !lastDay.Equal(start):
+		end = time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), defaultEventEndHour, 0, 0, 0, start.Location())
+	default:
+		end = start.Add(defaultEventDuration)
+	}
+	return start, end, true
+}
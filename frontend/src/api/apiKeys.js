@@ -0,0 +1,23 @@
+import { apiFetch } from "./client";
+
+export const SCOPES = [
+    { value: "events:read", label: "Read events" },
+    { value: "saved:manage", label: "Manage saved events and collections" },
+    { value: "webhooks:manage", label: "Manage webhooks" },
+    { value: "admin", label: "Admin" },
+];
+
+export function listKeys() {
+    return apiFetch("/api/api-keys", { method: "GET" });
+}
+
+export function createKey({ name, scopes, dailyQuota }) {
+    return apiFetch("/api/api-keys", {
+        method: "POST",
+        body: JSON.stringify({ name, scopes, ...(dailyQuota ? { daily_quota: dailyQuota } : {}) }),
+    });
+}
+
+export function deleteKey(id) {
+    return apiFetch(`/api/api-keys/${id}`, { method: "DELETE" });
+}
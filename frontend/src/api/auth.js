@@ -17,3 +17,93 @@ export function signin({ email, password }) {
 export function me() {
     return apiFetch("/api/auth/me", { method: "GET" });
 }
+
+export function refresh(refreshToken) {
+    return apiFetch("/api/auth/refresh", {
+        method: "POST",
+        body: JSON.stringify({ refresh_token: refreshToken }),
+    });
+}
+
+export function logout(refreshToken) {
+    return apiFetch("/api/auth/logout", {
+        method: "POST",
+        body: JSON.stringify(refreshToken ? { refresh_token: refreshToken } : {}),
+    });
+}
+
+export function logoutAll() {
+    return apiFetch("/api/auth/logout-all", { method: "POST" });
+}
+
+export function sessions() {
+    return apiFetch("/api/auth/sessions", { method: "GET" });
+}
+
+export function verifyEmail(token) {
+    return apiFetch("/api/auth/verify-email", {
+        method: "POST",
+        body: JSON.stringify({ token }),
+    });
+}
+
+export function requestVerification() {
+    return apiFetch("/api/auth/verify-email/request", { method: "POST" });
+}
+
+export function forgotPassword(email) {
+    return apiFetch("/api/auth/forgot-password", {
+        method: "POST",
+        body: JSON.stringify({ email }),
+    });
+}
+
+export function resetPassword({ token, password }) {
+    return apiFetch("/api/auth/reset-password", {
+        method: "POST",
+        body: JSON.stringify({ token, password }),
+    });
+}
+
+export function ssoConfig() {
+    return apiFetch("/api/auth/oidc/config", { method: "GET" });
+}
+
+// The browser navigates here; the API redirects to the identity provider.
+export function ssoLoginURL(redirect = "/") {
+    return `/api/auth/oidc/login?redirect=${encodeURIComponent(redirect)}`;
+}
+
+export function ssoToken(code) {
+    return apiFetch("/api/auth/oidc/token", {
+        method: "POST",
+        body: JSON.stringify({ code }),
+    });
+}
+
+// Changing the email needs currentPassword.
+export function updateMe({ fullName, email, currentPassword }) {
+    return apiFetch("/api/auth/me", {
+        method: "PATCH",
+        body: JSON.stringify({ full_name: fullName, email, current_password: currentPassword }),
+    });
+}
+
+export function changePassword({ currentPassword, newPassword }) {
+    return apiFetch("/api/auth/change-password", {
+        method: "POST",
+        body: JSON.stringify({ current_password: currentPassword, new_password: newPassword }),
+    });
+}
+
+export function exportAccount() {
+    return apiFetch("/api/auth/me/export", { method: "GET" });
+}
+
+// Accounts without a password confirm with their email instead.
+export function deleteAccount({ currentPassword, confirm }) {
+    return apiFetch("/api/auth/me", {
+        method: "DELETE",
+        body: JSON.stringify({ current_password: currentPassword, confirm }),
+    });
+}
@@ -1,7 +1,48 @@
 // Always use relative URLs so Vite proxy forwards /api/* → http://localhost:8080
 // Do NOT use http://localhost:8080 directly — that bypasses the proxy and causes CORS issues.
 
-export async function apiFetch(path, options = {}) {
+export const TOKEN_KEY = "event_token";
+export const REFRESH_KEY = "event_refresh_token";
+
+export function storeTokens(token, refreshToken) {
+    if (token) localStorage.setItem(TOKEN_KEY, token);
+    else localStorage.removeItem(TOKEN_KEY);
+    if (refreshToken) localStorage.setItem(REFRESH_KEY, refreshToken);
+    else if (!token) localStorage.removeItem(REFRESH_KEY);
+}
+
+// Access tokens are short-lived. On a 401 we trade the refresh token for a
+// new pair once and retry; concurrent requests share the one refresh, since
+// a refresh token is single-use and spending it twice ends the session.
+let refreshing = null;
+
+function refreshTokens() {
+    const refreshToken = localStorage.getItem(REFRESH_KEY);
+    if (!refreshToken) return Promise.resolve(false);
+    if (!refreshing) {
+        refreshing = fetch("/api/auth/refresh", {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ refresh_token: refreshToken }),
+        })
+            .then(async (res) => {
+                if (!res.ok) {
+                    storeTokens(null, null);
+                    return false;
+                }
+                const data = await res.json();
+                storeTokens(data.token, data.refresh_token);
+                return true;
+            })
+            .catch(() => false)
+            .finally(() => {
+                refreshing = null;
+            });
+    }
+    return refreshing;
+}
+
+export async function apiFetch(path, options = {}, retried = false) {
     // path must start with "/" e.g. "/api/events"
     const url = path.startsWith("/") ? path : `/${path}`;
 
@@ -10,11 +51,15 @@ export async function apiFetch(path, options = {}) {
         headers.set("Content-Type", "application/json");
     }
 
-    const token = localStorage.getItem("event_token");
+    const token = localStorage.getItem(TOKEN_KEY);
     if (token) headers.set("Authorization", `Bearer ${token}`);
 
     const res = await fetch(url, { ...options, headers });
 
+    if (res.status === 401 && token && !retried && !url.startsWith("/api/auth/refresh")) {
+        if (await refreshTokens()) return apiFetch(path, options, true);
+    }
+
     const contentType = res.headers.get("content-type") || "";
     const isJson = contentType.includes("application/json");
 
@@ -26,12 +71,18 @@ export async function apiFetch(path, options = {}) {
     }
 
     if (!res.ok) {
+        // Errors arrive as {"error": {"code", "message", "request_id"}}.
+        const apiError = data && typeof data === "object" ? data.error : null;
         const message =
-            (data && typeof data === "object" && (data.error || data.message)) ||
+            (apiError && typeof apiError === "object" && apiError.message) ||
+            (typeof apiError === "string" && apiError) ||
+            (data && typeof data === "object" && data.message) ||
             (typeof data === "string" && data) ||
             `Request failed: ${res.status}`;
         const err = new Error(message);
         err.status = res.status;
+        err.code = apiError && apiError.code;
+        err.requestId = apiError && apiError.request_id;
         err.data = data;
         throw err;
     }
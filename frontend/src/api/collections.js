@@ -0,0 +1,75 @@
+import { apiFetch } from "./client";
+
+// Fields left undefined keep their current values; tags replace every tag.
+export function updateSavedEvent(eventId, { notes, tags }) {
+    return apiFetch(`/api/events/${eventId}/save`, {
+        method: "PATCH",
+        body: JSON.stringify({ notes, tags }),
+    });
+}
+
+export function listSaved(tag = "") {
+    const q = tag ? `?tag=${encodeURIComponent(tag)}` : "";
+    return apiFetch(`/api/saved-events${q}`, { method: "GET" });
+}
+
+export function listCollections() {
+    return apiFetch("/api/collections", { method: "GET" });
+}
+
+export function getCollection(id) {
+    return apiFetch(`/api/collections/${id}`, { method: "GET" });
+}
+
+export function createCollection({ name, description }) {
+    return apiFetch("/api/collections", {
+        method: "POST",
+        body: JSON.stringify({ name, description }),
+    });
+}
+
+export function updateCollection(id, { name, description }) {
+    return apiFetch(`/api/collections/${id}`, {
+        method: "PATCH",
+        body: JSON.stringify({ name, description }),
+    });
+}
+
+export function deleteCollection(id) {
+    return apiFetch(`/api/collections/${id}`, { method: "DELETE" });
+}
+
+export function addEvent(id, eventId) {
+    return apiFetch(`/api/collections/${id}/events`, {
+        method: "POST",
+        body: JSON.stringify({ event_id: eventId }),
+    });
+}
+
+export function removeEvent(id, eventId) {
+    return apiFetch(`/api/collections/${id}/events/${eventId}`, { method: "DELETE" });
+}
+
+export function reorder(id, eventIds) {
+    return apiFetch(`/api/collections/${id}/order`, {
+        method: "PUT",
+        body: JSON.stringify({ event_ids: eventIds }),
+    });
+}
+
+// expiresAt is an ISO timestamp, or empty for a link that never expires.
+export function share(id, expiresAt = "") {
+    return apiFetch(`/api/collections/${id}/share`, {
+        method: "POST",
+        body: JSON.stringify(expiresAt ? { expires_at: expiresAt } : {}),
+    });
+}
+
+export function unshare(id) {
+    return apiFetch(`/api/collections/${id}/share`, { method: "DELETE" });
+}
+
+// The public view behind a share link; no sign-in needed.
+export function getShared(token) {
+    return apiFetch(`/api/shared/collections/${encodeURIComponent(token)}`, { method: "GET" });
+}
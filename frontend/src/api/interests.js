@@ -0,0 +1,25 @@
+import { apiFetch } from "./client";
+
+export const FORMATS = ["online", "offline", "hybrid"];
+
+export function getInterests() {
+    return apiFetch("/api/interests", { method: "GET" });
+}
+
+// Fields left undefined keep their current values.
+export function updateInterests({ tech, cities, formats, price }) {
+    return apiFetch("/api/interests", {
+        method: "PUT",
+        body: JSON.stringify({ tech, cities, formats, price }),
+    });
+}
+
+export function forYou(page = 1, limit = 20) {
+    return apiFetch(`/api/events/for-you?page=${page}&limit=${limit}`, { method: "GET" });
+}
+
+// Events saved by people who saved what the caller saved, filled up with
+// events like the caller's recently saved ones.
+export function recommended(limit = 8) {
+    return apiFetch(`/api/events/recommended?limit=${limit}`, { method: "GET" });
+}
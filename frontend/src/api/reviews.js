@@ -0,0 +1,39 @@
+import { apiFetch } from "./client";
+
+// Answers {reviews, summary, total, page, limit, total_pages}; signed in,
+// also mine and can_review.
+export function eventReviews(eventId, { rating = "", page = 1 } = {}) {
+    const q = new URLSearchParams({ page });
+    if (rating) q.set("rating", rating);
+    return apiFetch(`/api/events/${eventId}/reviews?${q}`, { method: "GET" });
+}
+
+export function saveReview(eventId, { rating, body }) {
+    return apiFetch(`/api/events/${eventId}/review`, {
+        method: "PUT",
+        body: JSON.stringify({ rating, body }),
+    });
+}
+
+export function deleteReview(eventId) {
+    return apiFetch(`/api/events/${eventId}/review`, { method: "DELETE" });
+}
+
+export function flagReview(id, reason = "") {
+    return apiFetch(`/api/reviews/${id}/flag`, {
+        method: "POST",
+        body: JSON.stringify({ reason }),
+    });
+}
+
+// Moderators only. status is pending, published or hidden.
+export function moderationQueue(status = "pending", page = 1) {
+    return apiFetch(`/api/admin/reviews?status=${status}&page=${page}`, { method: "GET" });
+}
+
+export function moderate(id, status, reason = "") {
+    return apiFetch(`/api/admin/reviews/${id}`, {
+        method: "PUT",
+        body: JSON.stringify({ status, reason }),
+    });
+}
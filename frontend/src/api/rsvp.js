@@ -0,0 +1,26 @@
+import { apiFetch } from "./client";
+
+export const STATUSES = [
+    { value: "interested", label: "Interested" },
+    { value: "going", label: "Going" },
+    { value: "attended", label: "Attended" },
+    { value: "skipped", label: "Skipped" },
+];
+
+// Answers {saved_event, conflicts}: when marked going, conflicts lists the
+// other going events it overlaps.
+export function setStatus(eventId, status) {
+    return apiFetch(`/api/events/${eventId}/save`, {
+        method: "PATCH",
+        body: JSON.stringify({ status }),
+    });
+}
+
+// from and to are YYYY-MM-DD, both included; empty for the next 30 days.
+export function agenda(from = "", to = "") {
+    const q = new URLSearchParams();
+    if (from) q.set("from", from);
+    if (to) q.set("to", to);
+    const qs = q.toString();
+    return apiFetch(`/api/agenda${qs ? `?${qs}` : ""}`, { method: "GET" });
+}
@@ -1,23 +1,17 @@
 import React, { createContext, useContext, useEffect, useMemo, useState } from "react";
 import * as authApi from "../api/auth";
+import { REFRESH_KEY, TOKEN_KEY, storeTokens } from "../api/client";
 
 const AuthContext = createContext(null);
 
-const TOKEN_KEY = "event_token";
-
 export function AuthProvider({ children }) {
     const [token, setToken] = useState(() => localStorage.getItem(TOKEN_KEY));
     const [user, setUser] = useState(null);
     const [loading, setLoading] = useState(true);
 
-    function setAuth(nextToken, nextUser) {
-        if (nextToken) {
-            localStorage.setItem(TOKEN_KEY, nextToken);
-            setToken(nextToken);
-        } else {
-            localStorage.removeItem(TOKEN_KEY);
-            setToken(null);
-        }
+    function setAuth(nextToken, nextUser, nextRefreshToken) {
+        storeTokens(nextToken, nextRefreshToken);
+        setToken(nextToken || null);
         setUser(nextUser || null);
     }
 
@@ -29,11 +23,11 @@ export function AuthProvider({ children }) {
         }
         try {
             const data = await authApi.me();
+            // apiFetch may have refreshed the access token on the way.
+            setToken(localStorage.getItem(TOKEN_KEY));
             setUser(data.user || data);
         } catch (e) {
-            localStorage.removeItem(TOKEN_KEY);
-            setToken(null);
-            setUser(null);
+            setAuth(null, null);
         } finally {
             setLoading(false);
         }
@@ -45,18 +39,48 @@ export function AuthProvider({ children }) {
 
     async function signup({ fullName, email, password }) {
         const data = await authApi.signup({ fullName, email, password });
-        setAuth(data.token, data.user);
+        setAuth(data.token, data.user, data.refresh_token);
         return data;
     }
 
     async function signin({ email, password }) {
         const data = await authApi.signin({ email, password });
-        setAuth(data.token, data.user);
+        setAuth(data.token, data.user, data.refresh_token);
+        return data;
+    }
+
+    async function signinWithSSO(code) {
+        const data = await authApi.ssoToken(code);
+        setAuth(data.token, data.user, data.refresh_token);
         return data;
     }
 
     function signout() {
+        // Ending the session server-side is best-effort; the tokens are
+        // dropped locally either way.
+        const refreshToken = localStorage.getItem(REFRESH_KEY);
+        authApi.logout(refreshToken).catch(() => {});
+        setAuth(null, null);
+    }
+
+    async function signoutEverywhere() {
+        try {
+            await authApi.logoutAll();
+        } finally {
+            setAuth(null, null);
+        }
+    }
+
+    async function updateProfile(changes) {
+        const data = await authApi.updateMe(changes);
+        setUser(data.user);
+        return data;
+    }
+
+    async function deleteAccount(confirmation) {
+        const data = await authApi.deleteAccount(confirmation);
         setAuth(null, null);
+        return data;
     }
 
     const value = useMemo(
@@ -67,8 +91,12 @@ export function AuthProvider({ children }) {
             isAuthed: !!token,
             signup,
             signin,
+            signinWithSSO,
             signout,
+            signoutEverywhere,
             refreshMe,
+            updateProfile,
+            deleteAccount,
         }),
         [token, user, loading]
     );
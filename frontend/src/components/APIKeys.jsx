@@ -0,0 +1,116 @@
+import { useEffect, useState } from "react";
+import { useAuth } from "../auth/AuthContext";
+import * as keysApi from "../api/apiKeys";
+
+// API keys for scripts and dashboards. A new key is shown once, right
+// after it is created.
+export default function APIKeys() {
+    const { user } = useAuth();
+    const [keys, setKeys] = useState([]);
+    const [name, setName] = useState("");
+    const [scopes, setScopes] = useState(["events:read"]);
+    const [newKey, setNewKey] = useState("");
+    const [error, setError] = useState("");
+
+    const canAdmin = user?.role === "admin" || user?.role === "moderator";
+
+    async function load() {
+        try {
+            const data = await keysApi.listKeys();
+            setKeys(data.api_keys || []);
+        } catch (e) {
+            setError(e.message);
+        }
+    }
+
+    useEffect(() => {
+        load();
+    }, []);
+
+    function toggleScope(scope) {
+        setScopes((s) => (s.includes(scope) ? s.filter((x) => x !== scope) : [...s, scope]));
+    }
+
+    async function create(e) {
+        e.preventDefault();
+        setError("");
+        try {
+            const data = await keysApi.createKey({ name, scopes });
+            setNewKey(data.key);
+            setName("");
+            load();
+        } catch (e) {
+            setError(e.message);
+        }
+    }
+
+    async function remove(id) {
+        setError("");
+        try {
+            await keysApi.deleteKey(id);
+            setKeys((k) => k.filter((key) => key.id !== id));
+        } catch (e) {
+            setError(e.message);
+        }
+    }
+
+    return (
+        <div className="mt-6 rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+            <h2 className="text-lg font-semibold text-black">API keys</h2>
+            <p className="mt-1 text-sm text-black/60">
+                Send a key as the <code>X-API-Key</code> header to call the API from scripts and dashboards.
+            </p>
+
+            {newKey && (
+                <div className="mt-4 rounded-xl bg-green-50 p-3 text-sm text-green-800">
+                    Copy your new key now - it will not be shown again:
+                    <code className="mt-1 block break-all font-mono">{newKey}</code>
+                </div>
+            )}
+            {error && <div className="mt-4 text-sm text-[#92140c]">{error}</div>}
+
+            <form onSubmit={create} className="mt-4 flex flex-wrap items-center gap-3 text-sm">
+                <input
+                    value={name}
+                    onChange={(e) => setName(e.target.value)}
+                    placeholder="Key name, e.g. Team dashboard"
+                    required
+                    className="rounded-xl border border-black/10 px-3 py-2"
+                />
+                {keysApi.SCOPES.filter((s) => s.value !== "admin" || canAdmin).map((s) => (
+                    <label key={s.value} className="flex items-center gap-1 text-black/70">
+                        <input type="checkbox" checked={scopes.includes(s.value)} onChange={() => toggleScope(s.value)} />
+                        {s.label}
+                    </label>
+                ))}
+                <button
+                    type="submit"
+                    disabled={!name || scopes.length === 0}
+                    className="rounded-xl bg-[#92140c] px-4 py-2 font-semibold text-white disabled:opacity-50"
+                >
+                    Create key
+                </button>
+            </form>
+
+            <ul className="mt-4 divide-y divide-black/5 text-sm">
+                {keys.map((k) => (
+                    <li key={k.id} className="flex items-center justify-between py-3">
+                        <div>
+                            <div className="font-semibold text-black">
+                                {k.name} <code className="ml-1 font-mono text-black/50">{k.hint}…</code>
+                            </div>
+                            <div className="text-black/50">
+                                {k.scopes.join(", ")} · {k.used_today}/{k.daily_quota} requests today ·{" "}
+                                {k.last_used_at ? `last used ${new Date(k.last_used_at).toLocaleString()}` : "never used"}
+                            </div>
+                        </div>
+                        <button onClick={() => remove(k.id)} className="font-semibold text-[#92140c] hover:underline">
+                            Delete
+                        </button>
+                    </li>
+                ))}
+                {keys.length === 0 && <li className="py-3 text-black/50">No API keys yet.</li>}
+            </ul>
+        </div>
+    );
+}
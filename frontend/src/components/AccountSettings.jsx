@@ -0,0 +1,158 @@
+import { useState } from "react";
+import { useNavigate } from "react-router-dom";
+import { useAuth } from "../auth/AuthContext";
+import * as authApi from "../api/auth";
+
+const inputClass = "w-full rounded-xl border border-black/10 px-3 py-2";
+const buttonClass = "rounded-xl bg-[#92140c] px-4 py-2 font-semibold text-white disabled:opacity-50";
+
+// Editing the profile, changing the password, downloading one's data and
+// deleting the account. The server asks for the current password again
+// for everything but a name change.
+export default function AccountSettings() {
+    const { user, updateProfile, deleteAccount } = useAuth();
+    const navigate = useNavigate();
+
+    const [fullName, setFullName] = useState(user?.full_name || "");
+    const [email, setEmail] = useState(user?.email || "");
+    const [profilePassword, setProfilePassword] = useState("");
+    const [profileMsg, setProfileMsg] = useState("");
+
+    const [currentPassword, setCurrentPassword] = useState("");
+    const [newPassword, setNewPassword] = useState("");
+    const [passwordMsg, setPasswordMsg] = useState("");
+
+    const [deleteConfirm, setDeleteConfirm] = useState("");
+    const [deleteMsg, setDeleteMsg] = useState("");
+
+    const emailChanged = email.trim().toLowerCase() !== (user?.email || "");
+
+    async function saveProfile(e) {
+        e.preventDefault();
+        setProfileMsg("");
+        try {
+            await updateProfile({
+                fullName,
+                email: emailChanged ? email : undefined,
+                currentPassword: emailChanged ? profilePassword : undefined,
+            });
+            setProfilePassword("");
+            setProfileMsg(emailChanged ? "Saved - check your new inbox for a verification link" : "Saved");
+        } catch (e) {
+            setProfileMsg(e.message);
+        }
+    }
+
+    async function savePassword(e) {
+        e.preventDefault();
+        setPasswordMsg("");
+        try {
+            const data = await authApi.changePassword({ currentPassword, newPassword });
+            setCurrentPassword("");
+            setNewPassword("");
+            setPasswordMsg(data.revoked ? `Password changed - signed out of ${data.revoked} other session(s)` : data.message);
+        } catch (e) {
+            setPasswordMsg(e.message);
+        }
+    }
+
+    async function download() {
+        const data = await authApi.exportAccount();
+        const url = URL.createObjectURL(new Blob([JSON.stringify(data, null, 2)], { type: "application/json" }));
+        const a = document.createElement("a");
+        a.href = url;
+        a.download = `account-export-${new Date().toISOString().slice(0, 10)}.json`;
+        a.click();
+        URL.revokeObjectURL(url);
+    }
+
+    async function remove(e) {
+        e.preventDefault();
+        if (!window.confirm("Delete your account and everything saved with it? This cannot be undone.")) return;
+        setDeleteMsg("");
+        try {
+            // Accounts made through single sign-on have no password and
+            // confirm with their email; the server uses whichever applies.
+            await deleteAccount({ currentPassword: deleteConfirm, confirm: deleteConfirm });
+            navigate("/");
+        } catch (e) {
+            setDeleteMsg(e.message);
+        }
+    }
+
+    return (
+        <div className="mt-6 rounded-2xl border border-black/5 bg-white shadow-sm p-6 text-sm">
+            <h2 className="text-lg font-semibold text-black">Account</h2>
+
+            <form onSubmit={saveProfile} className="mt-4 grid max-w-md gap-3">
+                <input value={fullName} onChange={(e) => setFullName(e.target.value)} placeholder="Full name" required className={inputClass} />
+                <input type="email" value={email} onChange={(e) => setEmail(e.target.value)} placeholder="Email" required className={inputClass} />
+                {emailChanged && (
+                    <input
+                        type="password"
+                        value={profilePassword}
+                        onChange={(e) => setProfilePassword(e.target.value)}
+                        placeholder="Current password"
+                        required
+                        className={inputClass}
+                    />
+                )}
+                <div>
+                    <button type="submit" className={buttonClass}>Save profile</button>
+                    {profileMsg && <span className="ml-3 text-black/60">{profileMsg}</span>}
+                </div>
+            </form>
+
+            <h3 className="mt-8 font-semibold text-black">Change password</h3>
+            <form onSubmit={savePassword} className="mt-3 grid max-w-md gap-3">
+                <input
+                    type="password"
+                    value={currentPassword}
+                    onChange={(e) => setCurrentPassword(e.target.value)}
+                    placeholder="Current password"
+                    required
+                    className={inputClass}
+                />
+                <input
+                    type="password"
+                    value={newPassword}
+                    onChange={(e) => setNewPassword(e.target.value)}
+                    placeholder="New password (at least 6 characters)"
+                    minLength={6}
+                    required
+                    className={inputClass}
+                />
+                <div>
+                    <button type="submit" className={buttonClass}>Change password</button>
+                    {passwordMsg && <span className="ml-3 text-black/60">{passwordMsg}</span>}
+                </div>
+            </form>
+
+            <h3 className="mt-8 font-semibold text-black">Your data</h3>
+            <p className="mt-1 text-black/60">
+                Download your profile, saved events and notes, notifications, webhooks, API keys and sessions as JSON.
+            </p>
+            <button onClick={download} className="mt-3 rounded-xl border border-black/10 bg-white px-4 py-2 font-semibold text-black hover:bg-black/5">
+                Download my data
+            </button>
+
+            <h3 className="mt-8 font-semibold text-[#92140c]">Delete account</h3>
+            <p className="mt-1 text-black/60">
+                Deletes your account and everything saved with it. Enter your password, or your email if you only sign in
+                with single sign-on.
+            </p>
+            <form onSubmit={remove} className="mt-3 flex max-w-md gap-3">
+                <input
+                    type="password"
+                    value={deleteConfirm}
+                    onChange={(e) => setDeleteConfirm(e.target.value)}
+                    placeholder="Password or email"
+                    required
+                    className={inputClass}
+                />
+                <button type="submit" className={buttonClass}>Delete</button>
+            </form>
+            {deleteMsg && <div className="mt-2 text-[#92140c]">{deleteMsg}</div>}
+        </div>
+    );
+}
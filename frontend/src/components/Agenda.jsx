@@ -0,0 +1,88 @@
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import * as rsvpApi from "../api/rsvp";
+
+const timeFormat = { hour: "2-digit", minute: "2-digit", timeZone: "Asia/Kolkata" };
+const dayFormat = { weekday: "short", day: "numeric", month: "short", timeZone: "Asia/Kolkata" };
+
+// The saved events of a date range, day by day, with overlapping "going"
+// events flagged. version changes whenever a status changes elsewhere.
+export default function Agenda({ version = 0 }) {
+    const [from, setFrom] = useState("");
+    const [to, setTo] = useState("");
+    const [data, setData] = useState(null);
+    const [error, setError] = useState("");
+
+    useEffect(() => {
+        setError("");
+        rsvpApi
+            .agenda(from, to)
+            .then(setData)
+            .catch((e) => setError(e.message));
+    }, [from, to, version]);
+
+    const names = Object.fromEntries((data?.entries || []).map((e) => [e.event_id, e.event?.event_name]));
+    const days = [];
+    for (const entry of data?.entries || []) {
+        const day = new Date(entry.start).toLocaleDateString(undefined, dayFormat);
+        if (days.length === 0 || days[days.length - 1].day !== day) days.push({ day, entries: [] });
+        days[days.length - 1].entries.push(entry);
+    }
+
+    return (
+        <div className="mt-10 rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+            <h2 className="text-lg font-semibold text-black">Agenda</h2>
+            <p className="mt-1 text-sm text-black/60">
+                Your saved events by day. Events you're going to that overlap are flagged.
+            </p>
+
+            <div className="mt-4 flex flex-wrap items-center gap-3 text-sm text-black/70">
+                <label className="flex items-center gap-2">
+                    From
+                    <input type="date" value={from} onChange={(e) => setFrom(e.target.value)} className="rounded-xl border border-black/10 px-3 py-1" />
+                </label>
+                <label className="flex items-center gap-2">
+                    To
+                    <input type="date" value={to} onChange={(e) => setTo(e.target.value)} className="rounded-xl border border-black/10 px-3 py-1" />
+                </label>
+                {data && (
+                    <span className="text-black/50">
+                        {data.from} – {data.to}
+                        {data.conflicts > 0 && <span className="ml-2 font-semibold text-[#92140c]">{data.conflicts} clashing</span>}
+                    </span>
+                )}
+            </div>
+            {error && <div className="mt-4 text-sm text-[#92140c]">{error}</div>}
+
+            {data && days.length === 0 && <p className="mt-4 text-sm text-black/50">Nothing saved in this range.</p>}
+            {days.map(({ day, entries }) => (
+                <div key={day} className="mt-4">
+                    <div className="text-xs font-semibold uppercase tracking-wide text-black/50">{day}</div>
+                    <ul className="mt-1 divide-y divide-black/5 text-sm">
+                        {entries.map((e) => (
+                            <li key={e.event_id} className="flex items-start justify-between gap-3 py-2">
+                                <div className="min-w-0">
+                                    <Link to={`/events/${e.event_id}`} className="font-semibold text-black hover:underline">
+                                        {e.event?.event_name}
+                                    </Link>
+                                    <div className="text-black/50">
+                                        {new Date(e.start).toLocaleTimeString(undefined, timeFormat)} –{" "}
+                                        {new Date(e.end).toLocaleTimeString(undefined, timeFormat)}
+                                    </div>
+                                    {e.conflicts.length > 0 && (
+                                        <div className="text-[#92140c]">
+                                            Overlaps {e.conflicts.map((id) => names[id] || `event ${id}`).join(", ")}
+                                        </div>
+                                    )}
+                                </div>
+                                <span className={`shrink-0 rounded-full px-2 py-0.5 text-xs ${e.status === "going" ? "bg-[#92140c] text-white" : "bg-black/5 text-black/60"}`}>
+                                    {rsvpApi.STATUSES.find((s) => s.value === e.status)?.label || e.status}
+                                </span>
+                            </li>
+                        ))}
+                    </ul>
+                </div>
+            ))}
+        </div>
+    );
+}
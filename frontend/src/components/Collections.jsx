@@ -0,0 +1,296 @@
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import * as collectionsApi from "../api/collections";
+
+// Named, ordered lists of saved events, each with an optional read-only
+// share link. savedEvents are the caller's saved events to pick from.
+export default function Collections({ savedEvents }) {
+    const [collections, setCollections] = useState([]);
+    const [open, setOpen] = useState(null); // { collection, saved_events }
+    const [name, setName] = useState("");
+    const [description, setDescription] = useState("");
+    const [renaming, setRenaming] = useState(null); // { name, description }
+    const [adding, setAdding] = useState("");
+    const [expiresOn, setExpiresOn] = useState("");
+    const [shareURL, setShareURL] = useState("");
+    const [error, setError] = useState("");
+
+    async function load() {
+        try {
+            const data = await collectionsApi.listCollections();
+            setCollections(data.collections || []);
+        } catch (e) {
+            setError(e.message);
+        }
+    }
+
+    useEffect(() => {
+        load();
+    }, []);
+
+    // run calls the API and shows whatever error it returns.
+    async function run(fn) {
+        setError("");
+        try {
+            return await fn();
+        } catch (e) {
+            setError(e.message);
+            return null;
+        }
+    }
+
+    // show opens a collection, or refreshes the open one, and keeps the
+    // list's summary of it current.
+    function show(data) {
+        if (!data) return;
+        setOpen(data);
+        setCollections((cs) => cs.map((c) => (c.id === data.collection.id ? data.collection : c)));
+    }
+
+    async function create(e) {
+        e.preventDefault();
+        const data = await run(() => collectionsApi.createCollection({ name, description }));
+        if (data) {
+            setName("");
+            setDescription("");
+            setCollections((cs) => [...cs, data.collection].sort((a, b) => a.name.localeCompare(b.name)));
+            show({ collection: data.collection, saved_events: [] });
+        }
+    }
+
+    async function openCollection(id) {
+        setShareURL("");
+        setRenaming(null);
+        show(await run(() => collectionsApi.getCollection(id)));
+    }
+
+    async function rename(e) {
+        e.preventDefault();
+        show(await run(() => collectionsApi.updateCollection(open.collection.id, renaming)));
+        setRenaming(null);
+    }
+
+    async function remove(id) {
+        if (!window.confirm("Delete this collection? Its events stay saved.")) return;
+        const data = await run(() => collectionsApi.deleteCollection(id));
+        if (data) {
+            setCollections((cs) => cs.filter((c) => c.id !== id));
+            if (open?.collection.id === id) setOpen(null);
+        }
+    }
+
+    async function addEvent(e) {
+        e.preventDefault();
+        if (!adding) return;
+        show(await run(() => collectionsApi.addEvent(open.collection.id, Number(adding))));
+        setAdding("");
+    }
+
+    async function removeEvent(eventId) {
+        show(await run(() => collectionsApi.removeEvent(open.collection.id, eventId)));
+    }
+
+    async function move(index, by) {
+        const ids = open.saved_events.map((se) => se.event_id);
+        [ids[index], ids[index + by]] = [ids[index + by], ids[index]];
+        show(await run(() => collectionsApi.reorder(open.collection.id, ids)));
+    }
+
+    async function share(e) {
+        e.preventDefault();
+        // A date means "until the end of that day", local time.
+        const expiresAt = expiresOn ? new Date(`${expiresOn}T23:59:59`).toISOString() : "";
+        const data = await run(() => collectionsApi.share(open.collection.id, expiresAt));
+        if (data) {
+            setShareURL(data.url);
+            show({ ...open, collection: data.collection });
+        }
+    }
+
+    async function unshare() {
+        const data = await run(() => collectionsApi.unshare(open.collection.id));
+        if (data) {
+            setShareURL("");
+            show({ ...open, collection: data.collection });
+        }
+    }
+
+    const inOpen = new Set((open?.saved_events || []).map((se) => se.event_id));
+    const link = shareURL || (open?.collection.share ? `${window.location.origin}/shared/${open.collection.share.token}` : "");
+
+    return (
+        <div className="mt-10 rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+            <h2 className="text-lg font-semibold text-black">Collections</h2>
+            <p className="mt-1 text-sm text-black/60">
+                Group saved events into lists, put them in order and share a read-only link.
+            </p>
+            {error && <div className="mt-4 text-sm text-[#92140c]">{error}</div>}
+
+            <form onSubmit={create} className="mt-4 flex flex-wrap items-center gap-3 text-sm">
+                <input
+                    value={name}
+                    onChange={(e) => setName(e.target.value)}
+                    placeholder="Name, e.g. Conference season"
+                    maxLength={100}
+                    required
+                    className="rounded-xl border border-black/10 px-3 py-2"
+                />
+                <input
+                    value={description}
+                    onChange={(e) => setDescription(e.target.value)}
+                    placeholder="Description (optional)"
+                    maxLength={2000}
+                    className="flex-1 rounded-xl border border-black/10 px-3 py-2"
+                />
+                <button
+                    type="submit"
+                    disabled={!name}
+                    className="rounded-xl bg-[#92140c] px-4 py-2 font-semibold text-white disabled:opacity-50"
+                >
+                    Create
+                </button>
+            </form>
+
+            <ul className="mt-4 divide-y divide-black/5 text-sm">
+                {collections.map((c) => (
+                    <li key={c.id} className="flex items-center justify-between py-3">
+                        <button onClick={() => openCollection(c.id)} className="text-left">
+                            <div className={`font-semibold ${open?.collection.id === c.id ? "text-[#92140c]" : "text-black"}`}>
+                                {c.name}
+                            </div>
+                            <div className="text-black/50">
+                                {c.event_count} event{c.event_count !== 1 ? "s" : ""}
+                                {c.share ? " · shared" : ""}
+                            </div>
+                        </button>
+                        <button onClick={() => remove(c.id)} className="font-semibold text-[#92140c] hover:underline">
+                            Delete
+                        </button>
+                    </li>
+                ))}
+                {collections.length === 0 && <li className="py-3 text-black/50">No collections yet.</li>}
+            </ul>
+
+            {open && (
+                <div className="mt-4 rounded-xl border border-black/10 p-4 text-sm">
+                    {renaming ? (
+                        <form onSubmit={rename} className="flex flex-col gap-2">
+                            <input
+                                value={renaming.name}
+                                onChange={(e) => setRenaming({ ...renaming, name: e.target.value })}
+                                maxLength={100}
+                                required
+                                className="rounded-xl border border-black/10 px-3 py-2"
+                            />
+                            <textarea
+                                value={renaming.description}
+                                onChange={(e) => setRenaming({ ...renaming, description: e.target.value })}
+                                maxLength={2000}
+                                rows={2}
+                                className="rounded-xl border border-black/10 px-3 py-2"
+                            />
+                            <div className="flex gap-3">
+                                <button type="submit" className="font-semibold text-[#92140c] hover:underline">Save</button>
+                                <button type="button" onClick={() => setRenaming(null)} className="text-black/60 hover:underline">
+                                    Cancel
+                                </button>
+                            </div>
+                        </form>
+                    ) : (
+                        <div className="flex items-start justify-between gap-3">
+                            <div>
+                                <div className="text-base font-semibold text-black">{open.collection.name}</div>
+                                {open.collection.description && (
+                                    <p className="mt-1 text-black/60">{open.collection.description}</p>
+                                )}
+                            </div>
+                            <button
+                                onClick={() => setRenaming({ name: open.collection.name, description: open.collection.description })}
+                                className="font-semibold text-[#92140c] hover:underline"
+                            >
+                                Edit
+                            </button>
+                        </div>
+                    )}
+
+                    <ol className="mt-3 divide-y divide-black/5">
+                        {open.saved_events.map((se, i) => (
+                            <li key={se.id} className="flex items-center justify-between gap-3 py-2">
+                                <Link to={`/events/${se.event_id}`} className="min-w-0 truncate text-black hover:underline">
+                                    {i + 1}. {se.event?.event_name}
+                                </Link>
+                                <div className="flex shrink-0 gap-2 text-black/60">
+                                    <button onClick={() => move(i, -1)} disabled={i === 0} title="Move up" className="disabled:opacity-30">↑</button>
+                                    <button
+                                        onClick={() => move(i, 1)}
+                                        disabled={i === open.saved_events.length - 1}
+                                        title="Move down"
+                                        className="disabled:opacity-30"
+                                    >
+                                        ↓
+                                    </button>
+                                    <button onClick={() => removeEvent(se.event_id)} className="text-[#92140c] hover:underline">
+                                        Remove
+                                    </button>
+                                </div>
+                            </li>
+                        ))}
+                        {open.saved_events.length === 0 && <li className="py-2 text-black/50">No events in this collection yet.</li>}
+                    </ol>
+
+                    <form onSubmit={addEvent} className="mt-3 flex gap-3">
+                        <select
+                            value={adding}
+                            onChange={(e) => setAdding(e.target.value)}
+                            className="min-w-0 flex-1 rounded-xl border border-black/10 px-3 py-2"
+                        >
+                            <option value="">Add a saved event…</option>
+                            {savedEvents.filter((se) => !inOpen.has(se.event_id)).map((se) => (
+                                <option key={se.id} value={se.event_id}>{se.event?.event_name}</option>
+                            ))}
+                        </select>
+                        <button type="submit" disabled={!adding} className="rounded-xl bg-[#92140c] px-4 py-2 font-semibold text-white disabled:opacity-50">
+                            Add
+                        </button>
+                    </form>
+
+                    <div className="mt-4 border-t border-black/5 pt-3">
+                        {link ? (
+                            <div className="flex flex-col gap-2">
+                                <div className="text-black/60">
+                                    Anyone with this link can see the collection
+                                    {open.collection.share?.expires_at
+                                        ? ` until ${new Date(open.collection.share.expires_at).toLocaleDateString()}`
+                                        : ""}
+                                    , but not your notes or tags:
+                                </div>
+                                <code className="break-all font-mono text-black">{link}</code>
+                                <div className="flex gap-3">
+                                    <button onClick={() => navigator.clipboard?.writeText(link)} className="font-semibold text-[#92140c] hover:underline">
+                                        Copy link
+                                    </button>
+                                    <button onClick={unshare} className="text-black/60 hover:underline">Stop sharing</button>
+                                </div>
+                            </div>
+                        ) : (
+                            <form onSubmit={share} className="flex flex-wrap items-center gap-3">
+                                <label className="flex items-center gap-2 text-black/70">
+                                    Expires
+                                    <input
+                                        type="date"
+                                        value={expiresOn}
+                                        onChange={(e) => setExpiresOn(e.target.value)}
+                                        className="rounded-xl border border-black/10 px-3 py-1"
+                                    />
+                                </label>
+                                <button type="submit" className="font-semibold text-[#92140c] hover:underline">
+                                    Create share link
+                                </button>
+                            </form>
+                        )}
+                    </div>
+                </div>
+            )}
+        </div>
+    );
+}
@@ -142,7 +142,10 @@ export default function Header() {
                     <nav style={{ display: "flex", alignItems: "center", gap: 36 }}>
                         {navLink("/welcome", "Home")}
                         {isAuthed && navLink("/events", "Browse Events")}
+                        {isAuthed && navLink("/for-you", "For You")}
                         {isAuthed && navLink("/saved", "Saved")}
+                        {user?.role === "admin" && navLink("/admin/scraper-health", "Scraper Health")}
+                        {(user?.role === "admin" || user?.role === "moderator") && navLink("/admin/reviews", "Reviews")}
                     </nav>
 
                     {/* Auth area */}
@@ -0,0 +1,97 @@
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import * as interestsApi from "../api/interests";
+
+const inputClass = "w-full rounded-xl border border-black/10 px-3 py-2";
+
+function splitList(text) {
+    return text.split(",").map((v) => v.trim()).filter(Boolean);
+}
+
+// The interests behind the "For you" feed.
+export default function Interests() {
+    const [tech, setTech] = useState("");
+    const [cities, setCities] = useState("");
+    const [formats, setFormats] = useState([]);
+    const [price, setPrice] = useState("");
+    const [message, setMessage] = useState("");
+
+    function show(interests) {
+        setTech(interests.tech.join(", "));
+        setCities(interests.cities.join(", "));
+        setFormats(interests.formats);
+        setPrice(interests.price);
+    }
+
+    useEffect(() => {
+        interestsApi
+            .getInterests()
+            .then((data) => show(data.interests))
+            .catch((e) => setMessage(e.message));
+    }, []);
+
+    function toggleFormat(format) {
+        setFormats((f) => (f.includes(format) ? f.filter((x) => x !== format) : [...f, format]));
+    }
+
+    async function save(e) {
+        e.preventDefault();
+        setMessage("");
+        try {
+            const data = await interestsApi.updateInterests({
+                tech: splitList(tech),
+                cities: splitList(cities),
+                formats,
+                price,
+            });
+            show(data.interests);
+            setMessage("Saved");
+        } catch (e) {
+            setMessage(e.message);
+        }
+    }
+
+    return (
+        <div className="mt-6 rounded-2xl border border-black/5 bg-white shadow-sm p-6 text-sm">
+            <h2 className="text-lg font-semibold text-black">Interests</h2>
+            <p className="mt-1 text-black/60">
+                These rank your <Link to="/for-you" className="font-semibold text-[#92140c] hover:underline">For you</Link>{" "}
+                feed, together with the events you save.
+            </p>
+
+            <form onSubmit={save} className="mt-4 grid max-w-md gap-3">
+                <label className="text-black/70">
+                    Technologies
+                    <input value={tech} onChange={(e) => setTech(e.target.value)} placeholder="go, rust, kubernetes" className={inputClass} />
+                </label>
+                <label className="text-black/70">
+                    Cities
+                    <input value={cities} onChange={(e) => setCities(e.target.value)} placeholder="Bangalore, Hyderabad" className={inputClass} />
+                </label>
+                <div className="flex flex-wrap items-center gap-3 text-black/70">
+                    Formats
+                    {interestsApi.FORMATS.map((f) => (
+                        <label key={f} className="flex items-center gap-1">
+                            <input type="checkbox" checked={formats.includes(f)} onChange={() => toggleFormat(f)} />
+                            {f}
+                        </label>
+                    ))}
+                </div>
+                <label className="text-black/70">
+                    Price
+                    <select value={price} onChange={(e) => setPrice(e.target.value)} className={inputClass}>
+                        <option value="">Free or paid</option>
+                        <option value="free">Free only</option>
+                        <option value="paid">Paid</option>
+                    </select>
+                </label>
+                <div>
+                    <button type="submit" className="rounded-xl bg-[#92140c] px-4 py-2 font-semibold text-white">
+                        Save interests
+                    </button>
+                    {message && <span className="ml-3 text-black/60">{message}</span>}
+                </div>
+            </form>
+        </div>
+    );
+}
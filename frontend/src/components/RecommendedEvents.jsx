@@ -99,6 +99,23 @@ function RecommendedCard({ event }) {
                     </span>
                 </div>
 
+                {/* Why it was recommended: shared tech and topic come first */}
+                {event.popularity ? (
+                    <p className="text-xs" style={{ color: "#1e1e24", opacity: 0.6 }}>
+                        {event.popularity.views} views · {event.popularity.saves} saves this month
+                    </p>
+                ) : event.source === "saved" ? (
+                    <p className="text-xs" style={{ color: "#1e1e24", opacity: 0.6 }}>
+                        Saved by {event.savers} people who saved this one
+                    </p>
+                ) : event.reasons?.length > 0 && (
+                    <ul className="space-y-0.5 text-xs" style={{ color: "#1e1e24", opacity: 0.6 }}>
+                        {event.reasons.slice(0, 2).map((r) => (
+                            <li key={r.kind}>{r.text}</li>
+                        ))}
+                    </ul>
+                )}
+
                 {/* CTA */}
                 <button
                     className="mt-auto flex items-center justify-center gap-2 py-2.5 rounded-xl text-sm font-medium"
@@ -124,7 +141,26 @@ function RecommendedCard({ event }) {
 }
 
 // ── Main export ───────────────────────────────────────────────────────────
-export default function RecommendedEvents({ eventId, limit = 4 }) {
+// list is "recommended" (events like this one), "also-saved" (events saved
+// together with it, without the content-based fill-up, which the
+// "recommended" list already shows) or "trending" (the most popular upcoming
+// events, in city when given; no eventId needed).
+const LISTS = {
+    "recommended": { eyebrow: "MORE LIKE THIS", title: "Recommended Events" },
+    "also-saved": { eyebrow: "PEOPLE ALSO SAVED", title: "Saved Together", source: "saved" },
+    "trending": { eyebrow: "TRENDING", title: "Popular Right Now" },
+};
+
+function listURL(list, eventId, limit, city) {
+    if (list !== "trending") return `${API_BASE_URL}/api/events/${eventId}/${list}`;
+    const params = new URLSearchParams({ limit: String(limit) });
+    if (city) params.append("city", city);
+    return `${API_BASE_URL}/api/events/trending?${params}`;
+}
+
+export default function RecommendedEvents({ eventId, limit = 4, list = "recommended", city = "" }) {
+    const { eyebrow, source } = LISTS[list];
+    const title = list === "trending" && city ? `Popular in ${city}` : LISTS[list].title;
     const [events, setEvents] = useState([]);
     const [loading, setLoading] = useState(true);
 
@@ -132,10 +168,10 @@ export default function RecommendedEvents({ eventId, limit = 4 }) {
         setLoading(true);
         (async () => {
             try {
-                const res = await fetch(`${API_BASE_URL}/api/events/${eventId}/recommended`);
+                const res = await fetch(listURL(list, eventId, limit, city));
                 if (res.ok) {
                     const data = await res.json();
-                    setEvents(data.events || []);
+                    setEvents((data.events || []).filter((e) => !source || e.source === source));
                 }
             } catch (err) {
                 console.error("Error fetching recommended events:", err);
@@ -143,7 +179,7 @@ export default function RecommendedEvents({ eventId, limit = 4 }) {
                 setLoading(false);
             }
         })();
-    }, [eventId]);
+    }, [eventId, list, source, limit, city]);
 
     if (loading) return (
         <div className="flex justify-center py-12">
@@ -158,13 +194,13 @@ export default function RecommendedEvents({ eventId, limit = 4 }) {
         <div className="mt-12">
             <div className="mb-6">
                 <p className="text-xs font-medium tracking-[0.2em] mb-1"
-                    style={{ color: "#92140c", opacity: 0.8 }}>MORE LIKE THIS</p>
+                    style={{ color: "#92140c", opacity: 0.8 }}>{eyebrow}</p>
                 <h2 style={{
                     fontFamily: "'Cormorant Garamond', serif",
                     fontSize: "1.8rem", fontWeight: 500,
                     color: "#1e1e24", letterSpacing: "-0.02em",
                 }}>
-                    Recommended Events
+                    {title}
                 </h2>
                 <div style={{ width: 40, height: 1, background: "#92140c", marginTop: 8, opacity: 0.3 }} />
             </div>
@@ -0,0 +1,218 @@
+import { useEffect, useState } from "react";
+import { useAuth } from "../auth/AuthContext";
+import * as reviewsApi from "../api/reviews";
+
+export function Stars({ rating }) {
+    const full = Math.round(rating);
+    return (
+        <span className="text-[#92140c]" aria-label={`${rating} out of 5`}>
+            {"★".repeat(full)}
+            <span className="text-black/20">{"★".repeat(5 - full)}</span>
+        </span>
+    );
+}
+
+const statusNote = {
+    pending: "Waiting for a moderator before it is shown to others.",
+    hidden: "Hidden by a moderator.",
+};
+
+// Ratings and reviews of one event. People who were there can rate it and
+// write a short review; anyone signed in can flag a review.
+export default function Reviews({ eventId }) {
+    const { user } = useAuth();
+    const [data, setData] = useState(null);
+    const [rating, setRating] = useState("");
+    const [page, setPage] = useState(1);
+    const [draft, setDraft] = useState(null); // { rating, body }
+    const [flagged, setFlagged] = useState({});
+    const [error, setError] = useState("");
+
+    async function load() {
+        try {
+            setData(await reviewsApi.eventReviews(eventId, { rating, page }));
+        } catch (e) {
+            setError(e.message);
+        }
+    }
+
+    useEffect(() => {
+        load();
+    }, [eventId, rating, page]);
+
+    async function run(fn) {
+        setError("");
+        try {
+            return await fn();
+        } catch (e) {
+            setError(e.message);
+            return null;
+        }
+    }
+
+    async function save(e) {
+        e.preventDefault();
+        if (await run(() => reviewsApi.saveReview(eventId, draft))) {
+            setDraft(null);
+            load();
+        }
+    }
+
+    async function remove() {
+        if (!window.confirm("Delete your review?")) return;
+        if (await run(() => reviewsApi.deleteReview(eventId))) load();
+    }
+
+    async function flag(id) {
+        const reason = window.prompt("What is wrong with this review? (optional)");
+        if (reason === null) return;
+        if (await run(() => reviewsApi.flagReview(id, reason))) setFlagged({ ...flagged, [id]: true });
+    }
+
+    if (!data) return null;
+    const { summary, mine } = data;
+
+    return (
+        <div className="mt-10 rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+            <div className="flex flex-wrap items-baseline justify-between gap-3">
+                <h2 className="text-lg font-semibold text-black">Reviews</h2>
+                {summary.count > 0 && (
+                    <div className="text-sm text-black/60">
+                        <Stars rating={summary.average} /> {summary.average.toFixed(1)} from {summary.count} review
+                        {summary.count !== 1 ? "s" : ""}
+                    </div>
+                )}
+            </div>
+            {error && <div className="mt-4 text-sm text-[#92140c]">{error}</div>}
+
+            {summary.count > 0 && (
+                <div className="mt-4 flex flex-wrap gap-2 text-sm">
+                    {["", 5, 4, 3, 2, 1].map((r) => (
+                        <button
+                            key={r}
+                            onClick={() => {
+                                setRating(r);
+                                setPage(1);
+                            }}
+                            className={`rounded-full border px-3 py-1 ${
+                                rating === r ? "border-[#92140c] text-[#92140c]" : "border-black/10 text-black/60"
+                            }`}
+                        >
+                            {r ? `${r}★ (${summary.distribution[r - 1]})` : "All"}
+                        </button>
+                    ))}
+                </div>
+            )}
+
+            {draft ? (
+                <form onSubmit={save} className="mt-4 flex flex-col gap-2 text-sm">
+                    <div className="flex gap-1 text-2xl">
+                        {[1, 2, 3, 4, 5].map((r) => (
+                            <button
+                                key={r}
+                                type="button"
+                                onClick={() => setDraft({ ...draft, rating: r })}
+                                className={r <= draft.rating ? "text-[#92140c]" : "text-black/20"}
+                                aria-label={`${r} out of 5`}
+                            >
+                                ★
+                            </button>
+                        ))}
+                    </div>
+                    <textarea
+                        value={draft.body}
+                        onChange={(e) => setDraft({ ...draft, body: e.target.value })}
+                        placeholder="How was it? (optional)"
+                        maxLength={2000}
+                        rows={3}
+                        className="rounded-xl border border-black/10 px-3 py-2"
+                    />
+                    <div className="flex gap-3">
+                        <button
+                            type="submit"
+                            disabled={!draft.rating}
+                            className="rounded-xl bg-[#92140c] px-4 py-2 font-semibold text-white disabled:opacity-50"
+                        >
+                            Post review
+                        </button>
+                        <button type="button" onClick={() => setDraft(null)} className="text-black/60 hover:underline">
+                            Cancel
+                        </button>
+                    </div>
+                </form>
+            ) : mine ? (
+                <div className="mt-4 rounded-xl border border-black/10 p-4 text-sm">
+                    <div className="flex items-center justify-between gap-3">
+                        <div className="font-semibold text-black">
+                            Your review <Stars rating={mine.rating} />
+                        </div>
+                        <div className="flex gap-3">
+                            <button
+                                onClick={() => setDraft({ rating: mine.rating, body: mine.body })}
+                                className="font-semibold text-[#92140c] hover:underline"
+                            >
+                                Edit
+                            </button>
+                            <button onClick={remove} className="text-black/60 hover:underline">Delete</button>
+                        </div>
+                    </div>
+                    {mine.body && <p className="mt-2 whitespace-pre-line text-black/80">{mine.body}</p>}
+                    {statusNote[mine.status] && (
+                        <p className="mt-2 text-black/50">
+                            {statusNote[mine.status]}
+                            {mine.moderation_reason ? ` (${mine.moderation_reason})` : ""}
+                        </p>
+                    )}
+                </div>
+            ) : (
+                data.can_review && (
+                    <button
+                        onClick={() => setDraft({ rating: 0, body: "" })}
+                        className="mt-4 rounded-xl bg-[#92140c] px-4 py-2 text-sm font-semibold text-white"
+                    >
+                        Rate this event
+                    </button>
+                )
+            )}
+
+            <ul className="mt-4 divide-y divide-black/5 text-sm">
+                {data.reviews.map((r) => (
+                    <li key={r.id} className="py-3">
+                        <div className="flex items-center justify-between gap-3">
+                            <div>
+                                <Stars rating={r.rating} />{" "}
+                                <span className="font-semibold text-black">{r.author_name}</span>{" "}
+                                <span className="text-black/40">{new Date(r.created_at).toLocaleDateString()}</span>
+                            </div>
+                            {user && r.id !== mine?.id && (
+                                flagged[r.id] ? (
+                                    <span className="text-black/40">Reported</span>
+                                ) : (
+                                    <button onClick={() => flag(r.id)} className="text-black/40 hover:underline">
+                                        Report
+                                    </button>
+                                )
+                            )}
+                        </div>
+                        {r.body && <p className="mt-1 whitespace-pre-line text-black/80">{r.body}</p>}
+                    </li>
+                ))}
+                {data.reviews.length === 0 && <li className="py-3 text-black/50">No reviews yet.</li>}
+            </ul>
+
+            {data.total_pages > 1 && (
+                <div className="mt-3 flex items-center gap-3 text-sm text-black/60">
+                    <button onClick={() => setPage(page - 1)} disabled={page === 1} className="disabled:opacity-30">
+                        ← Newer
+                    </button>
+                    <span>
+                        Page {page} of {data.total_pages}
+                    </span>
+                    <button onClick={() => setPage(page + 1)} disabled={page === data.total_pages} className="disabled:opacity-30">
+                        Older →
+                    </button>
+                </div>
+            )}
+        </div>
+    );
+}
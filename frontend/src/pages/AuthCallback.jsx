@@ -0,0 +1,46 @@
+import React, { useEffect, useRef, useState } from "react";
+import { Link, useNavigate, useSearchParams } from "react-router-dom";
+import Header from "../components/Header";
+import { useAuth } from "../auth/AuthContext";
+
+// Where the API sends the browser back after single sign-on, with a
+// one-time code to trade for tokens or an error to show.
+export default function AuthCallback() {
+    const [params] = useSearchParams();
+    const navigate = useNavigate();
+    const { signinWithSSO } = useAuth();
+    const [err, setErr] = useState(params.get("error") || "");
+    const sent = useRef(false);
+
+    useEffect(() => {
+        // The code is single-use; don't spend it twice in StrictMode.
+        if (sent.current || err) return;
+        sent.current = true;
+        const code = params.get("code");
+        if (!code) {
+            setErr("Sign-in did not complete. Please try again.");
+            return;
+        }
+        const redirect = params.get("redirect") || "/";
+        signinWithSSO(code)
+            .then(() => navigate(redirect.startsWith("/") ? redirect : "/", { replace: true }))
+            .catch((e) => setErr(e.message || "Sign-in failed. Please try again."));
+    }, []);
+
+    return (
+        <div className="min-h-screen bg-[#f6f3f2]">
+            <Header />
+            <div className="mx-auto max-w-md px-4 py-10">
+                <div className="rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+                    <h1 className="text-xl font-semibold text-black">Single sign-on</h1>
+                    <p className="mt-3 text-sm text-black/60">{err || "Signing you in…"}</p>
+                    {err && (
+                        <Link to="/signin" className="mt-6 inline-block text-sm font-semibold text-[#92140c] hover:underline">
+                            Back to sign in
+                        </Link>
+                    )}
+                </div>
+            </div>
+        </div>
+    );
+}
@@ -2,6 +2,7 @@ import { useState, useEffect } from "react";
 import { useParams, useNavigate } from "react-router-dom";
 import Header from "../components/Header";
 import RecommendedEvents from "../components/RecommendedEvents";
+import Reviews from "../components/Reviews";
 import SaveButton from "../components/SaveButton";
 import { formatEventDate } from "../utils/dateUtils";
 import {
@@ -217,6 +218,11 @@ export default function EventDetail() {
     );
 
     const officialWebsite = eventDetail?.external_url || eventDetail?.registration_url || event.website;
+    // Registration and website links go through the API so clicks count
+    // towards the event's popularity.
+    const outbound = eventDetail?.external_url ? null
+        : eventDetail?.registration_url ? "registration" : event.website ? "website" : null;
+    const registerURL = outbound ? `${API_BASE_URL}/api/events/${id}/out?to=${outbound}` : officialWebsite;
     const parentSiteURL = SOURCE_URLS[event.platform] || officialWebsite;
     const parentSiteLabel = SOURCE_LABELS[event.platform] || event.platform;
 
@@ -420,7 +426,7 @@ export default function EventDetail() {
 
                             <SaveButton eventId={id} initialSaved={isSaved} onToggle={setIsSaved} />
 
-                            <a href={officialWebsite} target="_blank" rel="noopener noreferrer"
+                            <a href={registerURL} target="_blank" rel="noopener noreferrer"
                                 className="w-full flex items-center justify-center gap-2 py-3 rounded-xl text-sm font-medium mt-3"
                                 style={{ background: "#92140c", color: "#fff8f0", border: "1px solid #92140c", letterSpacing: "0.02em" }}
                                 onMouseEnter={e => { e.currentTarget.style.background = "#1e1e24"; e.currentTarget.style.borderColor = "#1e1e24"; }}
@@ -447,7 +453,9 @@ export default function EventDetail() {
                     </div>
                 </div>
 
+                <Reviews eventId={id} />
                 <RecommendedEvents eventId={id} limit={4} />
+                <RecommendedEvents eventId={id} limit={4} list="also-saved" />
             </main>
         </div>
     );
@@ -5,6 +5,7 @@ import FilterBar from "../components/FilterBar";
 import SearchBar from "../components/SearchBar";
 import Header from "../components/Header";
 import Pagination from "../components/Pagination";
+import RecommendedEvents from "../components/RecommendedEvents";
 
 const API_BASE_URL = "";
 const PAGE_SIZE = 8;
@@ -112,6 +113,24 @@ export default function Events() {
 
     const hasFilters = !!(search || filters.location || filters.dateFrom || filters.dateTo);
 
+    // Bumped by the live stream after a cycle that added or changed events.
+    const [streamVersion, setStreamVersion] = useState(0);
+
+    useEffect(() => {
+        const source = new EventSource(`${API_BASE_URL}/api/stream?types=cycle-finished`);
+        source.addEventListener("cycle-finished", (e) => {
+            try {
+                const { data } = JSON.parse(e.data);
+                if (data && (data.inserted > 0 || data.changed > 0 || data.deleted > 0)) {
+                    setStreamVersion((v) => v + 1);
+                }
+            } catch {
+                // ignore malformed messages
+            }
+        });
+        return () => source.close();
+    }, []);
+
     useEffect(() => {
         const fetchEvents = async () => {
             setLoading(true);
@@ -156,7 +175,7 @@ export default function Events() {
         };
 
         fetchEvents();
-    }, [search, filters.location, filters.dateFrom, filters.dateTo, page]);
+    }, [search, filters.location, filters.dateFrom, filters.dateTo, page, streamVersion]);
 
     return (
         <div className="min-h-screen" style={{ background: "#fff8f0", fontFamily: "'Inter', sans-serif" }}>
@@ -190,6 +209,12 @@ export default function Events() {
 
                 <div style={{ marginBottom: 16 }} />
 
+                {!search && page === 1 && (
+                    <div className="mb-8">
+                        <RecommendedEvents list="trending" city={filters.location} limit={4} />
+                    </div>
+                )}
+
                 {error ? (
                     <div className="text-center py-24" style={{
                         background: "#fff8f0", borderRadius: 24,
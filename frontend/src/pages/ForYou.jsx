@@ -0,0 +1,102 @@
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import Header from "../components/Header";
+import EventCard from "../components/EventCard";
+import Pagination from "../components/Pagination";
+import { useAuth } from "../auth/AuthContext";
+import * as interestsApi from "../api/interests";
+
+// Upcoming events ranked by the user's interests and saved events, each
+// with the reasons it was picked, then events saved by people who saved
+// the same events.
+export default function ForYou() {
+    const { isAuthed } = useAuth();
+    const [data, setData] = useState(null);
+    const [page, setPage] = useState(1);
+    const [error, setError] = useState("");
+    const [picks, setPicks] = useState([]);
+
+    useEffect(() => {
+        if (!isAuthed) return;
+        setError("");
+        interestsApi
+            .forYou(page)
+            .then(setData)
+            .catch((e) => setError(e.message));
+    }, [isAuthed, page]);
+
+    useEffect(() => {
+        if (!isAuthed) return;
+        interestsApi
+            .recommended()
+            .then((data) => setPicks(data.events))
+            .catch(() => setPicks([]));
+    }, [isAuthed]);
+
+    return (
+        <div className="min-h-screen bg-[#f6f3f2]">
+            <Header />
+            <main className="mx-auto max-w-6xl px-4 py-10">
+                <h1 className="text-xl font-semibold text-black">For you</h1>
+
+                {!isAuthed ? (
+                    <p className="mt-3 text-sm text-black/60">
+                        <Link to="/signin" className="font-semibold text-[#92140c] hover:underline">Sign in</Link> to see
+                        events picked for you.
+                    </p>
+                ) : error ? (
+                    <p className="mt-3 text-sm text-[#92140c]">{error}</p>
+                ) : !data ? (
+                    <p className="mt-3 text-sm text-black/60">Loading…</p>
+                ) : (
+                    <>
+                        {!data.has_interests && (
+                            <p className="mt-3 text-sm text-black/60">
+                                Tell us what you like on your{" "}
+                                <Link to="/profile" className="font-semibold text-[#92140c] hover:underline">profile</Link> to
+                                get better picks.
+                            </p>
+                        )}
+                        {data.events.length === 0 ? (
+                            <p className="mt-6 text-sm text-black/60">
+                                Nothing matches yet. Add interests or save a few events.
+                            </p>
+                        ) : (
+                            <div className="mt-6 grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-5">
+                                {data.events.map((event, i) => (
+                                    <div key={event.id}>
+                                        <EventCard event={event} index={i} />
+                                        <ul className="mt-2 space-y-0.5 text-xs text-black/60">
+                                            {event.reasons.map((r) => (
+                                                <li key={`${r.kind}-${r.source}-${r.value}`}>{r.text}</li>
+                                            ))}
+                                        </ul>
+                                    </div>
+                                ))}
+                            </div>
+                        )}
+                        <Pagination page={page} totalPages={data.total_pages} onPage={setPage} />
+
+                        {picks.length > 0 && (
+                            <>
+                                <h2 className="mt-12 text-lg font-semibold text-black">Saved by people like you</h2>
+                                <div className="mt-4 grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-5">
+                                    {picks.map((event, i) => (
+                                        <div key={event.id}>
+                                            <EventCard event={event} index={i} />
+                                            <p className="mt-2 text-xs text-black/60">
+                                                {event.source === "saved"
+                                                    ? "Saved by people who saved the same events as you"
+                                                    : event.reasons?.[0]?.text}
+                                            </p>
+                                        </div>
+                                    ))}
+                                </div>
+                            </>
+                        )}
+                    </>
+                )}
+            </main>
+        </div>
+    );
+}
@@ -0,0 +1,61 @@
+import React, { useState } from "react";
+import { Link } from "react-router-dom";
+import Header from "../components/Header";
+import * as authApi from "../api/auth";
+
+export default function ForgotPassword() {
+    const [email, setEmail] = useState("");
+    const [loading, setLoading] = useState(false);
+    const [done, setDone] = useState("");
+    const [err, setErr] = useState("");
+
+    async function onSubmit(e) {
+        e.preventDefault();
+        setErr("");
+        setLoading(true);
+        try {
+            const data = await authApi.forgotPassword(email);
+            setDone(data.message);
+        } catch (error) {
+            setErr(error.message || "Something went wrong. Please try again.");
+        } finally {
+            setLoading(false);
+        }
+    }
+
+    return (
+        <div className="min-h-screen bg-[#f6f3f2]">
+            <Header />
+            <div className="mx-auto max-w-md px-4 py-10">
+                <div className="rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+                    <h1 className="text-xl font-semibold text-black">Forgot your password?</h1>
+                    {done ? (
+                        <p className="mt-3 text-sm text-black/60">{done}.</p>
+                    ) : (
+                        <form onSubmit={onSubmit} className="mt-4 space-y-4">
+                            <p className="text-sm text-black/60">We'll email you a link to choose a new one.</p>
+                            {err && <p className="text-sm text-[#92140c]">{err}</p>}
+                            <input
+                                className="w-full rounded-xl border border-black/10 px-4 py-3 text-sm outline-none focus:ring-2 focus:ring-[#92140c]/20"
+                                type="email"
+                                placeholder="you@example.com"
+                                value={email}
+                                onChange={(e) => setEmail(e.target.value)}
+                                required
+                            />
+                            <button
+                                disabled={loading}
+                                className="w-full rounded-xl bg-[#1e1e24] px-4 py-3 text-sm font-semibold text-white hover:bg-[#92140c] disabled:opacity-60"
+                            >
+                                {loading ? "Sending…" : "Send reset link"}
+                            </button>
+                        </form>
+                    )}
+                    <Link to="/signin" className="mt-6 inline-block text-sm text-black/60 hover:text-[#92140c]">
+                        ← Back to sign in
+                    </Link>
+                </div>
+            </div>
+        </div>
+    );
+}
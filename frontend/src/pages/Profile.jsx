@@ -1,9 +1,23 @@
-import React from "react";
+import React, { useState } from "react";
 import Header from "../components/Header";
+import APIKeys from "../components/APIKeys";
+import AccountSettings from "../components/AccountSettings";
+import Interests from "../components/Interests";
 import { useAuth } from "../auth/AuthContext";
+import * as authApi from "../api/auth";
 
 export default function Profile() {
     const { user, signout } = useAuth();
+    const [verifyMsg, setVerifyMsg] = useState("");
+
+    async function resendVerification() {
+        try {
+            const data = await authApi.requestVerification();
+            setVerifyMsg(data.message);
+        } catch (e) {
+            setVerifyMsg(e.message);
+        }
+    }
 
     return (
         <div className="min-h-screen bg-[#f6f3f2]">
@@ -17,7 +31,20 @@ export default function Profile() {
                         </div>
                         <div className="mt-1">
                             <span className="font-semibold text-black">Email:</span> {user?.email || "-"}
+                            {user && (user.email_verified ? (
+                                <span className="ml-2 rounded-full bg-green-50 px-2 py-0.5 text-xs text-green-700">Verified</span>
+                            ) : (
+                                <span className="ml-2 rounded-full bg-amber-50 px-2 py-0.5 text-xs text-amber-700">Not verified</span>
+                            ))}
                         </div>
+                        {user && !user.email_verified && (
+                            <div className="mt-2">
+                                <button onClick={resendVerification} className="text-sm font-semibold text-[#92140c] hover:underline">
+                                    Send verification email
+                                </button>
+                                {verifyMsg && <span className="ml-2">{verifyMsg}</span>}
+                            </div>
+                        )}
                     </div>
 
                     <button
@@ -27,6 +54,10 @@ export default function Profile() {
                         Sign out
                     </button>
                 </div>
+
+                {user && <Interests />}
+                {user && <AccountSettings />}
+                {user && <APIKeys />}
             </div>
         </div>
     );
@@ -0,0 +1,64 @@
+import React, { useState } from "react";
+import { Link, useSearchParams } from "react-router-dom";
+import Header from "../components/Header";
+import * as authApi from "../api/auth";
+
+// Landing page of the link in the password reset email.
+export default function ResetPassword() {
+    const [params] = useSearchParams();
+    const token = params.get("token") || "";
+    const [password, setPassword] = useState("");
+    const [confirm, setConfirm] = useState("");
+    const [loading, setLoading] = useState(false);
+    const [done, setDone] = useState("");
+    const [err, setErr] = useState(token ? "" : "This link is missing its token.");
+
+    async function onSubmit(e) {
+        e.preventDefault();
+        if (password.length < 6) { setErr("Password must be at least 6 characters."); return; }
+        if (password !== confirm) { setErr("Passwords don't match."); return; }
+        setErr("");
+        setLoading(true);
+        try {
+            const data = await authApi.resetPassword({ token, password });
+            setDone(data.message);
+        } catch (error) {
+            setErr(error.message || "Something went wrong. Please try again.");
+        } finally {
+            setLoading(false);
+        }
+    }
+
+    const input = "w-full rounded-xl border border-black/10 px-4 py-3 text-sm outline-none focus:ring-2 focus:ring-[#92140c]/20";
+
+    return (
+        <div className="min-h-screen bg-[#f6f3f2]">
+            <Header />
+            <div className="mx-auto max-w-md px-4 py-10">
+                <div className="rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+                    <h1 className="text-xl font-semibold text-black">Choose a new password</h1>
+                    {done ? (
+                        <p className="mt-3 text-sm text-black/60">{done}.</p>
+                    ) : (
+                        <form onSubmit={onSubmit} className="mt-4 space-y-4">
+                            {err && <p className="text-sm text-[#92140c]">{err}</p>}
+                            <input className={input} type="password" placeholder="New password" value={password}
+                                onChange={(e) => setPassword(e.target.value)} minLength={6} required />
+                            <input className={input} type="password" placeholder="Repeat it" value={confirm}
+                                onChange={(e) => setConfirm(e.target.value)} required />
+                            <button
+                                disabled={loading || !token}
+                                className="w-full rounded-xl bg-[#1e1e24] px-4 py-3 text-sm font-semibold text-white hover:bg-[#92140c] disabled:opacity-60"
+                            >
+                                {loading ? "Saving…" : "Set password"}
+                            </button>
+                        </form>
+                    )}
+                    <Link to="/signin" className="mt-6 inline-block text-sm text-black/60 hover:text-[#92140c]">
+                        ← Sign in
+                    </Link>
+                </div>
+            </div>
+        </div>
+    );
+}
@@ -0,0 +1,119 @@
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import Header from "../components/Header";
+import { Stars } from "../components/Reviews";
+import * as reviewsApi from "../api/reviews";
+
+const STATUSES = ["pending", "published", "hidden"];
+
+// Moderators publish or hide held reviews: ones a screening rule objected
+// to, edits of held reviews and reviews enough readers reported.
+export default function ReviewQueue() {
+    const [status, setStatus] = useState("pending");
+    const [page, setPage] = useState(1);
+    const [data, setData] = useState(null);
+    const [error, setError] = useState("");
+
+    async function load() {
+        setError("");
+        try {
+            setData(await reviewsApi.moderationQueue(status, page));
+        } catch (e) {
+            setError(e.message);
+        }
+    }
+
+    useEffect(() => {
+        load();
+    }, [status, page]);
+
+    async function moderate(review, next) {
+        let reason = "";
+        if (next === "hidden") {
+            reason = window.prompt("Why is this review hidden? The author will see this.");
+            if (reason === null) return;
+        }
+        setError("");
+        try {
+            await reviewsApi.moderate(review.id, next, reason);
+            load();
+        } catch (e) {
+            setError(e.message);
+        }
+    }
+
+    return (
+        <div className="min-h-screen" style={{ background: "#fff8f0" }}>
+            <Header />
+            <main className="mx-auto max-w-4xl px-4 py-10">
+                <h1 className="text-2xl font-semibold text-black">Reviews</h1>
+                <div className="mt-4 flex gap-2 text-sm">
+                    {STATUSES.map((s) => (
+                        <button
+                            key={s}
+                            onClick={() => {
+                                setStatus(s);
+                                setPage(1);
+                            }}
+                            className={`rounded-full border px-3 py-1 capitalize ${
+                                status === s ? "border-[#92140c] text-[#92140c]" : "border-black/10 text-black/60"
+                            }`}
+                        >
+                            {s}
+                        </button>
+                    ))}
+                </div>
+                {error && <div className="mt-4 text-sm text-[#92140c]">{error}</div>}
+
+                <ul className="mt-6 flex flex-col gap-4 text-sm">
+                    {(data?.reviews || []).map((r) => (
+                        <li key={r.id} className="rounded-2xl border border-black/5 bg-white p-5 shadow-sm">
+                            <div className="flex flex-wrap items-center justify-between gap-3">
+                                <div>
+                                    <Stars rating={r.rating} />{" "}
+                                    <span className="font-semibold text-black">{r.author_name}</span> on{" "}
+                                    <Link to={`/events/${r.event_id}`} className="text-[#92140c] hover:underline">
+                                        event {r.event_id}
+                                    </Link>
+                                </div>
+                                <div className="text-black/50">
+                                    {r.flags > 0 && `${r.flags} report${r.flags !== 1 ? "s" : ""} · `}
+                                    {new Date(r.updated_at).toLocaleString()}
+                                </div>
+                            </div>
+                            {r.body && <p className="mt-2 whitespace-pre-line text-black/80">{r.body}</p>}
+                            {r.moderation_reason && <p className="mt-2 text-black/50">Held: {r.moderation_reason}</p>}
+                            <div className="mt-3 flex gap-3">
+                                {r.status !== "published" && (
+                                    <button onClick={() => moderate(r, "published")} className="font-semibold text-[#92140c] hover:underline">
+                                        Publish
+                                    </button>
+                                )}
+                                {r.status !== "hidden" && (
+                                    <button onClick={() => moderate(r, "hidden")} className="text-black/60 hover:underline">
+                                        Hide
+                                    </button>
+                                )}
+                            </div>
+                        </li>
+                    ))}
+                    {data && data.reviews.length === 0 && <li className="text-black/50">Nothing here.</li>}
+                </ul>
+
+                {data?.total_pages > 1 && (
+                    <div className="mt-4 flex items-center gap-3 text-sm text-black/60">
+                        <button onClick={() => setPage(page - 1)} disabled={page === 1} className="disabled:opacity-30">
+                            ← Previous
+                        </button>
+                        <span>
+                            Page {page} of {data.total_pages}
+                        </span>
+                        <button onClick={() => setPage(page + 1)} disabled={page === data.total_pages} className="disabled:opacity-30">
+                            Next →
+                        </button>
+                    </div>
+                )}
+            </main>
+        </div>
+    );
+}
@@ -2,6 +2,10 @@ import { useState, useEffect } from "react";
 import { useNavigate, Link } from "react-router-dom";
 import { useAuth } from "../auth/AuthContext";
 import Header from "../components/Header";
+import Collections from "../components/Collections";
+import Agenda from "../components/Agenda";
+import * as collectionsApi from "../api/collections";
+import * as rsvpApi from "../api/rsvp";
 
 const API_BASE_URL = "";
 
@@ -72,21 +76,22 @@ export default function Saved() {
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState(null);
     const [removingId, setRemovingId] = useState(null);
+    const [tagFilter, setTagFilter] = useState("");
+    const [editing, setEditing] = useState(null); // { eventId, notes, tags }
+    const [editError, setEditError] = useState("");
+    const [conflicts, setConflicts] = useState(null); // { name, events }
+    const [agendaVersion, setAgendaVersion] = useState(0);
 
     useEffect(() => {
         if (!isAuthed) { setLoading(false); return; }
         fetchSavedEvents();
-    }, [isAuthed, token]);
+    }, [isAuthed, token, tagFilter]);
 
     async function fetchSavedEvents() {
         setLoading(true);
         setError(null);
         try {
-            const res = await fetch(`${API_BASE_URL}/api/saved-events`, {
-                headers: { Authorization: `Bearer ${token}` },
-            });
-            if (!res.ok) throw new Error("Failed to fetch saved events");
-            const data = await res.json();
+            const data = await collectionsApi.listSaved(tagFilter);
             setSavedEvents(data.saved_events || []);
         } catch (err) {
             setError(err.message);
@@ -95,6 +100,35 @@ export default function Saved() {
         }
     }
 
+    async function changeStatus(se, status) {
+        setError(null);
+        try {
+            const data = await rsvpApi.setStatus(se.event_id, status);
+            setSavedEvents((prev) => prev.map((x) => (x.event_id === se.event_id ? data.saved_event : x)));
+            setConflicts(data.conflicts?.length ? { name: se.event?.event_name, events: data.conflicts } : null);
+            setAgendaVersion((v) => v + 1);
+        } catch (err) {
+            setError(err.message);
+        }
+    }
+
+    function startEditing(se) {
+        setEditError("");
+        setEditing({ eventId: se.event_id, notes: se.notes || "", tags: (se.tags || []).join(", ") });
+    }
+
+    async function saveEditing() {
+        setEditError("");
+        const tags = editing.tags.split(",").map((t) => t.trim()).filter(Boolean);
+        try {
+            const data = await collectionsApi.updateSavedEvent(editing.eventId, { notes: editing.notes, tags });
+            setSavedEvents((prev) => prev.map((se) => (se.event_id === editing.eventId ? data.saved_event : se)));
+            setEditing(null);
+        } catch (err) {
+            setEditError(err.message);
+        }
+    }
+
     async function handleUnsave(eventId) {
         setRemovingId(eventId);
         try {
@@ -190,7 +224,7 @@ export default function Saved() {
                         </button>
                     </div>
 
-                ) : savedEvents.length === 0 ? (
+                ) : savedEvents.length === 0 && !tagFilter ? (
                     <div style={{
                         textAlign: "center", padding: "80px 24px",
                         borderRadius: 20, border: "1px solid rgba(146,20,12,0.1)",
@@ -220,118 +254,259 @@ export default function Saved() {
                     <div style={{ display: "flex", flexDirection: "column", gap: 12 }}>
                         <p style={{ fontSize: 13, color: "#1e1e24", opacity: 0.45, marginBottom: 4, letterSpacing: "0.02em" }}>
                             {savedEvents.length} saved event{savedEvents.length !== 1 ? "s" : ""}
+                            {tagFilter && (
+                                <>
+                                    {" "}tagged “{tagFilter}” ·{" "}
+                                    <button onClick={() => setTagFilter("")} style={{
+                                        background: "none", border: "none", padding: 0, cursor: "pointer",
+                                        color: "#92140c", fontSize: 13, textDecoration: "underline",
+                                    }}>
+                                        show all
+                                    </button>
+                                </>
+                            )}
                         </p>
 
+                        {conflicts && (
+                            <div style={{
+                                padding: "12px 16px", borderRadius: 12, fontSize: 13,
+                                background: "rgba(146,20,12,0.06)", border: "1px solid rgba(146,20,12,0.2)", color: "#92140c",
+                            }}>
+                                “{conflicts.name}” overlaps {conflicts.events.length === 1 ? "another event" : "other events"} you're going to:{" "}
+                                {conflicts.events.map((ev, i) => (
+                                    <span key={ev.id}>
+                                        {i > 0 && ", "}
+                                        <Link to={`/events/${ev.id}`} style={{ color: "#92140c", fontWeight: 500 }}>{ev.event_name}</Link>
+                                    </span>
+                                ))}
+                                <button onClick={() => setConflicts(null)} title="Dismiss" style={{
+                                    marginLeft: 8, background: "none", border: "none", cursor: "pointer", color: "#92140c",
+                                }}>
+                                    ✕
+                                </button>
+                            </div>
+                        )}
+
                         {savedEvents.map((se) => {
                             const event = se.event;
                             const isRemoving = removingId === se.event_id;
 
+                            const isEditing = editing?.eventId === se.event_id;
+
                             return (
-                                <div
-                                    key={se.id}
-                                    onClick={() => navigate(`/events/${event?.id}`)}
-                                    style={{
-                                        display: "flex", alignItems: "center", gap: 18,
-                                        padding: "18px 20px",
-                                        borderRadius: 16,
-                                        background: "#fff8f0",
-                                        border: "1px solid rgba(146,20,12,0.1)",
-                                        boxShadow: "0 4px 20px -10px rgba(30,30,36,0.1)",
-                                        cursor: "pointer",
-                                        transition: "all 0.3s ease",
-                                        opacity: isRemoving ? 0.5 : 1,
-                                    }}
-                                    onMouseEnter={e => {
-                                        e.currentTarget.style.boxShadow = "0 10px 30px -10px rgba(146,20,12,0.2)";
-                                        e.currentTarget.style.borderColor = "rgba(146,20,12,0.3)";
-                                        e.currentTarget.style.transform = "translateY(-1px)";
-                                    }}
-                                    onMouseLeave={e => {
-                                        e.currentTarget.style.boxShadow = "0 4px 20px -10px rgba(30,30,36,0.1)";
-                                        e.currentTarget.style.borderColor = "rgba(146,20,12,0.1)";
-                                        e.currentTarget.style.transform = "translateY(0)";
-                                    }}
-                                >
-                                    {/* Accent strip */}
-                                    <div style={{
-                                        width: 4, alignSelf: "stretch", minHeight: 52,
-                                        borderRadius: 2, background: "#92140c",
-                                        opacity: 0.7, flexShrink: 0,
-                                    }} />
-
-                                    {/* Event info */}
-                                    <div style={{ flex: 1, minWidth: 0 }}>
-                                        {/* Date row — SVG calendar icon matching EventCard */}
+                                <div key={se.id}>
+                                    <div
+                                        onClick={() => navigate(`/events/${event?.id}`)}
+                                        style={{
+                                            display: "flex", alignItems: "center", gap: 18,
+                                            padding: "18px 20px",
+                                            borderRadius: 16,
+                                            background: "#fff8f0",
+                                            border: "1px solid rgba(146,20,12,0.1)",
+                                            boxShadow: "0 4px 20px -10px rgba(30,30,36,0.1)",
+                                            cursor: "pointer",
+                                            transition: "all 0.3s ease",
+                                            opacity: isRemoving ? 0.5 : 1,
+                                        }}
+                                        onMouseEnter={e => {
+                                            e.currentTarget.style.boxShadow = "0 10px 30px -10px rgba(146,20,12,0.2)";
+                                            e.currentTarget.style.borderColor = "rgba(146,20,12,0.3)";
+                                            e.currentTarget.style.transform = "translateY(-1px)";
+                                        }}
+                                        onMouseLeave={e => {
+                                            e.currentTarget.style.boxShadow = "0 4px 20px -10px rgba(30,30,36,0.1)";
+                                            e.currentTarget.style.borderColor = "rgba(146,20,12,0.1)";
+                                            e.currentTarget.style.transform = "translateY(0)";
+                                        }}
+                                    >
+                                        {/* Accent strip */}
                                         <div style={{
-                                            display: "flex", alignItems: "center", gap: 5,
-                                            marginBottom: 5, color: "#92140c",
-                                        }}>
-                                            <IconCalendar />
-                                            <span style={{ fontSize: 12, fontWeight: 500, letterSpacing: "0.02em" }}>
-                                                {formatDate(event?.date || event?.date_time)}
-                                            </span>
-                                        </div>
+                                            width: 4, alignSelf: "stretch", minHeight: 52,
+                                            borderRadius: 2, background: "#92140c",
+                                            opacity: 0.7, flexShrink: 0,
+                                        }} />
 
-                                        {/* Title */}
-                                        <h3 style={{
-                                            fontFamily: "'Cormorant Garamond', serif",
-                                            fontSize: "1.05rem", fontWeight: 500,
-                                            color: "#1e1e24", letterSpacing: "-0.01em",
-                                            marginBottom: 5,
-                                            overflow: "hidden", textOverflow: "ellipsis", whiteSpace: "nowrap",
-                                        }}>
-                                            {event?.event_name}
-                                        </h3>
+                                        {/* Event info */}
+                                        <div style={{ flex: 1, minWidth: 0 }}>
+                                            {/* Date row — SVG calendar icon matching EventCard */}
+                                            <div style={{
+                                                display: "flex", alignItems: "center", gap: 5,
+                                                marginBottom: 5, color: "#92140c",
+                                            }}>
+                                                <IconCalendar />
+                                                <span style={{ fontSize: 12, fontWeight: 500, letterSpacing: "0.02em" }}>
+                                                    {formatDate(event?.date || event?.date_time)}
+                                                </span>
+                                            </div>
 
-                                        {/* Location row — SVG pin icon matching EventCard */}
-                                        <div style={{
-                                            display: "flex", alignItems: "center", gap: 5,
-                                            color: "#1e1e24",
-                                        }}>
-                                            <IconLocation />
-                                            <span style={{
-                                                fontSize: 12, opacity: 0.55,
+                                            {/* Title */}
+                                            <h3 style={{
+                                                fontFamily: "'Cormorant Garamond', serif",
+                                                fontSize: "1.05rem", fontWeight: 500,
+                                                color: "#1e1e24", letterSpacing: "-0.01em",
+                                                marginBottom: 5,
                                                 overflow: "hidden", textOverflow: "ellipsis", whiteSpace: "nowrap",
-                                                letterSpacing: "0.02em",
                                             }}>
-                                                {event?.location || "Location TBA"}
-                                            </span>
+                                                {event?.event_name}
+                                            </h3>
+
+                                            {/* Location row — SVG pin icon matching EventCard */}
+                                            <div style={{
+                                                display: "flex", alignItems: "center", gap: 5,
+                                                color: "#1e1e24",
+                                            }}>
+                                                <IconLocation />
+                                                <span style={{
+                                                    fontSize: 12, opacity: 0.55,
+                                                    overflow: "hidden", textOverflow: "ellipsis", whiteSpace: "nowrap",
+                                                    letterSpacing: "0.02em",
+                                                }}>
+                                                    {event?.location || "Location TBA"}
+                                                </span>
+                                            </div>
+
+                                            {/* Notes & tags */}
+                                            {se.notes && (
+                                                <p style={{
+                                                    fontSize: 12, color: "#1e1e24", opacity: 0.7, marginTop: 6,
+                                                    overflow: "hidden", textOverflow: "ellipsis", whiteSpace: "nowrap",
+                                                }}>
+                                                    {se.notes}
+                                                </p>
+                                            )}
+                                            {se.tags?.length > 0 && (
+                                                <div style={{ display: "flex", flexWrap: "wrap", gap: 6, marginTop: 6 }}>
+                                                    {se.tags.map((tag) => (
+                                                        <button
+                                                            key={tag}
+                                                            onClick={e => { e.stopPropagation(); setTagFilter(tag); }}
+                                                            title={`Show events tagged “${tag}”`}
+                                                            style={{
+                                                                padding: "2px 8px", borderRadius: 999, cursor: "pointer",
+                                                                border: "1px solid rgba(146,20,12,0.2)", background: "rgba(146,20,12,0.05)",
+                                                                color: "#92140c", fontSize: 11, fontWeight: 500,
+                                                            }}
+                                                        >
+                                                            #{tag}
+                                                        </button>
+                                                    ))}
+                                                </div>
+                                            )}
                                         </div>
+
+                                        {/* RSVP status */}
+                                        <select
+                                            value={se.status || "interested"}
+                                            onClick={e => e.stopPropagation()}
+                                            onChange={e => changeStatus(se, e.target.value)}
+                                            title="Are you going?"
+                                            style={{
+                                                height: 38, borderRadius: 10, flexShrink: 0, padding: "0 8px",
+                                                border: "1px solid rgba(146,20,12,0.2)", background: "transparent",
+                                                color: "#92140c", fontSize: 12, cursor: "pointer",
+                                            }}
+                                        >
+                                            {rsvpApi.STATUSES.map((st) => (
+                                                <option key={st.value} value={st.value}>{st.label}</option>
+                                            ))}
+                                        </select>
+
+                                        {/* Edit notes & tags */}
+                                        <button
+                                            onClick={e => { e.stopPropagation(); isEditing ? setEditing(null) : startEditing(se); }}
+                                            title="Edit notes and tags"
+                                            style={{
+                                                width: 38, height: 38, borderRadius: 10, flexShrink: 0,
+                                                border: "1px solid rgba(146,20,12,0.2)",
+                                                background: isEditing ? "rgba(146,20,12,0.08)" : "transparent",
+                                                cursor: "pointer",
+                                                display: "flex", alignItems: "center", justifyContent: "center",
+                                                color: "#92140c",
+                                            }}
+                                        >
+                                            <svg width="15" height="15" fill="none" viewBox="0 0 24 24" stroke="currentColor" strokeWidth={1.5}>
+                                                <path strokeLinecap="round" strokeLinejoin="round"
+                                                    d="M15.232 5.232l3.536 3.536M9 13l6.232-6.232a2.5 2.5 0 113.536 3.536L12.536 16.536 8 18l1.464-4.536z" />
+                                            </svg>
+                                        </button>
+
+                                        {/* Unsave button — filled bookmark → outline on hover */}
+                                        <button
+                                            onClick={e => { e.stopPropagation(); handleUnsave(se.event_id); }}
+                                            disabled={isRemoving}
+                                            title="Remove from saved"
+                                            style={{
+                                                width: 38, height: 38, borderRadius: 10, flexShrink: 0,
+                                                border: "1px solid rgba(146,20,12,0.2)",
+                                                background: "transparent",
+                                                cursor: isRemoving ? "not-allowed" : "pointer",
+                                                display: "flex", alignItems: "center", justifyContent: "center",
+                                                color: "#92140c", transition: "all 0.2s",
+                                            }}
+                                            onMouseEnter={e => {
+                                                e.currentTarget.style.background = "rgba(146,20,12,0.08)";
+                                                e.currentTarget.style.borderColor = "#92140c";
+                                            }}
+                                            onMouseLeave={e => {
+                                                e.currentTarget.style.background = "transparent";
+                                                e.currentTarget.style.borderColor = "rgba(146,20,12,0.2)";
+                                            }}
+                                        >
+                                            {/* Filled bookmark = saved; shows outline on hover via CSS would need extra state,
+                                                keeping filled bookmark as the "remove" affordance */}
+                                            <svg width="15" height="15" fill="currentColor" viewBox="0 0 24 24">
+                                                <path d="M5 5a2 2 0 012-2h10a2 2 0 012 2v16l-7-3.5L5 21V5z" />
+                                            </svg>
+                                        </button>
                                     </div>
 
-                                    {/* Unsave button — filled bookmark → outline on hover */}
-                                    <button
-                                        onClick={e => { e.stopPropagation(); handleUnsave(se.event_id); }}
-                                        disabled={isRemoving}
-                                        title="Remove from saved"
-                                        style={{
-                                            width: 38, height: 38, borderRadius: 10, flexShrink: 0,
-                                            border: "1px solid rgba(146,20,12,0.2)",
-                                            background: "transparent",
-                                            cursor: isRemoving ? "not-allowed" : "pointer",
-                                            display: "flex", alignItems: "center", justifyContent: "center",
-                                            color: "#92140c", transition: "all 0.2s",
-                                        }}
-                                        onMouseEnter={e => {
-                                            e.currentTarget.style.background = "rgba(146,20,12,0.08)";
-                                            e.currentTarget.style.borderColor = "#92140c";
-                                        }}
-                                        onMouseLeave={e => {
-                                            e.currentTarget.style.background = "transparent";
-                                            e.currentTarget.style.borderColor = "rgba(146,20,12,0.2)";
-                                        }}
-                                    >
-                                        {/* Filled bookmark = saved; shows outline on hover via CSS would need extra state,
-                                            keeping filled bookmark as the "remove" affordance */}
-                                        <svg width="15" height="15" fill="currentColor" viewBox="0 0 24 24">
-                                            <path d="M5 5a2 2 0 012-2h10a2 2 0 012 2v16l-7-3.5L5 21V5z" />
-                                        </svg>
-                                    </button>
+                                    {isEditing && (
+                                        <div style={{
+                                            margin: "6px 0 0 22px", padding: 16, borderRadius: 12,
+                                            border: "1px solid rgba(146,20,12,0.1)", display: "flex", flexDirection: "column", gap: 8,
+                                        }}>
+                                            <textarea
+                                                value={editing.notes}
+                                                onChange={e => setEditing({ ...editing, notes: e.target.value })}
+                                                placeholder="Notes"
+                                                rows={3}
+                                                style={{ padding: 8, borderRadius: 8, border: "1px solid rgba(30,30,36,0.15)", fontSize: 13, fontFamily: "inherit" }}
+                                            />
+                                            <input
+                                                value={editing.tags}
+                                                onChange={e => setEditing({ ...editing, tags: e.target.value })}
+                                                placeholder="Tags, separated by commas"
+                                                style={{ padding: 8, borderRadius: 8, border: "1px solid rgba(30,30,36,0.15)", fontSize: 13 }}
+                                            />
+                                            {editError && <p style={{ fontSize: 12, color: "#92140c" }}>{editError}</p>}
+                                            <div style={{ display: "flex", gap: 8 }}>
+                                                <button onClick={saveEditing} style={{
+                                                    padding: "6px 16px", borderRadius: 8, border: "none", cursor: "pointer",
+                                                    background: "#92140c", color: "#fff8f0", fontSize: 13, fontWeight: 500,
+                                                }}>
+                                                    Save
+                                                </button>
+                                                <button onClick={() => setEditing(null)} style={{
+                                                    padding: "6px 16px", borderRadius: 8, cursor: "pointer",
+                                                    border: "1px solid rgba(30,30,36,0.15)", background: "transparent", fontSize: 13,
+                                                }}>
+                                                    Cancel
+                                                </button>
+                                            </div>
+                                        </div>
+                                    )}
                                 </div>
                             );
                         })}
                     </div>
                 )}
+
+                {isAuthed && !loading && !error && savedEvents.length > 0 && (
+                    <>
+                        <Agenda version={agendaVersion} />
+                        <Collections savedEvents={savedEvents} />
+                    </>
+                )}
             </main>
         </div>
     );
@@ -1,5 +1,6 @@
 import { useState, useEffect } from "react";
 import Header from "../components/Header";
+import { apiFetch } from "../api/client";
 
 const API_BASE_URL = "";
 
@@ -13,13 +14,21 @@ export default function ScraperHealth() {
         fetchHealth();
     }, []);
 
+    // Live updates: reload the dashboard whenever a cycle ends or a scraper fails.
+    useEffect(() => {
+        const source = new EventSource(`${API_BASE_URL}/api/stream?types=cycle-finished,scraper-failed`);
+        const reload = () => fetchHealth();
+        source.addEventListener("cycle-finished", reload);
+        source.addEventListener("scraper-failed", reload);
+        return () => source.close();
+    }, []);
+
     async function fetchHealth() {
         setLoading(true);
         setError(null);
         try {
-            const res = await fetch(`${API_BASE_URL}/api/admin/scraper-health`);
-            if (!res.ok) throw new Error(`HTTP ${res.status}`);
-            const data = await res.json();
+            // Admins only; apiFetch sends the token.
+            const data = await apiFetch("/api/admin/scraper-health");
             setScrapers(data.scrapers || []);
         } catch (err) {
             setError(err.message);
@@ -0,0 +1,60 @@
+import { useEffect, useState } from "react";
+import { Link, useParams } from "react-router-dom";
+import Header from "../components/Header";
+import EventCard from "../components/EventCard";
+import * as collectionsApi from "../api/collections";
+
+// The read-only view behind a collection's share link. Works signed out.
+export default function SharedCollection() {
+    const { token } = useParams();
+    const [data, setData] = useState(null);
+    const [error, setError] = useState("");
+
+    useEffect(() => {
+        setError("");
+        collectionsApi
+            .getShared(token)
+            .then(setData)
+            .catch((e) =>
+                setError(e.status === 410 ? "This share link has expired." : "This collection is not shared, or the link is wrong.")
+            );
+    }, [token]);
+
+    return (
+        <div className="min-h-screen bg-[#f6f3f2]">
+            <Header />
+            <main className="mx-auto max-w-6xl px-4 py-10">
+                {error ? (
+                    <>
+                        <p className="text-sm text-[#92140c]">{error}</p>
+                        <Link to="/events" className="mt-3 inline-block text-sm font-semibold text-[#92140c] hover:underline">
+                            Browse events →
+                        </Link>
+                    </>
+                ) : !data ? (
+                    <p className="text-sm text-black/60">Loading…</p>
+                ) : (
+                    <>
+                        <h1 className="text-xl font-semibold text-black">{data.collection.name}</h1>
+                        <p className="mt-1 text-sm text-black/60">
+                            {data.collection.owner_name ? `Shared by ${data.collection.owner_name} · ` : ""}
+                            {data.collection.event_count} event{data.collection.event_count !== 1 ? "s" : ""}
+                        </p>
+                        {data.collection.description && (
+                            <p className="mt-3 max-w-2xl text-sm text-black/70">{data.collection.description}</p>
+                        )}
+                        {data.events.length === 0 ? (
+                            <p className="mt-6 text-sm text-black/60">This collection is empty.</p>
+                        ) : (
+                            <div className="mt-6 grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-4 gap-5">
+                                {data.events.map((event, i) => (
+                                    <EventCard key={event.id} event={event} index={i} />
+                                ))}
+                            </div>
+                        )}
+                    </>
+                )}
+            </main>
+        </div>
+    );
+}
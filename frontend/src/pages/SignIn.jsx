@@ -2,6 +2,7 @@ import React, { useState, useEffect, useRef } from "react";
 import { Link, useNavigate } from "react-router-dom";
 import Header from "../components/Header";
 import { useAuth } from "../auth/AuthContext";
+import * as authApi from "../api/auth";
 
 /* ── tiny keyframe injection (runs once) ── */
 const STYLES = `
@@ -55,6 +56,11 @@ export default function SignIn() {
     const [errKey, setErrKey] = useState(0);           // re-mount banner to replay anim
     const [touchedEmail, setTouchedEmail] = useState(false);
     const [touchedPassword, setTouchedPassword] = useState(false);
+    const [sso, setSSO] = useState(null);
+
+    useEffect(() => {
+        authApi.ssoConfig().then((c) => c.enabled && setSSO(c)).catch(() => {});
+    }, []);
 
     /* field-level inline validation */
     const emailInvalid = touchedEmail && !email.match(/^[^\s@]+@[^\s@]+\.[^\s@]+$/);
@@ -198,6 +204,11 @@ export default function SignIn() {
                                         <span>✕</span> At least 6 characters required
                                     </p>
                                 )}
+                                <div className="mt-1.5 text-right">
+                                    <Link to="/forgot-password" className="text-xs text-[#1e1e24]/60 hover:text-[#92140c] transition-colors" style={{ letterSpacing: "0.02em" }}>
+                                        Forgot password?
+                                    </Link>
+                                </div>
                             </div>
 
                             <button
@@ -217,6 +228,16 @@ export default function SignIn() {
                                 ) : "Sign in"}
                             </button>
 
+                            {sso && (
+                                <a
+                                    href={authApi.ssoLoginURL("/")}
+                                    className="block w-full rounded-xl border border-[#1e1e24]/10 bg-[#fff8f0] px-5 py-3 text-center text-sm font-medium text-[#1e1e24] hover:bg-[#92140c]/5 transition-colors"
+                                    style={{ letterSpacing: "0.05em" }}
+                                >
+                                    Sign in with {sso.name}
+                                </a>
+                            )}
+
                             <div className="flex items-center justify-between text-sm">
                                 <Link to="/welcome" className="text-[#1e1e24]/60 hover:text-[#92140c] transition-colors" style={{ letterSpacing: "0.02em" }}>← Back</Link>
                                 <Link to="/signup" className="font-medium text-[#92140c] hover:underline" style={{ letterSpacing: "0.02em" }}>Create account</Link>
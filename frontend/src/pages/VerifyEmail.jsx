@@ -0,0 +1,63 @@
+import React, { useEffect, useRef, useState } from "react";
+import { Link, useSearchParams } from "react-router-dom";
+import Header from "../components/Header";
+import { useAuth } from "../auth/AuthContext";
+import * as authApi from "../api/auth";
+
+// Landing page of the link in the verification email.
+export default function VerifyEmail() {
+    const [params] = useSearchParams();
+    const { isAuthed, refreshMe } = useAuth();
+    const [status, setStatus] = useState("verifying");
+    const [message, setMessage] = useState("");
+    const sent = useRef(false);
+
+    useEffect(() => {
+        // The token is single-use; don't spend it twice in StrictMode.
+        if (sent.current) return;
+        sent.current = true;
+        const token = params.get("token");
+        if (!token) {
+            setStatus("failed");
+            setMessage("This link is missing its token.");
+            return;
+        }
+        authApi
+            .verifyEmail(token)
+            .then(() => {
+                setStatus("verified");
+                if (isAuthed) refreshMe();
+            })
+            .catch((e) => {
+                setStatus("failed");
+                setMessage(e.message);
+            });
+    }, []);
+
+    return (
+        <div className="min-h-screen bg-[#f6f3f2]">
+            <Header />
+            <div className="mx-auto max-w-md px-4 py-10">
+                <div className="rounded-2xl border border-black/5 bg-white shadow-sm p-6">
+                    <h1 className="text-xl font-semibold text-black">Email verification</h1>
+                    <p className="mt-3 text-sm text-black/60">
+                        {status === "verifying" && "Checking your link…"}
+                        {status === "verified" && "Your email address is verified. Thanks!"}
+                        {status === "failed" && message}
+                    </p>
+                    {status === "failed" && isAuthed && (
+                        <p className="mt-2 text-sm text-black/60">
+                            You can send a new link from your <Link to="/profile" className="font-semibold text-[#92140c] hover:underline">profile</Link>.
+                        </p>
+                    )}
+                    <Link
+                        to={isAuthed ? "/events" : "/signin"}
+                        className="mt-6 inline-block rounded-xl border border-black/10 bg-white px-4 py-2 text-sm font-semibold text-black hover:bg-black/5"
+                    >
+                        {isAuthed ? "Browse events" : "Sign in"}
+                    </Link>
+                </div>
+            </div>
+        </div>
+    );
+}
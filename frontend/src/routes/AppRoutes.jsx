@@ -3,10 +3,17 @@ import Welcome from "../pages/Welcome";
 import Events from "../pages/Events";
 import EventDetail from "../pages/EventDetail";
 import Saved from "../pages/Saved";
+import SharedCollection from "../pages/SharedCollection";
+import ForYou from "../pages/ForYou";
 import SignIn from "../pages/SignIn";
 import SignUp from "../pages/SignUp";
 import Profile from "../pages/Profile";
 import ScraperHealth from "../pages/ScraperHealth";
+import ReviewQueue from "../pages/ReviewQueue";
+import VerifyEmail from "../pages/VerifyEmail";
+import ForgotPassword from "../pages/ForgotPassword";
+import ResetPassword from "../pages/ResetPassword";
+import AuthCallback from "../pages/AuthCallback";
 
 export default function AppRoutes() {
     return (
@@ -15,11 +22,18 @@ export default function AppRoutes() {
             <Route path="/welcome" element={<Welcome />} />
             <Route path="/events" element={<Events />} />
             <Route path="/events/:id" element={<EventDetail />} />
+            <Route path="/for-you" element={<ForYou />} />
             <Route path="/saved" element={<Saved />} />
+            <Route path="/shared/:token" element={<SharedCollection />} />
             <Route path="/signin" element={<SignIn />} />
             <Route path="/signup" element={<SignUp />} />
             <Route path="/profile" element={<Profile />} />
+            <Route path="/verify-email" element={<VerifyEmail />} />
+            <Route path="/forgot-password" element={<ForgotPassword />} />
+            <Route path="/reset-password" element={<ResetPassword />} />
+            <Route path="/auth/callback" element={<AuthCallback />} />
             <Route path="/admin/scraper-health" element={<ScraperHealth />} />
+            <Route path="/admin/reviews" element={<ReviewQueue />} />
             <Route path="*" element={<Navigate to="/welcome" replace />} />
         </Routes>
     );